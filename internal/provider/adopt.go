@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// allowAdoptionAttribute returns the allow_adoption attribute. Objects
+// created outside of Terraform, such as by a previous run whose state was
+// lost or by bootstrapping scripts, can be adopted by resources instead of
+// being imported. Creates then apply the configuration over them.
+func allowAdoptionAttribute() schema.Attribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether creating the resource adopts an object of the same name which already exists, " +
+			"applying the configuration over it and taking ownership of its fields from their other field managers, instead of failing.",
+		Optional: true,
+	}
+}
+
+// adoptionAllowed reports whether the allow_adoption attribute of the
+// resource value held by g is set.
+func adoptionAllowed(ctx context.Context, g attributeGetter) (bool, diag.Diagnostics) {
+	var allow types.Bool
+	diags := g.GetAttribute(ctx, path.Root("allow_adoption"), &allow)
+	return allow.ValueBool(), diags
+}
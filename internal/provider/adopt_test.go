@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestCreateAdoption(t *testing.T) {
+	ctx := context.Background()
+	var force string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "test", "namespace": "default", "uid": "1234"},
+			})
+		case http.MethodPatch:
+			force = r.URL.Query().Get("force")
+			_, _ = io.Copy(w, r.Body)
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}))
+	defer srv.Close()
+	clients, err := NewKubernetesClientForConfig(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+	r.clients = clients
+	r.fieldManager = fieldManager{name: defaultFieldManagerName}
+	s := testCustomResourceSchema(t, r)
+	typ := s.Type().TerraformType(ctx).(tftypes.Object)
+	mt := typ.AttributeTypes["metadata"].(tftypes.Object)
+	plan := func(adopt interface{}) tfsdk.Plan {
+		av := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+		for n, at := range typ.AttributeTypes {
+			av[n] = tftypes.NewValue(at, nil)
+		}
+		mv := make(map[string]tftypes.Value, len(mt.AttributeTypes))
+		for n, at := range mt.AttributeTypes {
+			mv[n] = tftypes.NewValue(at, nil)
+		}
+		mv["name"] = tftypes.NewValue(tftypes.String, "test")
+		mv["namespace"] = tftypes.NewValue(tftypes.String, "default")
+		av["metadata"] = tftypes.NewValue(mt, mv)
+		av["allow_adoption"] = tftypes.NewValue(tftypes.Bool, adopt)
+		return tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(typ, av)}
+	}
+	create := func(p tfsdk.Plan) *resource.CreateResponse {
+		resp := &resource.CreateResponse{State: tfsdk.State{Schema: s}}
+		r.Create(ctx, resource.CreateRequest{Plan: p, Config: tfsdk.Config(p)}, resp)
+		return resp
+	}
+
+	resp := create(plan(nil))
+	if !hasDiagnostic(resp.Diagnostics, "Resource Already Exists") || force != "" {
+		t.Errorf("expected the existing object to be left alone, got %v", resp.Diagnostics)
+	}
+
+	resp = create(plan(true))
+	if hasDiagnostic(resp.Diagnostics, "Resource Already Exists") {
+		t.Errorf("expected the existing object to be adopted, got %v", resp.Diagnostics)
+	}
+	if force != "true" {
+		t.Errorf("expected the adopted object to be applied with force, got %q", force)
+	}
+}
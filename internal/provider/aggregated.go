@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// apiServicesResource is the resource APIService objects, which register
+// aggregated API servers, are served as.
+var apiServicesResource = rtschema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+// aggregatedGroupVersions returns the group versions served by aggregated API
+// servers, selected like the versions of custom resource definitions. Those
+// served by the API server itself, including custom resources, are left out.
+// Aggregated APIs have no storage version, so versionsStorage selects the
+// version with the highest priority, as versionsLatest does.
+func aggregatedGroupVersions(apiServices []unstructured.Unstructured, selection string) []rtschema.GroupVersion {
+	type groupVersion struct {
+		version  string
+		priority int64
+	}
+	groups := make(map[string][]groupVersion)
+	for _, svc := range apiServices {
+		if _, ok, _ := unstructured.NestedMap(svc.Object, "spec", "service"); !ok {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(svc.Object, "spec", "group")
+		ver, _, _ := unstructured.NestedString(svc.Object, "spec", "version")
+		priority, _, _ := unstructured.NestedInt64(svc.Object, "spec", "versionPriority")
+		groups[group] = append(groups[group], groupVersion{ver, priority})
+	}
+
+	var gvs []rtschema.GroupVersion
+	for group, versions := range groups {
+		sort.Slice(versions, func(i, j int) bool {
+			if versions[i].priority != versions[j].priority {
+				return versions[i].priority > versions[j].priority
+			}
+			return version.CompareKubeAwareVersionStrings(versions[i].version, versions[j].version) > 0
+		})
+		if selection != versionsAll {
+			versions = versions[:1]
+		}
+		for _, v := range versions {
+			gvs = append(gvs, rtschema.GroupVersion{Group: group, Version: v.version})
+		}
+	}
+	sort.Slice(gvs, func(i, j int) bool { return gvs[i].String() < gvs[j].String() })
+	return gvs
+}
+
+// aggregatedResources returns the resources of rl which can be managed as
+// Terraform resources: top-level ones which can be created and read.
+func aggregatedResources(rl *metav1.APIResourceList) []metav1.APIResource {
+	var resources []metav1.APIResource
+	for _, r := range rl.APIResources {
+		if strings.Contains(r.Name, "/") {
+			continue
+		}
+		if !containsString(r.Verbs, "create") || !containsString(r.Verbs, "get") {
+			continue
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// aggregatedKinds returns the kinds served by aggregated API servers, and
+// why those which were left out were. Their schemas are read from the OpenAPI
+// documents of their group versions, as they have no definitions of their
+// own.
+func (p *KubernetesCRD) aggregatedKinds(ctx context.Context, clients *KubernetesClients) ([]customKind, []string) {
+	list, err := clients.Dynamic.Resource(apiServicesResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		p.discoveryDiags.AddWarning("Failed to list APIServices", fmt.Sprintf("No resources were generated for aggregated APIs: %s", err))
+		return nil, nil
+	}
+
+	var skipped []string
+	var gvks []rtschema.GroupVersionKind
+	resources := make(map[rtschema.GroupVersionKind]metav1.APIResource)
+	for _, gv := range aggregatedGroupVersions(list.Items, p.options.versions) {
+		rl, err := clients.Discovery.ServerResourcesForGroupVersion(gv.String())
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", gv, err))
+			continue
+		}
+		for _, r := range aggregatedResources(rl) {
+			if !p.options.selects(gv.Group, r.Kind) {
+				continue
+			}
+			gvk := gv.WithKind(r.Kind)
+			gvks = append(gvks, gvk)
+			resources[gvk] = r
+		}
+	}
+
+	gvs := make([]rtschema.GroupVersion, 0, len(gvks))
+	for _, gvk := range gvks {
+		gvs = append(gvs, gvk.GroupVersion())
+	}
+	docs := fetchOpenAPIDocuments(clients.Openapi, gvs)
+	var kinds []customKind
+	for _, gvk := range gvks {
+		doc := docs[gvk.GroupVersion()]
+		if doc.err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s (%s): %s", gvk.Kind, gvk.GroupVersion(), doc.err))
+			continue
+		}
+		s := p.openAPISchema(doc.spec, gvk)
+		if s == nil {
+			continue
+		}
+		r := resources[gvk]
+		singular := r.SingularName
+		if singular == "" {
+			singular = strings.ToLower(r.Kind)
+		}
+		scope := apiextv1.ClusterScoped
+		if r.Namespaced {
+			scope = apiextv1.NamespaceScoped
+		}
+		kinds = append(kinds, customKind{
+			version: gvk.Version,
+			group:   gvk.Group,
+			names: apiextv1.CustomResourceDefinitionNames{
+				Kind:       r.Kind,
+				Singular:   singular,
+				Plural:     r.Name,
+				ShortNames: r.ShortNames,
+				Categories: r.Categories,
+			},
+			scope:  scope,
+			schema: s,
+		})
+	}
+	return kinds, skipped
+}
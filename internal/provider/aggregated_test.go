@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// testAPIService returns an APIService registering version of group, served
+// by the API server itself unless aggregated.
+func testAPIService(group, version string, priority int64, aggregated bool) unstructured.Unstructured {
+	spec := map[string]interface{}{"group": group, "version": version, "versionPriority": priority}
+	if aggregated {
+		spec["service"] = map[string]interface{}{"namespace": "kube-system", "name": group}
+	}
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiregistration.k8s.io/v1",
+		"kind":       "APIService",
+		"metadata":   map[string]interface{}{"name": version + "." + group},
+		"spec":       spec,
+	}}
+}
+
+func TestAggregatedGroupVersions(t *testing.T) {
+	services := []unstructured.Unstructured{
+		testAPIService("apps", "v1", 15, false),
+		testAPIService("metrics.k8s.io", "v1beta1", 100, true),
+		testAPIService("catalog.example.com", "v1beta1", 10, true),
+		testAPIService("catalog.example.com", "v1alpha1", 20, true),
+	}
+	gvStrings := func(selection string) []string {
+		var s []string
+		for _, gv := range aggregatedGroupVersions(services, selection) {
+			s = append(s, gv.String())
+		}
+		return s
+	}
+	if got, want := gvStrings(versionsStorage), []string{"catalog.example.com/v1alpha1", "metrics.k8s.io/v1beta1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := gvStrings(versionsAll), []string{"catalog.example.com/v1alpha1", "catalog.example.com/v1beta1", "metrics.k8s.io/v1beta1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAggregatedResources(t *testing.T) {
+	rl := &metav1.APIResourceList{APIResources: []metav1.APIResource{
+		{Name: "brokers", Kind: "Broker", Verbs: []string{"create", "get", "list", "delete"}},
+		{Name: "brokers/status", Kind: "Broker", Verbs: []string{"get", "update"}},
+		{Name: "pods", Kind: "PodMetrics", Verbs: []string{"get", "list"}},
+	}}
+	rs := aggregatedResources(rl)
+	if len(rs) != 1 || rs[0].Name != "brokers" {
+		t.Errorf("expected only brokers, got %v", rs)
+	}
+}
+
+func TestResourcesForAggregatedAPIs(t *testing.T) {
+	t.Setenv("CRD_AGGREGATED_APIS", "true")
+	t.Setenv("CRD_SCHEMA_CACHE_DIR", "")
+	broker := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"type": "string"},
+			"spec": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"url": map[string]interface{}{"type": "string"}},
+			},
+		},
+		"x-kubernetes-group-version-kind": []interface{}{
+			map[string]interface{}{"group": "catalog.example.com", "version": "v1beta1", "kind": "Broker"},
+		},
+	}
+	p := New("test")().(*KubernetesCRD)
+	p.clients = testDiscoveryServer(t, map[string]interface{}{
+		crdListPath: apiextv1.CustomResourceDefinitionList{},
+		"/apis/apiregistration.k8s.io/v1/apiservices": map[string]interface{}{
+			"apiVersion": "apiregistration.k8s.io/v1",
+			"kind":       "APIServiceList",
+			"items":      []interface{}{testAPIService("catalog.example.com", "v1beta1", 10, true).Object},
+		},
+		"/apis/catalog.example.com/v1beta1": metav1.APIResourceList{
+			GroupVersion: "catalog.example.com/v1beta1",
+			APIResources: []metav1.APIResource{{Name: "brokers", SingularName: "broker", Kind: "Broker", Namespaced: true, Verbs: []string{"create", "get", "delete"}}},
+		},
+		"/openapi/v3": map[string]interface{}{
+			"paths": map[string]interface{}{
+				"apis/catalog.example.com/v1beta1": map[string]interface{}{"serverRelativeURL": "/openapi/v3/apis/catalog.example.com/v1beta1"},
+			},
+		},
+		"/openapi/v3/apis/catalog.example.com/v1beta1": map[string]interface{}{
+			"openapi":    "3.0.0",
+			"components": map[string]interface{}{"schemas": map[string]interface{}{"com.example.catalog.v1beta1.Broker": broker}},
+		},
+	})
+
+	kinds := p.discover(context.Background())
+	if len(p.discoveryDiags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", p.discoveryDiags)
+	}
+	if len(kinds) != 1 {
+		t.Fatalf("expected 1 kind, got %d", len(kinds))
+	}
+	k := kinds[0]
+	if k.names.Kind != "Broker" || k.names.Plural != "brokers" || k.scope != apiextv1.NamespaceScoped {
+		t.Errorf("unexpected kind %+v", k)
+	}
+	if _, ok := k.schema.Properties["spec"]; !ok {
+		t.Error("expected the schema to be read from the OpenAPI document")
+	}
+}
@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// defaultFieldManagerName identifies the provider as a field manager for
+// server-side apply and is recorded in the managedFields of every object it
+// writes, unless the configuration names another manager.
+const defaultFieldManagerName = "terraform-provider-crd"
+
+// FieldManagerModel describes the field_manager attribute of the provider and
+// of generated resources.
+type FieldManagerModel struct {
+	Name            types.String `tfsdk:"name"`
+	ForceConflicts  types.Bool   `tfsdk:"force_conflicts"`
+	FieldValidation types.String `tfsdk:"field_validation"`
+}
+
+// fieldValidations are the field validation directives of the API server.
+var fieldValidations = []string{metav1.FieldValidationStrict, metav1.FieldValidationWarn, metav1.FieldValidationIgnore}
+
+// fieldManager holds the server-side apply settings used to write an object.
+type fieldManager struct {
+	name  string
+	force bool
+	// validation is how the API server treats unknown and duplicate
+	// fields, or empty for its default.
+	validation string
+}
+
+// merge returns the settings of fm overridden by those set in m.
+func (fm fieldManager) merge(m *FieldManagerModel) fieldManager {
+	if m == nil {
+		return fm
+	}
+	if !m.Name.IsNull() && !m.Name.IsUnknown() {
+		fm.name = m.Name.ValueString()
+	}
+	if !m.ForceConflicts.IsNull() && !m.ForceConflicts.IsUnknown() {
+		fm.force = m.ForceConflicts.ValueBool()
+	}
+	if !m.FieldValidation.IsNull() && !m.FieldValidation.IsUnknown() {
+		fm.validation = m.FieldValidation.ValueString()
+	}
+	return fm
+}
+
+// createOptions returns the options creating objects with the settings of fm.
+func (fm fieldManager) createOptions() metav1.CreateOptions {
+	return metav1.CreateOptions{FieldManager: fm.name, FieldValidation: fm.validation}
+}
+
+// fieldValidationDescription describes the field_validation attribute.
+const fieldValidationDescription = "How the API server treats fields of objects which their schema doesn't declare, such as misspelled fields of dynamic attributes: " +
+	"`Strict` rejects the object, `Warn` reports them as warnings and `Ignore` drops them silently. Defaults to the behavior of the API server, which is `Warn` as of Kubernetes 1.27."
+
+func fieldManagerAttribute() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Server-side apply settings for this resource, overriding those of the provider configuration.",
+		Optional:            true,
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the field manager the object is applied as.",
+				Optional:            true,
+			},
+			"force_conflicts": schema.BoolAttribute{
+				MarkdownDescription: "Take ownership of fields managed by other field managers instead of failing with a conflict.",
+				Optional:            true,
+			},
+			"field_validation": schema.StringAttribute{
+				MarkdownDescription: fieldValidationDescription,
+				Optional:            true,
+				Validators:          []validator.String{stringOneOfValidator{values: fieldValidations}},
+			},
+		},
+	}
+}
+
+// fieldManagerFor returns the server-side apply settings for the resource
+// value held by g, overriding the provider's with those of its field_manager
+// attribute.
+func (r *CustomResource) fieldManagerFor(ctx context.Context, g attributeGetter) (fieldManager, diag.Diagnostics) {
+	var m *FieldManagerModel
+	diags := g.GetAttribute(ctx, path.Root("field_manager"), &m)
+	return r.fieldManager.merge(m), diags
+}
+
+// lastAppliedKey is the private state key holding the manifest the provider
+// last sent to the API server for a resource.
+const lastAppliedKey = "last_applied"
+
+// privateState is implemented by the private state data carried in resource
+// requests and responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// getLastApplied returns the last applied manifest recorded in private state,
+// or nil if none was recorded.
+func getLastApplied(ctx context.Context, p privateState) ([]byte, diag.Diagnostics) {
+	return p.GetKey(ctx, lastAppliedKey)
+}
+
+// setLastApplied records obj as the last applied manifest in private state.
+func setLastApplied(ctx context.Context, p privateState, obj map[string]interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	la, err := json.Marshal(obj)
+	if err != nil {
+		diags.AddError("Failed to record last applied configuration", err.Error())
+		return diags
+	}
+	return p.SetKey(ctx, lastAppliedKey, la)
+}
+
+// threeWayMergePatch computes a JSON merge patch which takes the live object
+// to the modified configuration. Fields present in original (the last applied
+// configuration) but dropped from modified are removed, while fields the
+// provider never set are left untouched.
+func threeWayMergePatch(original []byte, modified, current map[string]interface{}) ([]byte, error) {
+	mb, err := json.Marshal(modified)
+	if err != nil {
+		return nil, err
+	}
+	cb, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	return jsonmergepatch.CreateThreeWayJSONMergePatch(original, mb, cb)
+}
+
+// serverSideApply applies obj with the provider's field manager, as a dry run
+// if dryRun is given. Clusters which don't support server-side apply answer
+// with UnsupportedMediaType, which callers use to fall back to client-side
+// updates. The object is sent as an apply patch, as the apply options of the
+// dynamic client can't set the field validation.
+func serverSideApply(ctx context.Context, rc dynamic.ResourceInterface, obj *unstructured.Unstructured, fm fieldManager, dryRun ...string) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	return rc.Patch(ctx, obj.GetName(), apitypes.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager:    fm.name,
+		Force:           &fm.force,
+		FieldValidation: fm.validation,
+		DryRun:          dryRun,
+	})
+}
+
+// threeWayUpdate patches the live object towards obj using a JSON merge patch
+// computed against original, the last applied configuration. The patch only
+// applies to the version of the object it was computed against, so it is
+// computed again when the object changes in the meantime.
+func threeWayUpdate(ctx context.Context, rc dynamic.ResourceInterface, obj *unstructured.Unstructured, original []byte, fm fieldManager) (*unstructured.Unstructured, error) {
+	var live *unstructured.Unstructured
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cur, err := rc.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		patch, err := threeWayMergePatch(original, obj.Object, cur.Object)
+		if err != nil {
+			return err
+		}
+		patch, err = withResourceVersion(patch, cur.GetResourceVersion())
+		if err != nil {
+			return err
+		}
+		live, err = rc.Patch(ctx, obj.GetName(), apitypes.MergePatchType, patch, metav1.PatchOptions{
+			FieldManager:    fm.name,
+			FieldValidation: fm.validation,
+		})
+		return err
+	})
+	return live, err
+}
+
+// withResourceVersion returns the JSON merge patch patch, made to apply only
+// to the given version of the object.
+func withResourceVersion(patch []byte, version string) ([]byte, error) {
+	var p map[string]interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+	md, _ := p["metadata"].(map[string]interface{})
+	if md == nil {
+		md = make(map[string]interface{})
+	}
+	md["resourceVersion"] = version
+	p["metadata"] = md
+	return json.Marshal(p)
+}
+
+// applyErrorDiagnostic describes a failure to write obj, calling out field
+// ownership conflicts reported by server-side apply.
+func applyErrorDiagnostic(op string, obj *unstructured.Unstructured, err error) diag.Diagnostic {
+	if apierrors.IsConflict(err) {
+		return diag.NewErrorDiagnostic(
+			"Field Manager Conflict",
+			fmt.Sprintf("Unable to %s %s %q, got error: %s\n\n"+
+				"Other field managers own some of the fields in the configuration. "+
+				"Set force_conflicts = true in the field_manager attribute of the resource or the provider to take ownership of them.",
+				op, obj.GetKind(), obj.GetName(), err),
+		)
+	}
+	return diag.NewErrorDiagnostic(
+		"Client Error",
+		fmt.Sprintf("Unable to %s %s %q, got error: %s", op, obj.GetKind(), obj.GetName(), err),
+	)
+}
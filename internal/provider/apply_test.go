@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+func TestFieldManagerMerge(t *testing.T) {
+	base := fieldManager{name: defaultFieldManagerName}
+
+	if got := base.merge(nil); got != base {
+		t.Errorf("expected no override, got %+v", got)
+	}
+
+	got := base.merge(&FieldManagerModel{
+		Name:           types.StringNull(),
+		ForceConflicts: types.BoolValue(true),
+	})
+	if want := (fieldManager{name: defaultFieldManagerName, force: true}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	got = got.merge(&FieldManagerModel{
+		Name:            types.StringValue("argocd-controller"),
+		ForceConflicts:  types.BoolValue(false),
+		FieldValidation: types.StringValue("Strict"),
+	})
+	if want := (fieldManager{name: "argocd-controller", validation: "Strict"}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestServerSideApplyFieldValidation(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		if ct := r.Header.Get("Content-Type"); ct != string(apitypes.ApplyPatchType) {
+			t.Errorf("expected an apply patch, got %s", ct)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Warning", `299 - "unknown field \"spec.replcas\""`)
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer srv.Close()
+	cfg := &rest.Config{Host: srv.URL}
+	withWarningCollection(cfg)
+	clients, err := NewKubernetesClientForConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "test", "namespace": "default"},
+		"spec":       map[string]interface{}{"replcas": int64(2)},
+	}}
+	rc := clients.Dynamic.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}).Namespace("default")
+	ctx, warnings := withWarnings(context.Background())
+	fm := fieldManager{name: defaultFieldManagerName, force: true, validation: metav1.FieldValidationWarn}
+	if _, err := serverSideApply(ctx, rc, obj, fm, metav1.DryRunAll); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]string{"fieldManager": defaultFieldManagerName, "force": "true", "fieldValidation": "Warn", "dryRun": "All"} {
+		if query.Get(k) != want {
+			t.Errorf("expected %s=%s, got %q", k, want, query.Get(k))
+		}
+	}
+	diags := warnings.diagnostics(obj)
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), `unknown field "spec.replcas"`) {
+		t.Errorf("expected the warning of the API server, got %v", diags)
+	}
+}
+
+// testWidgetResource returns a namespaced Widget resource served by h, and
+// a function building plans for the Widget test in the default namespace.
+func testWidgetResource(t *testing.T, h http.HandlerFunc) (*CustomResource, func(adopt interface{}) tfsdk.Plan) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	clients, err := NewKubernetesClientForConfig(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+	r.clients = clients
+	r.fieldManager = fieldManager{name: defaultFieldManagerName}
+	s := testCustomResourceSchema(t, r)
+	typ := s.Type().TerraformType(context.Background()).(tftypes.Object)
+	mt := typ.AttributeTypes["metadata"].(tftypes.Object)
+	return r, func(adopt interface{}) tfsdk.Plan {
+		av := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+		for n, at := range typ.AttributeTypes {
+			av[n] = tftypes.NewValue(at, nil)
+		}
+		mv := make(map[string]tftypes.Value, len(mt.AttributeTypes))
+		for n, at := range mt.AttributeTypes {
+			mv[n] = tftypes.NewValue(at, nil)
+		}
+		mv["name"] = tftypes.NewValue(tftypes.String, "test")
+		mv["namespace"] = tftypes.NewValue(tftypes.String, "default")
+		av["metadata"] = tftypes.NewValue(mt, mv)
+		av["allow_adoption"] = tftypes.NewValue(tftypes.Bool, adopt)
+		return tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(typ, av)}
+	}
+}
+
+// writeStatus answers a request with the failure status of the API server.
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, details *metav1.StatusDetails) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Reason:   reason,
+		Code:     int32(code),
+		Message:  string(reason),
+		Details:  details,
+	})
+}
+
+func TestCreateFieldManagerConflict(t *testing.T) {
+	r, plan := testWidgetResource(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, nil)
+		case http.MethodPatch:
+			writeStatus(w, http.StatusConflict, metav1.StatusReasonConflict, &metav1.StatusDetails{
+				Causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl"`, Field: ".spec.replicas"}},
+			})
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	})
+
+	p := plan(nil)
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: p.Schema}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: p, Config: tfsdk.Config(p)}, resp)
+	if !hasDiagnostic(resp.Diagnostics, "Field Manager Conflict") {
+		t.Errorf("expected a field manager conflict, got %v", resp.Diagnostics)
+	}
+}
+
+func TestCreateMergePatchFallback(t *testing.T) {
+	var patches []string
+	var merge map[string]interface{}
+	r, plan := testWidgetResource(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "test", "namespace": "default", "resourceVersion": "42"},
+			})
+		case http.MethodPatch:
+			ct := r.Header.Get("Content-Type")
+			patches = append(patches, ct)
+			if ct == string(apitypes.ApplyPatchType) {
+				writeStatus(w, http.StatusUnsupportedMediaType, metav1.StatusReasonUnsupportedMediaType, nil)
+				return
+			}
+			_ = json.NewDecoder(r.Body).Decode(&merge)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "test", "namespace": "default", "resourceVersion": "43"},
+			})
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	})
+
+	p := plan(true)
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: p.Schema}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: p, Config: tfsdk.Config(p)}, resp)
+	if hasDiagnostic(resp.Diagnostics, "Client Error") {
+		t.Fatalf("expected the object to be updated, got %v", resp.Diagnostics)
+	}
+	want := []string{string(apitypes.ApplyPatchType), string(apitypes.MergePatchType)}
+	if strings.Join(patches, " ") != strings.Join(want, " ") {
+		t.Errorf("expected a merge patch after the apply patch was refused, got %v", patches)
+	}
+	if md, _ := merge["metadata"].(map[string]interface{}); md["resourceVersion"] != "42" {
+		t.Errorf("expected the merge patch to be made against the version read, got %v", merge)
+	}
+}
@@ -0,0 +1,473 @@
+package provider
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// celExpr is an expression of the CEL subset evaluated locally.
+type celExpr interface {
+	// eval evaluates the expression against self, the value the rule is
+	// declared on. ok is false when the expression can't be evaluated
+	// locally, such as when it reads fields which aren't set and may be
+	// defaulted by the API server.
+	eval(self interface{}) (v interface{}, ok bool)
+}
+
+type celLiteral struct{ v interface{} }
+
+func (e celLiteral) eval(self interface{}) (interface{}, bool) {
+	return e.v, true
+}
+
+type celSelf struct{}
+
+func (e celSelf) eval(self interface{}) (interface{}, bool) {
+	return self, true
+}
+
+// celSelect selects field from the object operand evaluates to.
+type celSelect struct {
+	operand celExpr
+	field   string
+}
+
+func (e celSelect) eval(self interface{}) (interface{}, bool) {
+	o, ok := e.operand.eval(self)
+	if !ok {
+		return nil, false
+	}
+	m, ok := o.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	v, ok := m[e.field]
+	return v, ok && v != nil
+}
+
+// celHas tests whether a field is set. Fields which aren't may still be
+// defaulted by the API server, so only set ones can be told apart.
+type celHas struct{ sel celSelect }
+
+func (e celHas) eval(self interface{}) (interface{}, bool) {
+	if _, ok := e.sel.eval(self); ok {
+		return true, true
+	}
+	return nil, false
+}
+
+type celSize struct{ operand celExpr }
+
+func (e celSize) eval(self interface{}) (interface{}, bool) {
+	o, ok := e.operand.eval(self)
+	if !ok {
+		return nil, false
+	}
+	switch tv := o.(type) {
+	case string:
+		return int64(utf8.RuneCountInString(tv)), true
+	case []interface{}:
+		return int64(len(tv)), true
+	case map[string]interface{}:
+		return int64(len(tv)), true
+	}
+	return nil, false
+}
+
+type celNot struct{ operand celExpr }
+
+func (e celNot) eval(self interface{}) (interface{}, bool) {
+	o, ok := e.operand.eval(self)
+	b, isBool := o.(bool)
+	if !ok || !isBool {
+		return nil, false
+	}
+	return !b, true
+}
+
+type celNeg struct{ operand celExpr }
+
+func (e celNeg) eval(self interface{}) (interface{}, bool) {
+	o, ok := e.operand.eval(self)
+	if !ok {
+		return nil, false
+	}
+	switch tv := o.(type) {
+	case int64:
+		return -tv, true
+	case float64:
+		return -tv, true
+	}
+	return nil, false
+}
+
+// celLogical is a conjunction or disjunction. As in CEL, either operand
+// decides the result on its own, whether or not the other can be evaluated.
+type celLogical struct {
+	and  bool
+	l, r celExpr
+}
+
+func (e celLogical) eval(self interface{}) (interface{}, bool) {
+	lv, lok := e.l.eval(self)
+	rv, rok := e.r.eval(self)
+	lb, lbool := lv.(bool)
+	rb, rbool := rv.(bool)
+	lok, rok = lok && lbool, rok && rbool
+	// false decides conjunctions, and true disjunctions.
+	decisive := !e.and
+	if (lok && lb == decisive) || (rok && rb == decisive) {
+		return decisive, true
+	}
+	if lok && rok {
+		return !decisive, true
+	}
+	return nil, false
+}
+
+type celCompare struct {
+	op   string
+	l, r celExpr
+}
+
+func (e celCompare) eval(self interface{}) (interface{}, bool) {
+	lv, ok := e.l.eval(self)
+	if !ok {
+		return nil, false
+	}
+	rv, ok := e.r.eval(self)
+	if !ok {
+		return nil, false
+	}
+	switch e.op {
+	case "==":
+		return celEqual(lv, rv), true
+	case "!=":
+		return !celEqual(lv, rv), true
+	}
+	c, ok := celOrder(lv, rv)
+	if !ok {
+		return nil, false
+	}
+	switch e.op {
+	case "<":
+		return c < 0, true
+	case "<=":
+		return c <= 0, true
+	case ">":
+		return c > 0, true
+	default:
+		return c >= 0, true
+	}
+}
+
+// celNumber returns v as a number, if it is one.
+func celNumber(v interface{}) (*big.Float, bool) {
+	switch tv := v.(type) {
+	case int64:
+		return new(big.Float).SetInt64(tv), true
+	case int:
+		return new(big.Float).SetInt64(int64(tv)), true
+	case float64:
+		return big.NewFloat(tv), true
+	}
+	return nil, false
+}
+
+// celEqual reports whether a and b are equal. Numbers are compared by value,
+// whatever their types, as Kubernetes does.
+func celEqual(a, b interface{}) bool {
+	if an, ok := celNumber(a); ok {
+		bn, ok := celNumber(b)
+		return ok && an.Cmp(bn) == 0
+	}
+	switch ta := a.(type) {
+	case []interface{}:
+		tb, ok := b.([]interface{})
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for i := range ta {
+			if !celEqual(ta[i], tb[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		tb, ok := b.(map[string]interface{})
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for k, e := range ta {
+			if f, ok := tb[k]; !ok || !celEqual(e, f) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// celOrder compares a and b, which must both be numbers or both be strings.
+func celOrder(a, b interface{}) (int, bool) {
+	if an, ok := celNumber(a); ok {
+		bn, ok := celNumber(b)
+		if !ok {
+			return 0, false
+		}
+		return an.Cmp(bn), true
+	}
+	as, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	bs, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(as, bs), true
+}
+
+// parseCELRule parses rule, reporting whether it belongs to the subset of CEL
+// evaluated locally: field selections on self, literals, comparisons, the
+// logical operators, and the has and size functions. This covers rules such
+// as self.replicas <= self.maxReplicas, so plans report the objects breaking
+// them even without a server-side dry run. Rules using anything else, such as
+// transition rules reading oldSelf, macros or arithmetic, are left to the API
+// server.
+func parseCELRule(rule string) (celExpr, bool) {
+	tokens, ok := celTokens(rule)
+	if !ok {
+		return nil, false
+	}
+	p := &celParser{tokens: tokens}
+	e, ok := p.or()
+	if !ok || p.pos != len(p.tokens) {
+		return nil, false
+	}
+	return e, true
+}
+
+// celToken is a token of a rule. Literals hold their values, and identifiers
+// and operators are held as text.
+type celToken struct {
+	text    string
+	literal interface{}
+	isLit   bool
+}
+
+var celOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "-", "(", ")", "."}
+
+// celTokens splits rule into tokens, reporting whether it only holds tokens
+// of the subset of CEL evaluated locally.
+func celTokens(rule string) ([]celToken, bool) {
+	var tokens []celToken
+	for i := 0; i < len(rule); {
+		c := rule[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(rule) && (rule[j] == '_' || rule[j] >= 'a' && rule[j] <= 'z' || rule[j] >= 'A' && rule[j] <= 'Z' || rule[j] >= '0' && rule[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, celToken{text: rule[i:j]})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(rule) && (rule[j] >= '0' && rule[j] <= '9' || rule[j] == '.' || rule[j] == 'e' || rule[j] == 'E' ||
+				(rule[j] == '+' || rule[j] == '-') && (rule[j-1] == 'e' || rule[j-1] == 'E')) {
+				j++
+			}
+			text := rule[i:j]
+			if iv, err := strconv.ParseInt(text, 10, 64); err == nil {
+				tokens = append(tokens, celToken{text: text, literal: iv, isLit: true})
+			} else if fv, err := strconv.ParseFloat(text, 64); err == nil && strings.ContainsAny(text, ".eE") {
+				tokens = append(tokens, celToken{text: text, literal: fv, isLit: true})
+			} else {
+				return nil, false
+			}
+			i = j
+		case c == '"' || c == '\'':
+			s, n, ok := celString(rule[i:])
+			if !ok {
+				return nil, false
+			}
+			tokens = append(tokens, celToken{text: rule[i : i+n], literal: s, isLit: true})
+			i += n
+		default:
+			op := ""
+			for _, o := range celOperators {
+				if strings.HasPrefix(rule[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, false
+			}
+			tokens = append(tokens, celToken{text: op})
+			i += len(op)
+		}
+	}
+	return tokens, true
+}
+
+// celString reads the quoted string literal s starts with, returning its
+// value and length. Triple-quoted strings and escapes other than those of
+// quotes, backslashes, tabs and newlines aren't supported.
+func celString(s string) (string, int, bool) {
+	q := s[0]
+	if strings.HasPrefix(s, strings.Repeat(string(q), 3)) {
+		return "", 0, false
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == q:
+			return b.String(), i + 1, true
+		case c == '\n':
+			return "", 0, false
+		case c == '\\':
+			if i+1 == len(s) {
+				return "", 0, false
+			}
+			i++
+			switch s[i] {
+			case '\\', '"', '\'':
+				b.WriteByte(s[i])
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				return "", 0, false
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, false
+}
+
+// celParser parses tokens by recursive descent, following the precedence of
+// CEL operators.
+type celParser struct {
+	tokens []celToken
+	pos    int
+}
+
+// accept consumes the next token if it is the identifier or operator text.
+func (p *celParser) accept(text string) bool {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].isLit && p.tokens[p.pos].text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *celParser) or() (celExpr, bool) {
+	e, ok := p.and()
+	for ok && p.accept("||") {
+		var r celExpr
+		r, ok = p.and()
+		e = celLogical{l: e, r: r}
+	}
+	return e, ok
+}
+
+func (p *celParser) and() (celExpr, bool) {
+	e, ok := p.relation()
+	for ok && p.accept("&&") {
+		var r celExpr
+		r, ok = p.relation()
+		e = celLogical{and: true, l: e, r: r}
+	}
+	return e, ok
+}
+
+func (p *celParser) relation() (celExpr, bool) {
+	e, ok := p.unary()
+	if !ok {
+		return nil, false
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			r, ok := p.unary()
+			return celCompare{op: op, l: e, r: r}, ok
+		}
+	}
+	return e, true
+}
+
+func (p *celParser) unary() (celExpr, bool) {
+	switch {
+	case p.accept("!"):
+		e, ok := p.unary()
+		return celNot{operand: e}, ok
+	case p.accept("-"):
+		e, ok := p.unary()
+		return celNeg{operand: e}, ok
+	}
+	return p.member()
+}
+
+func (p *celParser) member() (celExpr, bool) {
+	e, ok := p.primary()
+	for ok && p.accept(".") {
+		if p.pos == len(p.tokens) || p.tokens[p.pos].isLit {
+			return nil, false
+		}
+		field := p.tokens[p.pos].text
+		p.pos++
+		if p.accept("(") {
+			if field != "size" || !p.accept(")") {
+				return nil, false
+			}
+			e = celSize{operand: e}
+			continue
+		}
+		e = celSelect{operand: e, field: field}
+	}
+	return e, ok
+}
+
+func (p *celParser) primary() (celExpr, bool) {
+	if p.pos == len(p.tokens) {
+		return nil, false
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	if t.isLit {
+		return celLiteral{v: t.literal}, true
+	}
+	switch t.text {
+	case "self":
+		return celSelf{}, true
+	case "true", "false":
+		return celLiteral{v: t.text == "true"}, true
+	case "null":
+		return celLiteral{}, true
+	case "(":
+		e, ok := p.or()
+		return e, ok && p.accept(")")
+	case "has":
+		if !p.accept("(") {
+			return nil, false
+		}
+		e, ok := p.member()
+		sel, isSelect := e.(celSelect)
+		return celHas{sel: sel}, ok && isSelect && p.accept(")")
+	case "size":
+		if !p.accept("(") {
+			return nil, false
+		}
+		e, ok := p.or()
+		return celSize{operand: e}, ok && p.accept(")")
+	}
+	return nil, false
+}
@@ -2,10 +2,15 @@ package provider
 
 import (
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/cached"
 	"k8s.io/client-go/openapi3"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -13,23 +18,57 @@ type KubernetesClients struct {
 	Config        *rest.Config
 	Discovery     *discovery.DiscoveryClient
 	APIextensions *apiextensionsclientset.Clientset
+	Dynamic       dynamic.Interface
 	Openapi       openapi3.Root
+	// Mapper maps kinds to resources. It is shared by all resources and
+	// data sources, and safe for concurrent use, so that the discovery
+	// documents it reads are only fetched once, and again when a kind
+	// can't be found in them.
+	Mapper meta.ResettableRESTMapper
+
+	// reads shares the results of reads between resources and data sources.
+	reads *readCache
 }
 
-func NewKubernetesClient() *KubernetesClients {
+// NewKubernetesClient creates the set of clients used by the provider from the
+// kubeconfig found with the standard loading rules.
+func NewKubernetesClient() (*KubernetesClients, error) {
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
 	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, nil)
 	clientConfig, err := cc.ClientConfig()
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	withRetries(clientConfig, defaultMaxRetries)
+	return NewKubernetesClientForConfig(clientConfig)
+}
+
+// NewKubernetesClientForConfig creates the set of clients used by the provider
+// from a REST client configuration.
+func NewKubernetesClientForConfig(clientConfig *rest.Config) (*KubernetesClients, error) {
+	disClient, err := discovery.NewDiscoveryClientForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	// The cached client fetches the root OpenAPI document once rather than
+	// for every group version.
+	oapi := openapi3.NewRoot(cached.NewClient(openapi.NewClient(disClient.RESTClient())))
+	apiext, err := apiextensionsclientset.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	dyn, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
 	}
-	disClient := discovery.NewDiscoveryClientForConfigOrDie(clientConfig)
-	oapi := openapi3.NewRoot(openapi.NewClient(disClient.RESTClient()))
 
 	return &KubernetesClients{
 		Config:        clientConfig,
 		Discovery:     disClient,
-		APIextensions: apiextensionsclientset.NewForConfigOrDie(clientConfig),
+		APIextensions: apiext,
+		Dynamic:       dyn,
 		Openapi:       oapi,
-	}
+		Mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disClient)),
+		reads:         newReadCache(cacheForever),
+	}, nil
 }
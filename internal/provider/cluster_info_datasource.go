@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+var _ datasource.DataSource = &ClusterInfoDataSource{}
+var _ datasource.DataSourceWithConfigure = &ClusterInfoDataSource{}
+
+func NewClusterInfoDataSource() datasource.DataSource {
+	return &ClusterInfoDataSource{}
+}
+
+// ClusterInfoDataSource describes the capabilities of the cluster: its
+// version, the API versions it serves and whether given kinds exist.
+type ClusterInfoDataSource struct {
+	clients *KubernetesClients
+}
+
+type clusterInfoDataSourceModel struct {
+	Kinds          []string        `tfsdk:"kinds"`
+	ServerVersion  types.String    `tfsdk:"server_version"`
+	Major          types.String    `tfsdk:"major"`
+	Minor          types.String    `tfsdk:"minor"`
+	APIVersions    []string        `tfsdk:"api_versions"`
+	AvailableKinds map[string]bool `tfsdk:"available_kinds"`
+}
+
+func (d *ClusterInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_info"
+}
+
+func (d *ClusterInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Describes the capabilities of the cluster, so that configurations can depend on its version and on the APIs it serves.",
+		Attributes: map[string]schema.Attribute{
+			"kinds": schema.ListAttribute{
+				MarkdownDescription: "Kinds to look up, as their API version and kind joined with a slash, e.g. `cert-manager.io/v1/ClusterIssuer` or `v1/ConfigMap`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"server_version": schema.StringAttribute{
+				MarkdownDescription: "Version of the API server, e.g. `v1.32.3`.",
+				Computed:            true,
+			},
+			"major": schema.StringAttribute{
+				MarkdownDescription: "Major version of the API server.",
+				Computed:            true,
+			},
+			"minor": schema.StringAttribute{
+				MarkdownDescription: "Minor version of the API server. Some distributions append a `+` to it.",
+				Computed:            true,
+			},
+			"api_versions": schema.ListAttribute{
+				MarkdownDescription: "API versions served by the cluster, e.g. `apps/v1`, in lexical order.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"available_kinds": schema.MapAttribute{
+				MarkdownDescription: "Whether each of `kinds` is served by the cluster.",
+				Computed:            true,
+				ElementType:         types.BoolType,
+			},
+		},
+	}
+}
+
+func (d *ClusterInfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.clients = pd.Clients
+}
+
+func (d *ClusterInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data clusterInfoDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dc := d.clients.Discovery
+	read, err := d.clients.reads.get("version", func() (interface{}, error) { return dc.ServerVersion() })
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the server version, got error: %s", err))
+		return
+	}
+	v := read.(*version.Info)
+	data.ServerVersion = types.StringValue(v.GitVersion)
+	data.Major = types.StringValue(v.Major)
+	data.Minor = types.StringValue(v.Minor)
+
+	read, err = d.clients.reads.get("groups", func() (interface{}, error) { return dc.ServerGroups() })
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list API groups, got error: %s", err))
+		return
+	}
+	data.APIVersions = apiVersions(read.(*metav1.APIGroupList))
+
+	data.AvailableKinds = make(map[string]bool, len(data.Kinds))
+	for i, k := range data.Kinds {
+		gvk, err := parseKind(k)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("kinds").AtListIndex(i), "Invalid Kind", err.Error())
+			continue
+		}
+		_, err = d.clients.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil && !meta.IsNoMatchError(err) {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to look up the resource of %s, got error: %s", k, err))
+			return
+		}
+		data.AvailableKinds[k] = err == nil
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// apiVersions returns the group versions served by the cluster, in lexical
+// order.
+func apiVersions(groups *metav1.APIGroupList) []string {
+	versions := []string{}
+	for _, g := range groups.Groups {
+		for _, v := range g.Versions {
+			versions = append(versions, v.GroupVersion)
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// parseKind parses a kind given as its API version and kind joined with a
+// slash, such as apps/v1/Deployment.
+func parseKind(k string) (rtschema.GroupVersionKind, error) {
+	i := strings.LastIndex(k, "/")
+	if i <= 0 || i == len(k)-1 {
+		return rtschema.GroupVersionKind{}, fmt.Errorf("expected an API version and a kind joined with a slash, e.g. apps/v1/Deployment, got %q", k)
+	}
+	gv, err := rtschema.ParseGroupVersion(k[:i])
+	if err != nil {
+		return rtschema.GroupVersionKind{}, fmt.Errorf("invalid API version in %q: %w", k, err)
+	}
+	return gv.WithKind(k[i+1:]), nil
+}
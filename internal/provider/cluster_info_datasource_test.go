@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseKind(t *testing.T) {
+	cases := map[string]rtschema.GroupVersionKind{
+		"cert-manager.io/v1/ClusterIssuer": {Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer"},
+		"v1/ConfigMap":                     {Version: "v1", Kind: "ConfigMap"},
+	}
+	for k, want := range cases {
+		got, err := parseKind(k)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", k, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %v, got %v", k, want, got)
+		}
+	}
+	for _, k := range []string{"ConfigMap", "v1/", "/ConfigMap", "a/b/c/Kind"} {
+		if _, err := parseKind(k); err == nil {
+			t.Errorf("%s: expected an error", k)
+		}
+	}
+}
+
+func TestAPIVersions(t *testing.T) {
+	groups := &metav1.APIGroupList{Groups: []metav1.APIGroup{
+		{Name: "example.com", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "example.com/v1"}, {GroupVersion: "example.com/v1beta1"}}},
+		{Name: "", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "v1"}}},
+		{Name: "apps", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "apps/v1"}}},
+	}}
+	want := []string{"apps/v1", "example.com/v1", "example.com/v1beta1", "v1"}
+	if got := apiVersions(groups); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// flattenAllOf returns a copy of s with its allOf sub-schemas merged into it.
+// controller-gen and the API server use allOf to layer validation onto shared
+// definitions, so the sub-schemas describe the same value rather than
+// alternatives.
+func flattenAllOf(s *spec.Schema) *spec.Schema {
+	fs := *s
+	fs.AllOf = nil
+	for _, sub := range s.AllOf {
+		mergeSchema(&fs, *flattenAllOf(&sub))
+	}
+	return &fs
+}
+
+// mergeSchema adds the keywords of src to dst. Keywords dst already sets are
+// kept, except for properties and required fields, which are combined.
+func mergeSchema(dst *spec.Schema, src spec.Schema) {
+	if len(dst.Type) == 0 {
+		dst.Type = src.Type
+	}
+	if dst.Format == "" {
+		dst.Format = src.Format
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if dst.Default == nil {
+		dst.Default = src.Default
+	}
+	if len(dst.Enum) == 0 {
+		dst.Enum = src.Enum
+	}
+	if dst.Pattern == "" {
+		dst.Pattern = src.Pattern
+	}
+	if dst.Minimum == nil {
+		dst.Minimum, dst.ExclusiveMinimum = src.Minimum, src.ExclusiveMinimum
+	}
+	if dst.Maximum == nil {
+		dst.Maximum, dst.ExclusiveMaximum = src.Maximum, src.ExclusiveMaximum
+	}
+	if dst.MultipleOf == nil {
+		dst.MultipleOf = src.MultipleOf
+	}
+	if dst.MinLength == nil {
+		dst.MinLength = src.MinLength
+	}
+	if dst.MaxLength == nil {
+		dst.MaxLength = src.MaxLength
+	}
+	if dst.MinItems == nil {
+		dst.MinItems = src.MinItems
+	}
+	if dst.MaxItems == nil {
+		dst.MaxItems = src.MaxItems
+	}
+	dst.UniqueItems = dst.UniqueItems || src.UniqueItems
+	if dst.Items == nil {
+		dst.Items = src.Items
+	}
+	if dst.AdditionalProperties == nil {
+		dst.AdditionalProperties = src.AdditionalProperties
+	}
+	if len(src.Properties) > 0 {
+		props := make(map[string]spec.Schema, len(dst.Properties)+len(src.Properties))
+		for k, p := range dst.Properties {
+			props[k] = p
+		}
+		for k, p := range src.Properties {
+			if dp, ok := props[k]; ok {
+				mergeSchema(&dp, p)
+				p = dp
+			}
+			props[k] = p
+		}
+		dst.Properties = props
+	}
+	for _, r := range src.Required {
+		if !containsString(dst.Required, r) {
+			dst.Required = append(dst.Required, r)
+		}
+	}
+	if len(src.Extensions) > 0 {
+		ext := make(spec.Extensions, len(dst.Extensions)+len(src.Extensions))
+		for k, v := range src.Extensions {
+			ext[k] = v
+		}
+		for k, v := range dst.Extensions {
+			ext[k] = v
+		}
+		dst.Extensions = ext
+	}
+}
+
+// isAlternatives reports whether s only describes its value through oneOf or
+// anyOf alternatives, without declaring a type of its own.
+func isAlternatives(s *spec.Schema) bool {
+	return len(s.Type) == 0 && len(s.Properties) == 0 && (len(s.OneOf) > 0 || len(s.AnyOf) > 0)
+}
+
+func containsString(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestAttributeFromOAPIAllOf(t *testing.T) {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Description: "Pod template.",
+		AllOf: []spec.Schema{
+			{SchemaProps: spec.SchemaProps{
+				Type:       []string{"object"},
+				Properties: map[string]spec.Schema{"serviceAccountName": *spec.StringProperty()},
+			}},
+			{SchemaProps: spec.SchemaProps{
+				Required:   []string{"nodeName"},
+				Properties: map[string]spec.Schema{"nodeName": *spec.StringProperty()},
+			}},
+		},
+	}}
+	a, diags := attributeFromOAPI(s, path.Root("spec").AtName("template"), optionalAttribute)
+	if diags.HasError() || diags.WarningsCount() != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	sa, ok := a.(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a single nested attribute, got %T", a)
+	}
+	if !sa.Attributes["node_name"].IsRequired() || !sa.Attributes["service_account_name"].IsOptional() {
+		t.Errorf("unexpected attributes: %#v", sa.Attributes)
+	}
+
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"service_account_name": tftypes.String,
+		"node_name":            tftypes.String,
+	}}
+	obj := map[string]interface{}{"serviceAccountName": "builder", "nodeName": "worker-1"}
+	v, err := valueFromObject(s, typ, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := objectFromValue(s, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, obj) {
+		t.Errorf("round trip mismatch:\n got: %#v\nwant: %#v", got, obj)
+	}
+}
+
+func TestAttributeFromOAPIOneOf(t *testing.T) {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		OneOf: []spec.Schema{*spec.StringProperty(), *spec.BooleanProperty()},
+	}}
+	a, diags := attributeFromOAPI(s, path.Root("spec").AtName("mode"), optionalAttribute)
+	if _, ok := a.(schema.DynamicAttribute); !ok {
+		t.Errorf("expected a dynamic attribute, got %T", a)
+	}
+	if diags.WarningsCount() != 1 {
+		t.Errorf("expected a warning, got %v", diags)
+	}
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// withComputedPaths makes the attributes of attrs, held by the attribute at
+// p, computed if their paths are listed in paths. attrs is modified, along
+// with the attribute maps nested in it. Computed attributes hold fields the
+// API server or its webhooks may set or change, such as defaults injected by
+// admission webhooks. When left out of the configuration, they are planned as
+// unknown, and take the values of the object the API server returns.
+func withComputedPaths(attrs map[string]schema.Attribute, p path.Path, paths map[string]bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(paths) == 0 {
+		return diags
+	}
+	for n, a := range attrs {
+		ap := p.AtName(n)
+		if paths[ap.String()] {
+			switch {
+			case a.IsRequired():
+				diags.AddWarning("Invalid Computed Attribute",
+					fmt.Sprintf("Attribute %s is required and can't be computed.", ap))
+				continue
+			case a.IsWriteOnly():
+				diags.AddWarning("Invalid Computed Attribute",
+					fmt.Sprintf("Attribute %s is write-only and can't be computed.", ap))
+				continue
+			}
+			attrs[n] = withComputed(a)
+			continue
+		}
+		switch a := a.(type) {
+		case schema.SingleNestedAttribute:
+			diags.Append(withComputedPaths(a.Attributes, ap, paths)...)
+		case schema.ListNestedAttribute:
+			diags.Append(withComputedPaths(a.NestedObject.Attributes, ap, paths)...)
+		case schema.SetNestedAttribute:
+			diags.Append(withComputedPaths(a.NestedObject.Attributes, ap, paths)...)
+		case schema.MapNestedAttribute:
+			diags.Append(withComputedPaths(a.NestedObject.Attributes, ap, paths)...)
+		}
+	}
+	return diags
+}
+
+// withComputed returns a made computed. Only optional attributes are passed
+// in, so a remains optional.
+func withComputed(a schema.Attribute) schema.Attribute {
+	switch a := a.(type) {
+	case schema.StringAttribute:
+		a.Computed = true
+		return a
+	case schema.BoolAttribute:
+		a.Computed = true
+		return a
+	case schema.Int32Attribute:
+		a.Computed = true
+		return a
+	case schema.Int64Attribute:
+		a.Computed = true
+		return a
+	case schema.Float32Attribute:
+		a.Computed = true
+		return a
+	case schema.Float64Attribute:
+		a.Computed = true
+		return a
+	case schema.NumberAttribute:
+		a.Computed = true
+		return a
+	case schema.DynamicAttribute:
+		a.Computed = true
+		return a
+	case schema.ListAttribute:
+		a.Computed = true
+		return a
+	case schema.SetAttribute:
+		a.Computed = true
+		return a
+	case schema.MapAttribute:
+		a.Computed = true
+		return a
+	case schema.SingleNestedAttribute:
+		a.Computed = true
+		return a
+	case schema.ListNestedAttribute:
+		a.Computed = true
+		return a
+	case schema.SetNestedAttribute:
+		a.Computed = true
+		return a
+	case schema.MapNestedAttribute:
+		a.Computed = true
+		return a
+	}
+	return a
+}
+
+// withLiveValues returns v, a planned resource value, with its unknown values
+// taken from live, or set to null if live has none.
+func (r *CustomResource) withLiveValues(v tftypes.Value, live *unstructured.Unstructured) (tftypes.Value, error) {
+	if v.IsFullyKnown() {
+		return v, nil
+	}
+	lv, err := valueFromObject(r.schema, v.Type(), live.Object)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.Transform(v, func(p *tftypes.AttributePath, e tftypes.Value) (tftypes.Value, error) {
+		if e.IsKnown() {
+			return e, nil
+		}
+		le, _, err := tftypes.WalkAttributePath(lv, p)
+		if err != nil {
+			return tftypes.NewValue(e.Type(), nil), nil
+		}
+		if le, ok := le.(tftypes.Value); ok {
+			return le, nil
+		}
+		return tftypes.NewValue(e.Type(), nil), nil
+	})
+}
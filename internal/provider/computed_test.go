@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCustomResourceComputedPaths(t *testing.T) {
+	t.Setenv("CRD_COMPUTED_ATTRIBUTES", "spec.image,spec.replicas")
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	opts, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), opts)
+	rs := testCustomResourceSchema(t, r)
+	attrs := rs.Attributes["spec"].(schema.SingleNestedAttribute).Attributes
+	if !attrs["image"].IsOptional() || !attrs["image"].IsComputed() {
+		t.Errorf("expected image to be optional and computed, got %+v", attrs["image"])
+	}
+	if attrs["replicas"].IsComputed() {
+		t.Error("expected required attributes not to be computed")
+	}
+
+	st := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"image":    tftypes.String,
+		"replicas": tftypes.Number,
+	}}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"spec": st}}
+	value := func(image interface{}) tftypes.Value {
+		return tftypes.NewValue(typ, map[string]tftypes.Value{
+			"spec": tftypes.NewValue(st, map[string]tftypes.Value{
+				"image":    tftypes.NewValue(tftypes.String, image),
+				"replicas": tftypes.NewValue(tftypes.Number, 1),
+			}),
+		})
+	}
+	cr := r.(*CustomResource)
+	known, err := cr.manifestKnown(value(tftypes.UnknownValue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !known {
+		t.Error("expected unknown computed attributes not to make the manifest unknown")
+	}
+
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"image": "nginx:1.27", "replicas": int64(1)},
+	}}
+	v, err := cr.withLiveValues(value(tftypes.UnknownValue), live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Equal(value("nginx:1.27")) {
+		t.Errorf("expected the image set by the API server, got %s", v)
+	}
+	delete(live.Object["spec"].(map[string]interface{}), "image")
+	v, err = cr.withLiveValues(value(tftypes.UnknownValue), live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Equal(value(nil)) {
+		t.Errorf("expected a null image, got %s", v)
+	}
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ function.Function = &ExtractConditionFunction{}
+
+// conditionAttrTypes are the attributes of the objects returned by
+// extract_condition.
+var conditionAttrTypes = map[string]attr.Type{
+	"type":                 types.StringType,
+	"status":               types.StringType,
+	"reason":               types.StringType,
+	"message":              types.StringType,
+	"last_transition_time": types.StringType,
+}
+
+func NewExtractConditionFunction() function.Function {
+	return &ExtractConditionFunction{}
+}
+
+// ExtractConditionFunction looks up a status condition of an object.
+type ExtractConditionFunction struct{}
+
+func (f *ExtractConditionFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "extract_condition"
+}
+
+func (f *ExtractConditionFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Extract a status condition",
+		MarkdownDescription: "Looks up the condition of a given type in `status.conditions` of an object, such as a custom resource read by a data source or a decoded manifest. Returns an object with its `type`, `status`, `reason`, `message` and `last_transition_time`, or null if the object has no such condition.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:                "object",
+				MarkdownDescription: "Object holding the conditions.",
+			},
+			function.StringParameter{
+				Name:                "type",
+				MarkdownDescription: "Type of the condition, e.g. `Ready`.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: conditionAttrTypes,
+		},
+	}
+}
+
+func (f *ExtractConditionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var arg types.Dynamic
+	var ct string
+	resp.Error = req.Arguments.Get(ctx, &arg, &ct)
+	if resp.Error != nil {
+		return
+	}
+	v, err := arg.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	o, err := objectFromValue(nil, v)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	obj, ok := o.(map[string]interface{})
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(0, "expected an object")
+		return
+	}
+	cond := findCondition(obj, ct)
+	if cond == nil {
+		resp.Error = resp.Result.Set(ctx, types.ObjectNull(conditionAttrTypes))
+		return
+	}
+	// Objects read by data sources carry snake_case attribute names.
+	field := func(names ...string) attr.Value {
+		for _, n := range names {
+			if s, ok := cond[n].(string); ok {
+				return types.StringValue(s)
+			}
+		}
+		return types.StringNull()
+	}
+	resp.Error = resp.Result.Set(ctx, types.ObjectValueMust(conditionAttrTypes, map[string]attr.Value{
+		"type":                 types.StringValue(ct),
+		"status":               field("status"),
+		"reason":               field("reason"),
+		"message":              field("message"),
+		"last_transition_time": field("lastTransitionTime", "last_transition_time"),
+	}))
+}
+
+// findCondition returns the condition of type ct in the status of obj, or nil
+// if there is none.
+func findCondition(obj map[string]interface{}, ct string) map[string]interface{} {
+	conds, _, err := unstructured.NestedSlice(obj, "status", "conditions")
+	if err != nil {
+		return nil
+	}
+	for _, c := range conds {
+		cm, ok := c.(map[string]interface{})
+		if ok && cm["type"] == ct {
+			return cm
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestExtractConditionFunction(t *testing.T) {
+	obj, ferr := runFunction(t, NewManifestDecodeFunction(), types.StringValue(`
+kind: Widget
+status:
+  conditions:
+  - type: Synced
+    status: "True"
+  - type: Ready
+    status: "False"
+    reason: Reconciling
+    lastTransitionTime: "2024-01-02T03:04:05Z"
+`))
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	arg, err := types.DynamicType.ValueFromTerraform(context.Background(), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, ferr := runFunction(t, NewExtractConditionFunction(), arg, types.StringValue("Ready"))
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	var cond map[string]tftypes.Value
+	if err := v.As(&cond); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"type": "Ready", "status": "False", "reason": "Reconciling", "last_transition_time": "2024-01-02T03:04:05Z"}
+	for k, w := range want {
+		var got string
+		if err := cond[k].As(&got); err != nil || got != w {
+			t.Errorf("expected %s %q, got %q (err: %v)", k, w, got, err)
+		}
+	}
+	if !cond["message"].IsNull() {
+		t.Errorf("expected a null message, got %s", cond["message"])
+	}
+
+	v, ferr = runFunction(t, NewExtractConditionFunction(), arg, types.StringValue("Degraded"))
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	if !v.IsNull() {
+		t.Errorf("expected null for a missing condition, got %s", v)
+	}
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/homedir"
+)
+
+// clientConfig builds the REST client configuration described by the provider
+// configuration. Explicit attributes override the matching settings of the
+// kubeconfig file, which is looked up with the standard loading rules unless
+// a path or the raw contents of a kubeconfig are given. When no kubeconfig
+// can be found, the loading rules fall back to the in-cluster configuration.
+func clientConfig(ctx context.Context, data KubernetesCRDModel) (*rest.Config, error) {
+	data, err := withEnvDefaults(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loadClientConfig(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if !data.QPS.IsNull() {
+		cfg.QPS = float32(data.QPS.ValueFloat64())
+	}
+	if !data.Burst.IsNull() {
+		cfg.Burst = int(data.Burst.ValueInt64())
+	}
+	if !data.RequestTimeout.IsNull() {
+		cfg.Timeout, err = time.ParseDuration(data.RequestTimeout.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("invalid request_timeout: %w", err)
+		}
+	}
+	// Retries wrap the limit, so that requests waiting to be sent again
+	// don't hold on to it.
+	if !data.Parallelism.IsNull() {
+		withParallelism(cfg, int(data.Parallelism.ValueInt64()))
+	}
+	maxRetries := defaultMaxRetries
+	if !data.MaxRetries.IsNull() {
+		maxRetries = int(data.MaxRetries.ValueInt64())
+	}
+	withRetries(cfg, maxRetries)
+	withWarningCollection(cfg)
+	return cfg, nil
+}
+
+// loadClientConfig loads the kubeconfig and applies the connection and
+// authentication attributes on top of it.
+func loadClientConfig(ctx context.Context, data KubernetesCRDModel) (*rest.Config, error) {
+	if data.InCluster.ValueBool() {
+		cfg, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("in_cluster is set but the in-cluster configuration is unavailable: %w", err)
+		}
+		return cfg, nil
+	}
+	sources := 0
+	for _, v := range []attr.Value{data.Kubeconfig, data.ConfigPaths, data.KubeconfigRaw} {
+		if !v.IsNull() {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, fmt.Errorf("only one of kubeconfig, config_paths and kubeconfig_raw can be set")
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	if !data.ConfigContext.IsNull() {
+		overrides.CurrentContext = data.ConfigContext.ValueString()
+	}
+	if !data.ConfigContextCluster.IsNull() {
+		overrides.Context.Cluster = data.ConfigContextCluster.ValueString()
+	}
+	if !data.ConfigContextUser.IsNull() {
+		overrides.Context.AuthInfo = data.ConfigContextUser.ValueString()
+	}
+	if !data.Host.IsNull() {
+		overrides.ClusterInfo.Server = data.Host.ValueString()
+	}
+	if !data.Insecure.IsNull() {
+		overrides.ClusterInfo.InsecureSkipTLSVerify = data.Insecure.ValueBool()
+	}
+	if !data.TLSServerName.IsNull() {
+		overrides.ClusterInfo.TLSServerName = data.TLSServerName.ValueString()
+	}
+	if !data.ProxyURL.IsNull() {
+		overrides.ClusterInfo.ProxyURL = data.ProxyURL.ValueString()
+	}
+	if !data.ClusterCACertificate.IsNull() {
+		overrides.ClusterInfo.CertificateAuthorityData = []byte(data.ClusterCACertificate.ValueString())
+	}
+	if !data.ClientCertificate.IsNull() {
+		overrides.AuthInfo.ClientCertificateData = []byte(data.ClientCertificate.ValueString())
+	}
+	if !data.ClientKey.IsNull() {
+		overrides.AuthInfo.ClientKeyData = []byte(data.ClientKey.ValueString())
+	}
+	if !data.Token.IsNull() {
+		overrides.AuthInfo.Token = data.Token.ValueString()
+	}
+	if !data.Username.IsNull() {
+		overrides.AuthInfo.Username = data.Username.ValueString()
+	}
+	if !data.Password.IsNull() {
+		overrides.AuthInfo.Password = data.Password.ValueString()
+	}
+	if data.Exec != nil {
+		exec, err := execConfig(ctx, data.Exec)
+		if err != nil {
+			return nil, err
+		}
+		overrides.AuthInfo.Exec = exec
+	}
+	if data.Impersonate != nil {
+		overrides.AuthInfo.Impersonate = data.Impersonate.User.ValueString()
+		overrides.AuthInfo.ImpersonateUID = data.Impersonate.UID.ValueString()
+		if d := data.Impersonate.Groups.ElementsAs(ctx, &overrides.AuthInfo.ImpersonateGroups, false); d.HasError() {
+			return nil, fmt.Errorf("invalid impersonate groups: %s", d.Errors()[0].Detail())
+		}
+	}
+
+	if !data.KubeconfigRaw.IsNull() {
+		raw, err := clientcmd.Load([]byte(data.KubeconfigRaw.ValueString()))
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig_raw: %w", err)
+		}
+		return clientcmd.NewNonInteractiveClientConfig(*raw, overrides.CurrentContext, overrides, nil).ClientConfig()
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if !data.ConfigPaths.IsNull() {
+		var paths []string
+		if d := data.ConfigPaths.ElementsAs(ctx, &paths, false); d.HasError() {
+			return nil, fmt.Errorf("invalid config_paths: %s", d.Errors()[0].Detail())
+		}
+		rules.Precedence = nil
+		for _, p := range paths {
+			rules.Precedence = append(rules.Precedence, expandPath(p))
+		}
+	}
+	if !data.Kubeconfig.IsNull() {
+		rules.ExplicitPath = expandPath(data.Kubeconfig.ValueString())
+	}
+	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+	return cc.ClientConfig()
+}
+
+// envDefaults maps environment variables to the string attributes they set
+// when the configuration leaves them out. The names match those of the
+// hashicorp/kubernetes provider.
+var envDefaults = map[string]func(*KubernetesCRDModel) *types.String{
+	"KUBE_CTX":                  func(m *KubernetesCRDModel) *types.String { return &m.ConfigContext },
+	"KUBE_CTX_CLUSTER":          func(m *KubernetesCRDModel) *types.String { return &m.ConfigContextCluster },
+	"KUBE_CTX_AUTH_INFO":        func(m *KubernetesCRDModel) *types.String { return &m.ConfigContextUser },
+	"KUBE_HOST":                 func(m *KubernetesCRDModel) *types.String { return &m.Host },
+	"KUBE_TOKEN":                func(m *KubernetesCRDModel) *types.String { return &m.Token },
+	"KUBE_CLIENT_CERT_DATA":     func(m *KubernetesCRDModel) *types.String { return &m.ClientCertificate },
+	"KUBE_CLIENT_KEY_DATA":      func(m *KubernetesCRDModel) *types.String { return &m.ClientKey },
+	"KUBE_CLUSTER_CA_CERT_DATA": func(m *KubernetesCRDModel) *types.String { return &m.ClusterCACertificate },
+	"KUBE_TLS_SERVER_NAME":      func(m *KubernetesCRDModel) *types.String { return &m.TLSServerName },
+	"KUBE_PROXY_URL":            func(m *KubernetesCRDModel) *types.String { return &m.ProxyURL },
+	"KUBE_USER":                 func(m *KubernetesCRDModel) *types.String { return &m.Username },
+	"KUBE_PASSWORD":             func(m *KubernetesCRDModel) *types.String { return &m.Password },
+}
+
+// withEnvDefaults fills the attributes left out of data from the environment.
+func withEnvDefaults(data KubernetesCRDModel) (KubernetesCRDModel, error) {
+	for env, attr := range envDefaults {
+		a := attr(&data)
+		if v, ok := os.LookupEnv(env); ok && a.IsNull() {
+			*a = types.StringValue(v)
+		}
+	}
+	// The kubeconfig location only comes from the environment when the
+	// configuration doesn't name one in any way.
+	if data.Kubeconfig.IsNull() && data.ConfigPaths.IsNull() && data.KubeconfigRaw.IsNull() {
+		if v, ok := os.LookupEnv("KUBE_CONFIG_PATH"); ok {
+			data.Kubeconfig = types.StringValue(v)
+		} else if v, ok := os.LookupEnv("KUBE_CONFIG_PATHS"); ok {
+			var paths []attr.Value
+			for _, p := range filepath.SplitList(v) {
+				paths = append(paths, types.StringValue(p))
+			}
+			data.ConfigPaths = types.ListValueMust(types.StringType, paths)
+		}
+	}
+	if v, ok := os.LookupEnv("KUBE_INSECURE"); ok && data.Insecure.IsNull() {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return data, fmt.Errorf("invalid KUBE_INSECURE: %w", err)
+		}
+		data.Insecure = types.BoolValue(b)
+	}
+	return data, nil
+}
+
+// execConfig translates the exec attribute into a credential plugin configuration.
+func execConfig(ctx context.Context, m *ExecModel) (*clientcmdapi.ExecConfig, error) {
+	exec := &clientcmdapi.ExecConfig{
+		APIVersion:      m.APIVersion.ValueString(),
+		Command:         m.Command.ValueString(),
+		InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+	}
+	if d := m.Args.ElementsAs(ctx, &exec.Args, false); d.HasError() {
+		return nil, fmt.Errorf("invalid exec args: %s", d.Errors()[0].Detail())
+	}
+	env := make(map[string]string)
+	if d := m.Env.ElementsAs(ctx, &env, false); d.HasError() {
+		return nil, fmt.Errorf("invalid exec env: %s", d.Errors()[0].Detail())
+	}
+	for k, v := range env {
+		exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: k, Value: v})
+	}
+	return exec, nil
+}
+
+// expandPath resolves a leading ~ in p to the user's home directory.
+func expandPath(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		return filepath.Join(homedir.HomeDir(), p[1:])
+	}
+	return p
+}
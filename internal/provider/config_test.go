@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestClientConfigOverrides(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/nonexistent/kubeconfig")
+
+	data := KubernetesCRDModel{}
+	data.Host = types.StringValue("https://cluster.example.com:6443")
+	data.Token = types.StringValue("secret")
+	data.Insecure = types.BoolValue(true)
+	data.TLSServerName = types.StringValue("kubernetes.default.svc")
+
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "https://cluster.example.com:6443" {
+		t.Errorf("unexpected host %q", cfg.Host)
+	}
+	if cfg.BearerToken != "secret" {
+		t.Errorf("unexpected token %q", cfg.BearerToken)
+	}
+	if !cfg.Insecure {
+		t.Error("expected insecure to be set")
+	}
+	if cfg.TLSClientConfig.ServerName != "kubernetes.default.svc" {
+		t.Errorf("unexpected TLS server name %q", cfg.TLSClientConfig.ServerName)
+	}
+}
+
+func TestClientConfigKubeconfig(t *testing.T) {
+	data := KubernetesCRDModel{}
+	data.Kubeconfig = types.StringValue("/nonexistent/kubeconfig")
+	if _, err := clientConfig(context.Background(), data); err == nil {
+		t.Error("expected an error for a missing kubeconfig file")
+	}
+}
+
+func TestClientConfigExec(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/nonexistent/kubeconfig")
+
+	data := KubernetesCRDModel{
+		Host: types.StringValue("https://cluster.example.com:6443"),
+		Exec: &ExecModel{
+			APIVersion: types.StringValue("client.authentication.k8s.io/v1beta1"),
+			Command:    types.StringValue("aws"),
+			Args:       types.ListValueMust(types.StringType, []attr.Value{types.StringValue("eks"), types.StringValue("get-token")}),
+			Env:        types.MapValueMust(types.StringType, map[string]attr.Value{"AWS_PROFILE": types.StringValue("prod")}),
+		},
+	}
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ExecProvider == nil {
+		t.Fatal("expected an exec provider")
+	}
+	if cfg.ExecProvider.Command != "aws" || len(cfg.ExecProvider.Args) != 2 {
+		t.Errorf("unexpected exec provider: %#v", cfg.ExecProvider)
+	}
+	if len(cfg.ExecProvider.Env) != 1 || cfg.ExecProvider.Env[0].Value != "prod" {
+		t.Errorf("unexpected exec env: %#v", cfg.ExecProvider.Env)
+	}
+}
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+users:
+- name: dev
+  user:
+    token: dev-token
+- name: prod
+  user:
+    token: prod-token
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+- name: prod
+  context:
+    cluster: prod
+    user: prod
+`
+
+func TestClientConfigKubeconfigRaw(t *testing.T) {
+	data := KubernetesCRDModel{}
+	data.KubeconfigRaw = types.StringValue(testKubeconfig)
+
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "https://dev.example.com:6443" {
+		t.Errorf("unexpected host %q", cfg.Host)
+	}
+	if cfg.BearerToken != "dev-token" {
+		t.Errorf("unexpected token %q", cfg.BearerToken)
+	}
+
+	data.Kubeconfig = types.StringValue("~/.kube/config")
+	if _, err := clientConfig(context.Background(), data); err == nil {
+		t.Error("expected an error when both kubeconfig and kubeconfig_raw are set")
+	}
+}
+
+func TestClientConfigContext(t *testing.T) {
+	data := KubernetesCRDModel{}
+	data.KubeconfigRaw = types.StringValue(testKubeconfig)
+	data.ConfigContext = types.StringValue("prod")
+
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "https://prod.example.com:6443" || cfg.BearerToken != "prod-token" {
+		t.Errorf("unexpected configuration for context prod: %q %q", cfg.Host, cfg.BearerToken)
+	}
+
+	data.ConfigContextUser = types.StringValue("dev")
+	cfg, err = clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "https://prod.example.com:6443" || cfg.BearerToken != "dev-token" {
+		t.Errorf("unexpected configuration with auth info override: %q %q", cfg.Host, cfg.BearerToken)
+	}
+}
+
+func TestClientConfigInCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+
+	data := KubernetesCRDModel{}
+	data.InCluster = types.BoolValue(true)
+	data.KubeconfigRaw = types.StringValue(testKubeconfig)
+	if _, err := clientConfig(context.Background(), data); err == nil {
+		t.Error("expected an error outside of a cluster")
+	}
+}
+
+func TestClientConfigProxyURL(t *testing.T) {
+	data := KubernetesCRDModel{}
+	data.KubeconfigRaw = types.StringValue(testKubeconfig)
+	data.ProxyURL = types.StringValue("socks5://localhost:1080")
+
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Proxy == nil {
+		t.Fatal("expected a proxy to be configured")
+	}
+	u, err := cfg.Proxy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "socks5://localhost:1080" {
+		t.Errorf("unexpected proxy %q", u)
+	}
+
+	data.ProxyURL = types.StringValue("ftp://localhost")
+	if _, err := clientConfig(context.Background(), data); err == nil {
+		t.Error("expected an error for an unsupported proxy scheme")
+	}
+}
+
+func TestClientConfigImpersonate(t *testing.T) {
+	data := KubernetesCRDModel{}
+	data.KubeconfigRaw = types.StringValue(testKubeconfig)
+	data.Impersonate = &ImpersonateModel{
+		User:   types.StringValue("system:serviceaccount:infra:deployer"),
+		Groups: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("deployers")}),
+	}
+
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Impersonate.UserName != "system:serviceaccount:infra:deployer" {
+		t.Errorf("unexpected impersonated user %q", cfg.Impersonate.UserName)
+	}
+	if len(cfg.Impersonate.Groups) != 1 || cfg.Impersonate.Groups[0] != "deployers" {
+		t.Errorf("unexpected impersonated groups %v", cfg.Impersonate.Groups)
+	}
+}
+
+func TestClientConfigThrottling(t *testing.T) {
+	data := KubernetesCRDModel{}
+	data.KubeconfigRaw = types.StringValue(testKubeconfig)
+	data.QPS = types.Float64Value(50)
+	data.Burst = types.Int64Value(100)
+	data.RequestTimeout = types.StringValue("30s")
+
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.QPS != 50 || cfg.Burst != 100 {
+		t.Errorf("unexpected rate limits: qps %v, burst %d", cfg.QPS, cfg.Burst)
+	}
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("unexpected request timeout %s", cfg.Timeout)
+	}
+}
+
+func TestClientConfigEnvDefaults(t *testing.T) {
+	t.Setenv("KUBE_HOST", "https://env.example.com:6443")
+	t.Setenv("KUBE_TOKEN", "env-token")
+	t.Setenv("KUBE_INSECURE", "true")
+
+	data := KubernetesCRDModel{}
+	data.KubeconfigRaw = types.StringValue(testKubeconfig)
+	data.Token = types.StringValue("config-token")
+
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "https://env.example.com:6443" {
+		t.Errorf("unexpected host %q", cfg.Host)
+	}
+	if cfg.BearerToken != "config-token" {
+		t.Errorf("expected the configured token to take precedence, got %q", cfg.BearerToken)
+	}
+	if !cfg.Insecure {
+		t.Error("expected insecure to be set")
+	}
+
+	t.Setenv("KUBE_INSECURE", "maybe")
+	if _, err := clientConfig(context.Background(), data); err == nil {
+		t.Error("expected an error for an invalid KUBE_INSECURE")
+	}
+}
+
+func TestClientConfigPaths(t *testing.T) {
+	dir := t.TempDir()
+	clusters := filepath.Join(dir, "clusters")
+	users := filepath.Join(dir, "users")
+	if err := os.WriteFile(clusters, []byte(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(users, []byte(`apiVersion: v1
+kind: Config
+users:
+- name: dev
+  user:
+    token: dev-token
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	data := KubernetesCRDModel{}
+	data.ConfigPaths = types.ListValueMust(types.StringType, []attr.Value{types.StringValue(clusters), types.StringValue(users)})
+	cfg, err := clientConfig(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "https://dev.example.com:6443" || cfg.BearerToken != "dev-token" {
+		t.Errorf("unexpected merged configuration: %q %q", cfg.Host, cfg.BearerToken)
+	}
+
+	t.Setenv("KUBE_CONFIG_PATHS", clusters+string(filepath.ListSeparator)+users)
+	cfg, err = clientConfig(context.Background(), KubernetesCRDModel{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BearerToken != "dev-token" {
+		t.Errorf("unexpected token from KUBE_CONFIG_PATHS: %q", cfg.BearerToken)
+	}
+
+	data.Kubeconfig = types.StringValue(clusters)
+	if _, err := clientConfig(context.Background(), data); err == nil {
+		t.Error("expected an error when both kubeconfig and config_paths are set")
+	}
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// crdsFromManifests reads the custom resource definitions held by the files
+// matching patterns, in lexical order of their paths. Other objects are
+// skipped, so that the manifests operators are released as can be used as
+// they are.
+func crdsFromManifests(patterns []string) ([]apiextv1.CustomResourceDefinition, error) {
+	var crds []apiextv1.CustomResourceDefinition
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no files match %q", pattern)
+		}
+		for _, path := range paths {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			objs, err := decodeManifests(string(b))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			for _, obj := range objs {
+				if obj["apiVersion"] != apiextv1.SchemeGroupVersion.String() || obj["kind"] != "CustomResourceDefinition" {
+					continue
+				}
+				// Numbers are decoded as json.Number, which the
+				// unstructured converter doesn't take.
+				j, err := json.Marshal(obj)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", path, err)
+				}
+				var crd apiextv1.CustomResourceDefinition
+				if err := json.Unmarshal(j, &crd); err != nil {
+					return nil, fmt.Errorf("%s: %w", path, err)
+				}
+				crds = append(crds, crd)
+			}
+		}
+	}
+	return crds, nil
+}
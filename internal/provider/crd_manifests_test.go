@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testCRDManifest = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: widgets-system
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  scope: Namespaced
+  names:
+    kind: Widget
+    singular: widget
+    plural: widgets
+    listKind: WidgetList
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              name:
+                type: string
+                maxLength: 63
+              replicas:
+                type: integer
+                minimum: 0
+                default: 1
+              ratio:
+                type: number
+                maximum: 1.5
+`
+
+func TestCRDsFromManifests(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "widgets.yaml"), []byte(testCRDManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	crds, err := crdsFromManifests([]string{filepath.Join(dir, "*.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(crds) != 1 || crds[0].Spec.Names.Kind != "Widget" {
+		t.Fatalf("expected the Widget definition, got %v", crds)
+	}
+	props := crds[0].Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties
+	if ml := props["name"].MaxLength; ml == nil || *ml != 63 {
+		t.Errorf("expected a maximum length of 63, got %v", ml)
+	}
+
+	if _, err := crdsFromManifests([]string{filepath.Join(dir, "*.json")}); err == nil {
+		t.Error("expected an error for a pattern matching no files")
+	}
+}
+
+func TestResourcesFromManifests(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "widgets.yaml"), []byte(testCRDManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CRD_MANIFEST_PATHS", filepath.Join(dir, "*.yaml"))
+	t.Setenv("KUBECONFIG", "/nonexistent/kubeconfig")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	p := New("test")().(*KubernetesCRD)
+	if rs := p.Resources(context.Background()); len(rs) != 1 {
+		t.Errorf("expected 1 resource, got %d", len(rs))
+	}
+	if p.discoveryDiags.HasError() {
+		t.Errorf("unexpected discovery errors: %v", p.discoveryDiags)
+	}
+	if p.options.source != schemaSourceCRD {
+		t.Errorf("expected schemas to be read from the definitions, got %q", p.options.source)
+	}
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ basetypes.StringTypable = DateTimeType{}
+var _ basetypes.StringValuableWithSemanticEquals = DateTimeValue{}
+
+// isDateTime reports whether s describes an RFC 3339 timestamp.
+func isDateTime(s *spec.Schema) bool {
+	return s != nil && s.Format == "date-time"
+}
+
+// DateTimeType is the type of attributes generated for date-time fields.
+// Timestamps which denote the same instant, such as "2024-01-01T10:00:00Z" and
+// "2024-01-01T11:00:00+01:00", are semantically equal, so normalization by
+// the API server or controllers causes no diffs.
+type DateTimeType struct {
+	basetypes.StringType
+}
+
+func (t DateTimeType) Equal(o attr.Type) bool {
+	other, ok := o.(DateTimeType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t DateTimeType) String() string {
+	return "DateTimeType"
+}
+
+func (t DateTimeType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return DateTimeValue{StringValue: in}, nil
+}
+
+func (t DateTimeType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	av, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	sv, ok := av.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", av)
+	}
+	v, diags := t.ValueFromString(ctx, sv)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to DateTimeValue: %v", diags)
+	}
+	return v, nil
+}
+
+func (t DateTimeType) ValueType(ctx context.Context) attr.Value {
+	return DateTimeValue{}
+}
+
+// DateTimeValue is a value of DateTimeType.
+type DateTimeValue struct {
+	basetypes.StringValue
+}
+
+func (v DateTimeValue) Equal(o attr.Value) bool {
+	other, ok := o.(DateTimeValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v DateTimeValue) Type(ctx context.Context) attr.Type {
+	return DateTimeType{}
+}
+
+// StringSemanticEquals compares timestamps by the instant they denote. Values
+// which don't parse as RFC 3339 timestamps are compared as strings.
+func (v DateTimeValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	nv, d := newValuable.ToStringValue(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return false, diags
+	}
+	if v.ValueString() == nv.ValueString() {
+		return true, diags
+	}
+	a, err := time.Parse(time.RFC3339Nano, v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	b, err := time.Parse(time.RFC3339Nano, nv.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	return a.Equal(b), diags
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestDateTimeSemanticEquals(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"2024-01-01T10:00:00Z", "2024-01-01T11:00:00+01:00", true},
+		{"2024-01-01T10:00:00Z", "2024-01-01T10:00:00.000Z", true},
+		{"2024-01-01T10:00:00Z", "2024-01-01T10:00:01Z", false},
+		{"soon", "2024-01-01T10:00:00Z", false},
+	}
+	for _, c := range cases {
+		v := DateTimeValue{StringValue: types.StringValue(c.a)}
+		eq, diags := v.StringSemanticEquals(context.Background(), DateTimeValue{StringValue: types.StringValue(c.b)})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if eq != c.equal {
+			t.Errorf("%q == %q: got %t, want %t", c.a, c.b, eq, c.equal)
+		}
+	}
+}
+
+func TestDateTimeAttributeFromOAPI(t *testing.T) {
+	a, _ := attributeFromOAPI(spec.DateTimeProperty(), path.Root("spec").AtName("renew_before"), optionalAttribute)
+	sa, ok := a.(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("expected a string attribute, got %T", a)
+	}
+	if _, ok := sa.CustomType.(DateTimeType); !ok {
+		t.Errorf("expected DateTimeType, got %T", sa.CustomType)
+	}
+}
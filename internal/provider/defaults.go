@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// stringDefaultFromOAPI returns the default of the string attribute generated
+// in mode m from s, or nil if it has none. OpenAPI defaults become defaults of
+// the generated attributes, which are then computed as well as optional so
+// that the value the API server would fill in is known at plan time. Only
+// optional attributes get defaults, and defaults of the wrong type are
+// ignored.
+func stringDefaultFromOAPI(s *spec.Schema, m attributeMode) defaults.String {
+	if s.Default == nil || m != optionalAttribute {
+		return nil
+	}
+	switch dv := s.Default.(type) {
+	case string:
+		return stringdefault.StaticString(dv)
+	case bool:
+		return nil
+	default:
+		// Int-or-string fields hold their integers as strings.
+		if nv, err := numberFromObject(dv); err == nil && (isIntOrString(s) || isQuantity(s)) {
+			return stringdefault.StaticString(nv.Text('f', -1))
+		}
+	}
+	return nil
+}
+
+func boolDefaultFromOAPI(s *spec.Schema, m attributeMode) defaults.Bool {
+	if dv, ok := s.Default.(bool); ok && m == optionalAttribute {
+		return booldefault.StaticBool(dv)
+	}
+	return nil
+}
+
+func int32DefaultFromOAPI(s *spec.Schema, m attributeMode) defaults.Int32 {
+	if iv, ok := integerDefault(s, m); ok {
+		return int32default.StaticInt32(int32(iv))
+	}
+	return nil
+}
+
+func int64DefaultFromOAPI(s *spec.Schema, m attributeMode) defaults.Int64 {
+	if iv, ok := integerDefault(s, m); ok {
+		return int64default.StaticInt64(iv)
+	}
+	return nil
+}
+
+func float32DefaultFromOAPI(s *spec.Schema, m attributeMode) defaults.Float32 {
+	if fv, ok := numberDefault(s, m); ok {
+		return float32default.StaticFloat32(float32(fv))
+	}
+	return nil
+}
+
+func float64DefaultFromOAPI(s *spec.Schema, m attributeMode) defaults.Float64 {
+	if fv, ok := numberDefault(s, m); ok {
+		return float64default.StaticFloat64(fv)
+	}
+	return nil
+}
+
+func integerDefault(s *spec.Schema, m attributeMode) (int64, bool) {
+	if s.Default == nil || m != optionalAttribute {
+		return 0, false
+	}
+	nv, err := numberFromObject(s.Default)
+	if err != nil || !nv.IsInt() {
+		return 0, false
+	}
+	iv, _ := nv.Int64()
+	return iv, true
+}
+
+func numberDefault(s *spec.Schema, m attributeMode) (float64, bool) {
+	if s.Default == nil || m != optionalAttribute {
+		return 0, false
+	}
+	nv, err := numberFromObject(s.Default)
+	if err != nil {
+		return 0, false
+	}
+	fv, _ := nv.Float64()
+	return fv, true
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"errors"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// clusterUnavailable reports whether err means the API server doesn't exist
+// yet, as is the case when the cluster has yet to be created: its host name
+// doesn't resolve, or nothing listens at its address. Other network errors,
+// such as TLS failures and timeouts, point at an existing cluster.
+func clusterUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var de *net.DNSError
+	return utilnet.IsConnectionRefused(err) || (errors.As(err, &de) && de.IsNotFound)
+}
+
+// prerequisiteAbsent reports whether err means something the object depends
+// on is missing: the cluster itself, the custom resource definition, or the
+// namespace the object lives in.
+func prerequisiteAbsent(err error) bool {
+	return clusterUnavailable(err) || apierrors.IsNotFound(err)
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestPrerequisiteAbsent(t *testing.T) {
+	gr := rtschema.GroupResource{Group: "example.com", Resource: "widgets"}
+	cases := []struct {
+		name        string
+		err         error
+		unavailable bool
+		absent      bool
+	}{
+		{"nil", nil, false, false},
+		{"connection refused", &url.Error{Op: "Get", URL: "https://127.0.0.1:6443", Err: syscall.ECONNREFUSED}, true, true},
+		{"host not found", &url.Error{Op: "Get", URL: "https://cluster.example.com", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Name: "cluster.example.com", IsNotFound: true}}}, true, true},
+		{"tls", &url.Error{Op: "Get", URL: "https://127.0.0.1:6443", Err: x509.UnknownAuthorityError{}}, false, false},
+		{"timeout", &url.Error{Op: "Get", URL: "https://127.0.0.1:6443", Err: context.DeadlineExceeded}, false, false},
+		{"not found", apierrors.NewNotFound(gr, "test"), false, true},
+		{"forbidden", apierrors.NewForbidden(gr, "test", errors.New("denied")), false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := clusterUnavailable(c.err); got != c.unavailable {
+				t.Errorf("clusterUnavailable: got %t, want %t", got, c.unavailable)
+			}
+			if got := prerequisiteAbsent(c.err); got != c.absent {
+				t.Errorf("prerequisiteAbsent: got %t, want %t", got, c.absent)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ datasource.DataSource = &DefinitionsDataSource{}
+var _ datasource.DataSourceWithConfigure = &DefinitionsDataSource{}
+
+func NewDefinitionsDataSource() datasource.DataSource {
+	return &DefinitionsDataSource{}
+}
+
+// DefinitionsDataSource lists the custom resource definitions installed in
+// the cluster.
+type DefinitionsDataSource struct {
+	clients *KubernetesClients
+}
+
+type definitionsDataSourceModel struct {
+	Definitions []definitionModel `tfsdk:"definitions"`
+}
+
+type definitionModel struct {
+	Name       types.String `tfsdk:"name"`
+	Group      types.String `tfsdk:"group"`
+	Kind       types.String `tfsdk:"kind"`
+	Plural     types.String `tfsdk:"plural"`
+	Singular   types.String `tfsdk:"singular"`
+	Scope      types.String `tfsdk:"scope"`
+	Versions   []string     `tfsdk:"versions"`
+	Categories []string     `tfsdk:"categories"`
+	ShortNames []string     `tfsdk:"short_names"`
+}
+
+func (d *DefinitionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_definitions"
+}
+
+func (d *DefinitionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the custom resource definitions installed in the cluster.",
+		Attributes: map[string]schema.Attribute{
+			"definitions": schema.ListNestedAttribute{
+				MarkdownDescription: "Custom resource definitions, ordered by name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the definition, such as `widgets.example.com`.",
+							Computed:            true,
+						},
+						"group": schema.StringAttribute{
+							MarkdownDescription: "API group of the kind.",
+							Computed:            true,
+						},
+						"kind": schema.StringAttribute{
+							MarkdownDescription: "Kind of the objects.",
+							Computed:            true,
+						},
+						"plural": schema.StringAttribute{
+							MarkdownDescription: "Plural name of the resource.",
+							Computed:            true,
+						},
+						"singular": schema.StringAttribute{
+							MarkdownDescription: "Singular name of the resource.",
+							Computed:            true,
+						},
+						"scope": schema.StringAttribute{
+							MarkdownDescription: "Scope of the objects, `Namespaced` or `Cluster`.",
+							Computed:            true,
+						},
+						"versions": schema.ListAttribute{
+							MarkdownDescription: "Versions served by the API server.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"categories": schema.ListAttribute{
+							MarkdownDescription: "Categories the resource belongs to, such as `all`.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"short_names": schema.ListAttribute{
+							MarkdownDescription: "Short names of the resource.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DefinitionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.clients = pd.Clients
+}
+
+func (d *DefinitionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	crds, err := d.clients.reads.get("customresourcedefinitions", func() (interface{}, error) {
+		return d.clients.APIextensions.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list Custom Resource Definitions", err.Error())
+		return
+	}
+	data := definitionsDataSourceModel{Definitions: definitionsFromCRDs(crds.(*apiextv1.CustomResourceDefinitionList).Items)}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// definitionsFromCRDs returns the models of crds, ordered by name. Only served
+// versions are listed.
+func definitionsFromCRDs(crds []apiextv1.CustomResourceDefinition) []definitionModel {
+	defs := make([]definitionModel, 0, len(crds))
+	for _, crd := range crds {
+		versions := []string{}
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				versions = append(versions, v.Name)
+			}
+		}
+		defs = append(defs, definitionModel{
+			Name:       types.StringValue(crd.Name),
+			Group:      types.StringValue(crd.Spec.Group),
+			Kind:       types.StringValue(crd.Spec.Names.Kind),
+			Plural:     types.StringValue(crd.Spec.Names.Plural),
+			Singular:   types.StringValue(crd.Spec.Names.Singular),
+			Scope:      types.StringValue(string(crd.Spec.Scope)),
+			Versions:   versions,
+			Categories: append([]string{}, crd.Spec.Names.Categories...),
+			ShortNames: append([]string{}, crd.Spec.Names.ShortNames...),
+		})
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Name.ValueString() < defs[j].Name.ValueString()
+	})
+	return defs
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDefinitionsFromCRDs(t *testing.T) {
+	crds := []apiextv1.CustomResourceDefinition{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+			Spec: apiextv1.CustomResourceDefinitionSpec{
+				Group: "example.com",
+				Names: apiextv1.CustomResourceDefinitionNames{Kind: "Widget", Plural: "widgets", Singular: "widget", Categories: []string{"all"}},
+				Scope: apiextv1.NamespaceScoped,
+				Versions: []apiextv1.CustomResourceDefinitionVersion{
+					{Name: "v1", Served: true},
+					{Name: "v1alpha1", Served: false},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "clusterissuers.cert-manager.io"},
+			Spec: apiextv1.CustomResourceDefinitionSpec{
+				Group: "cert-manager.io",
+				Names: apiextv1.CustomResourceDefinitionNames{Kind: "ClusterIssuer", Plural: "clusterissuers", Singular: "clusterissuer"},
+				Scope: apiextv1.ClusterScoped,
+			},
+		},
+	}
+	defs := definitionsFromCRDs(crds)
+	if len(defs) != 2 {
+		t.Fatalf("expected 2 definitions, got %d", len(defs))
+	}
+	if defs[0].Name.ValueString() != "clusterissuers.cert-manager.io" {
+		t.Errorf("expected definitions ordered by name, got %s first", defs[0].Name.ValueString())
+	}
+	w := defs[1]
+	if w.Scope.ValueString() != "Namespaced" || w.Kind.ValueString() != "Widget" {
+		t.Errorf("unexpected definition: %+v", w)
+	}
+	if !reflect.DeepEqual(w.Versions, []string{"v1"}) {
+		t.Errorf("expected served versions only, got %v", w.Versions)
+	}
+	if !reflect.DeepEqual(w.Categories, []string{"all"}) || w.ShortNames == nil {
+		t.Errorf("unexpected names: %v %v", w.Categories, w.ShortNames)
+	}
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// Delete strategies, which set what destroying a resource does to its object.
+// Objects can be handed over to other tools, such as GitOps controllers, by
+// destroying their resources without deleting them.
+const (
+	// deleteStrategyDelete deletes the object.
+	deleteStrategyDelete = "delete"
+	// deleteStrategyOrphan leaves the object in place, and removes the
+	// provider from its field managers.
+	deleteStrategyOrphan = "orphan"
+	// deleteStrategyAbandon leaves the object as it is.
+	deleteStrategyAbandon = "abandon"
+)
+
+var deleteStrategies = []string{deleteStrategyDelete, deleteStrategyOrphan, deleteStrategyAbandon}
+
+func preventDeletionAttribute() schema.Attribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether to refuse to delete the object, failing plans which destroy or replace the resource. " +
+			"It must be unset, and the change applied, before the object can be deleted.",
+		Optional: true,
+	}
+}
+
+func deleteStrategyAttribute() schema.Attribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "What destroying the resource does to the object: `delete` deletes it, " +
+			"`orphan` leaves it in place and gives up the ownership of its fields, so that other field managers can take them over, " +
+			"and `abandon` leaves it as it is. Defaults to `delete`.",
+		Optional:   true,
+		Validators: []validator.String{stringOneOfValidator{values: deleteStrategies}},
+	}
+}
+
+// deleteStrategy returns the delete strategy of the resource value held by g.
+func deleteStrategy(ctx context.Context, g attributeGetter) (string, diag.Diagnostics) {
+	var strategy types.String
+	diags := g.GetAttribute(ctx, path.Root("delete_strategy"), &strategy)
+	if strategy.IsNull() || strategy.IsUnknown() {
+		return deleteStrategyDelete, diags
+	}
+	return strategy.ValueString(), diags
+}
+
+// deletionPrevented reports whether the prevent_deletion attribute of the
+// resource value held by g is set. Objects which are costly to lose, such as
+// databases, are thereby protected from being deleted by Terraform, be it by
+// destroying or replacing the resource. Plans which would delete them fail,
+// and so do deletes, in case they were planned before the protection was
+// applied. Objects which destroying the resource leaves in place aren't
+// protected.
+func deletionPrevented(ctx context.Context, g attributeGetter) (bool, diag.Diagnostics) {
+	var prevent types.Bool
+	diags := g.GetAttribute(ctx, path.Root("prevent_deletion"), &prevent)
+	strategy, d := deleteStrategy(ctx, g)
+	diags.Append(d...)
+	return prevent.ValueBool() && strategy == deleteStrategyDelete, diags
+}
+
+// replacementPlanned reports whether the plan of req replaces the object, as
+// changing its name, namespace, triggers or immutable fields does. Resources
+// learn about the replacements planned by attribute plan modifiers only once
+// they are done planning, so they are worked out again here.
+func (r *CustomResource) replacementPlanned(ctx context.Context, req resource.ModifyPlanRequest) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	md := path.Root("metadata")
+	names := []path.Path{md.AtName("name"), md.AtName("generate_name")}
+	if r.namespaced {
+		names = append(names, md.AtName("namespace"))
+	}
+	for _, p := range names {
+		var state, plan, config types.String
+		diags.Append(req.State.GetAttribute(ctx, p, &state)...)
+		diags.Append(req.Plan.GetAttribute(ctx, p, &plan)...)
+		diags.Append(req.Config.GetAttribute(ctx, p, &config)...)
+		if diags.HasError() {
+			return false, diags
+		}
+		// Names computed from generate_name are kept when it is unset.
+		if p.Equal(md.AtName("name")) && config.IsNull() {
+			continue
+		}
+		if !plan.Equal(state) {
+			return true, diags
+		}
+	}
+
+	tr := &planmodifier.MapResponse{}
+	tq := planmodifier.MapRequest{}
+	diags.Append(req.State.GetAttribute(ctx, path.Root("triggers"), &tq.StateValue)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("triggers"), &tq.PlanValue)...)
+	if diags.HasError() {
+		return false, diags
+	}
+	triggersModifier{}.PlanModifyMap(ctx, tq, tr)
+	if tr.RequiresReplace {
+		return true, diags
+	}
+
+	replaced := false
+	err := tftypes.Walk(req.Plan.Raw, func(p *tftypes.AttributePath, pv tftypes.Value) (bool, error) {
+		if replaced || len(p.Steps()) == 0 {
+			return !replaced, nil
+		}
+		a, err := req.Plan.Schema.AttributeAtTerraformPath(ctx, p)
+		if err != nil {
+			// Elements of lists, sets and maps aren't attributes, unlike
+			// the attributes of the objects they hold.
+			return true, nil
+		}
+		// Values within dynamic attributes have no attributes of their own.
+		_, dynamic := a.(schema.DynamicAttribute)
+		if !hasImmutableModifier(a) {
+			return !dynamic, nil
+		}
+		sv, _, err := tftypes.WalkAttributePath(req.State.Raw, p)
+		if err != nil {
+			return !dynamic, nil
+		}
+		state, err := a.GetType().ValueFromTerraform(ctx, sv.(tftypes.Value))
+		if err != nil {
+			return false, err
+		}
+		plan, err := a.GetType().ValueFromTerraform(ctx, pv)
+		if err != nil {
+			return false, err
+		}
+		replaced = immutableModifier{}.requiresReplace(state, plan)
+		return !replaced && !dynamic, nil
+	})
+	if err != nil {
+		diags.AddError("Failed to inspect plan", err.Error())
+	}
+	return replaced, diags
+}
+
+// deletionPreventedDiagnostic reports that obj can't be deleted.
+func (r *CustomResource) deletionPreventedDiagnostic(obj *unstructured.Unstructured) diag.Diagnostic {
+	return diag.NewErrorDiagnostic("Deletion Prevented", fmt.Sprintf(
+		"%s %q has prevent_deletion set, so it can't be deleted or replaced. "+
+			"Unset prevent_deletion and apply the change before deleting it.", r.gvk.Kind, obj.GetName()))
+}
+
+// relinquishFields removes manager from the field managers of live, so that
+// the fields it owns are left to the other managers. The API server clears
+// managedFields when given a single empty entry, as an empty list leaves
+// them unchanged. The object is read again when it changed since live was.
+func relinquishFields(ctx context.Context, rc dynamic.ResourceInterface, live *unstructured.Unstructured, manager string) error {
+	stale := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if stale {
+			var err error
+			live, err = rc.Get(ctx, live.GetName(), metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		stale = true
+		var kept []metav1.ManagedFieldsEntry
+		for _, e := range live.GetManagedFields() {
+			if e.Manager != manager {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == len(live.GetManagedFields()) {
+			return nil
+		}
+		if len(kept) == 0 {
+			kept = []metav1.ManagedFieldsEntry{{}}
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"managedFields":   kept,
+				"resourceVersion": live.GetResourceVersion(),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = rc.Patch(ctx, live.GetName(), apitypes.MergePatchType, patch, metav1.PatchOptions{})
+		return err
+	})
+}
@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// testResourceProvider serves a single resource, so that tests can plan it as
+// Terraform would, through the framework.
+type testResourceProvider struct {
+	r resource.Resource
+}
+
+func (p testResourceProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "crd"
+}
+
+func (p testResourceProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+}
+
+func (p testResourceProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+}
+
+func (p testResourceProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{func() resource.Resource { return p.r }}
+}
+
+func (p testResourceProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func TestPreventDeletion(t *testing.T) {
+	ctx := context.Background()
+	crd := testCRDSchema()
+	class := spec.StringProperty()
+	class.AddExtension("x-kubernetes-validations", []interface{}{map[string]interface{}{"rule": "self == oldSelf"}})
+	crd.Properties["spec"].Properties["class"] = *class
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, crd, schemaOptions{}).(*CustomResource)
+	s := testCustomResourceSchema(t, r)
+	typ := s.Type().TerraformType(ctx).(tftypes.Object)
+	mt := typ.AttributeTypes["metadata"].(tftypes.Object)
+	st := typ.AttributeTypes["spec"].(tftypes.Object)
+	mr := &resource.MetadataResponse{}
+	r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "crd"}, mr)
+
+	type widget struct {
+		namespace, class, image, revision string
+		prevent                           bool
+		strategy                          interface{}
+	}
+	value := func(w widget) tftypes.Value {
+		av := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+		for n, at := range typ.AttributeTypes {
+			av[n] = tftypes.NewValue(at, nil)
+		}
+		mv := make(map[string]tftypes.Value, len(mt.AttributeTypes))
+		for n, at := range mt.AttributeTypes {
+			mv[n] = tftypes.NewValue(at, nil)
+		}
+		mv["name"] = tftypes.NewValue(tftypes.String, "test")
+		mv["namespace"] = tftypes.NewValue(tftypes.String, w.namespace)
+		av["metadata"] = tftypes.NewValue(mt, mv)
+		av["spec"] = tftypes.NewValue(st, map[string]tftypes.Value{
+			"replicas": tftypes.NewValue(tftypes.Number, 1),
+			"image":    tftypes.NewValue(tftypes.String, w.image),
+			"class":    tftypes.NewValue(tftypes.String, w.class),
+		})
+		tt := typ.AttributeTypes["triggers"]
+		av["triggers"] = tftypes.NewValue(tt, map[string]tftypes.Value{"revision": tftypes.NewValue(tftypes.String, w.revision)})
+		av["prevent_deletion"] = tftypes.NewValue(tftypes.Bool, w.prevent)
+		av["delete_strategy"] = tftypes.NewValue(tftypes.String, w.strategy)
+		return tftypes.NewValue(typ, av)
+	}
+	plan := func(prior, config tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+		t.Helper()
+		dv := func(v tftypes.Value) *tfprotov6.DynamicValue {
+			d, err := tfprotov6.NewDynamicValue(typ, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return &d
+		}
+		server := providerserver.NewProtocol6(testResourceProvider{r: r})()
+		resp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+			TypeName:         mr.TypeName,
+			PriorState:       dv(prior),
+			ProposedNewState: dv(config),
+			Config:           dv(config),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	failed := func(resp *tfprotov6.PlanResourceChangeResponse) bool {
+		for _, d := range resp.Diagnostics {
+			if d.Severity == tfprotov6.DiagnosticSeverityError && d.Summary == "Deletion Prevented" {
+				return true
+			}
+		}
+		return false
+	}
+
+	protected := widget{namespace: "default", class: "standard", image: "nginx", revision: "1", prevent: true}
+	changes := map[string]func(w widget) widget{
+		"namespace": func(w widget) widget { w.namespace = "other"; return w },
+		"immutable": func(w widget) widget { w.class = "premium"; return w },
+		"triggers":  func(w widget) widget { w.revision = "2"; return w },
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			if resp := plan(value(protected), value(change(protected))); !failed(resp) {
+				t.Errorf("expected the replacement to fail, got %v", resp.Diagnostics)
+			}
+			unprotected := protected
+			unprotected.prevent = false
+			resp := plan(value(unprotected), value(change(unprotected)))
+			if failed(resp) || len(resp.RequiresReplace) == 0 {
+				t.Errorf("expected the object to be replaced, got %v (replacing %v)", resp.Diagnostics, resp.RequiresReplace)
+			}
+		})
+	}
+
+	updated := protected
+	updated.image = "nginx:latest"
+	if resp := plan(value(protected), value(updated)); failed(resp) || len(resp.RequiresReplace) != 0 {
+		t.Errorf("expected the object to be updated in place, got %v (replacing %v)", resp.Diagnostics, resp.RequiresReplace)
+	}
+
+	destroy := tftypes.NewValue(typ, nil)
+	if resp := plan(value(protected), destroy); !failed(resp) {
+		t.Errorf("expected the destroy plan to fail, got %v", resp.Diagnostics)
+	}
+	unprotected := protected
+	unprotected.prevent = false
+	if resp := plan(value(unprotected), destroy); failed(resp) {
+		t.Errorf("expected unprotected objects to be destroyed, got %v", resp.Diagnostics)
+	}
+	abandoned := protected
+	abandoned.strategy = deleteStrategyAbandon
+	if resp := plan(value(abandoned), destroy); failed(resp) {
+		t.Errorf("expected objects left in place to be destroyed, got %v", resp.Diagnostics)
+	}
+
+	state := tfsdk.State{Schema: s, Raw: value(protected)}
+	dresp := &resource.DeleteResponse{State: state}
+	r.Delete(ctx, resource.DeleteRequest{State: state}, dresp)
+	if !dresp.Diagnostics.HasError() || !strings.Contains(dresp.Diagnostics[0].Detail(), `Widget "test"`) {
+		t.Errorf("expected the delete to fail, got %v", dresp.Diagnostics)
+	}
+}
+
+func TestRelinquishFields(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	widget := func(managers ...string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": "test", "namespace": "default"},
+			"spec":       map[string]interface{}{"size": "large"},
+		}}
+		var fields []metav1.ManagedFieldsEntry
+		for _, m := range managers {
+			fields = append(fields, metav1.ManagedFieldsEntry{Manager: m, Operation: metav1.ManagedFieldsOperationApply})
+		}
+		obj.SetManagedFields(fields)
+		return obj
+	}
+	tests := []struct {
+		name     string
+		managers []string
+		want     []metav1.ManagedFieldsEntry
+		patched  bool
+	}{
+		{name: "shared", managers: []string{defaultFieldManagerName, "argocd"}, want: []metav1.ManagedFieldsEntry{{Manager: "argocd", Operation: metav1.ManagedFieldsOperationApply}}, patched: true},
+		{name: "sole", managers: []string{defaultFieldManagerName}, want: []metav1.ManagedFieldsEntry{{}}, patched: true},
+		{name: "unmanaged", managers: []string{"argocd"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			live := widget(tc.managers...)
+			client := fake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+			var patch map[string]interface{}
+			client.PrependReactor("patch", "widgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				_ = json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &patch)
+				return false, nil, nil
+			})
+			if err := relinquishFields(context.Background(), client.Resource(gvr).Namespace("default"), live, defaultFieldManagerName); err != nil {
+				t.Fatal(err)
+			}
+			if (patch != nil) != tc.patched {
+				t.Fatalf("expected patched to be %t, got %v", tc.patched, patch)
+			}
+			if !tc.patched {
+				return
+			}
+			var got struct {
+				Metadata struct {
+					ManagedFields []metav1.ManagedFieldsEntry `json:"managedFields"`
+				} `json:"metadata"`
+			}
+			b, _ := json.Marshal(patch)
+			_ = json.Unmarshal(b, &got)
+			if !reflect.DeepEqual(got.Metadata.ManagedFields, tc.want) {
+				t.Errorf("expected managed fields %v, got %v", tc.want, got.Metadata.ManagedFields)
+			}
+		})
+	}
+}
+
+func TestRelinquishFieldsConflict(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "test", "namespace": "default"},
+	}}
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: defaultFieldManagerName}, {Manager: "argocd"}})
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+	patches, gets := 0, 0
+	client.PrependReactor("get", "widgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	client.PrependReactor("patch", "widgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patches++
+		if patches == 1 {
+			return true, nil, apierrors.NewConflict(gvr.GroupResource(), "test", errors.New("the object has been modified"))
+		}
+		return false, nil, nil
+	})
+	if err := relinquishFields(context.Background(), client.Resource(gvr).Namespace("default"), live, defaultFieldManagerName); err != nil {
+		t.Fatal(err)
+	}
+	if patches != 2 || gets != 1 {
+		t.Errorf("expected the object to be read again before patching it again, got %d patches and %d gets", patches, gets)
+	}
+}
@@ -0,0 +1,272 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ datasource.DataSource = &CustomDataSource{}
+var _ datasource.DataSourceWithConfigure = &CustomDataSource{}
+
+func NewCustomDataSource(v string, g string, n v1.CustomResourceDefinitionNames, scope v1.ResourceScope, s *spec.Schema, opts schemaOptions) datasource.DataSource {
+	return &CustomDataSource{
+		resource: NewCustomResource(v, g, n, scope, s, opts).(*CustomResource),
+	}
+}
+
+// CustomDataSource reads an existing object of a custom resource kind. Its
+// attributes are those of the resource generated for the kind, all computed.
+type CustomDataSource struct {
+	resource *CustomResource
+}
+
+func (d *CustomDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.resource.name
+}
+
+func (d *CustomDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attrs, diags := d.resource.dataSourceAttributes(ctx)
+	resp.Diagnostics.Append(diags...)
+	attrs["metadata"] = dataSourceMetadataAttribute(d.resource.namespaced)
+	attrs["wait_for"] = waitForAttribute("Block until the object exists and satisfies all of the given criteria, e.g. when it is created asynchronously by an operator.")
+	attrs["jsonpath"] = schema.MapAttribute{
+		MarkdownDescription: "JSONPath expressions to evaluate against the object, by name, e.g. `{ phase = \"{.status.phase}\" }`.",
+		Optional:            true,
+		ElementType:         types.StringType,
+	}
+	attrs["jsonpath_values"] = schema.MapAttribute{
+		MarkdownDescription: "Results of the `jsonpath` expressions, by name. Missing fields yield empty strings.",
+		Computed:            true,
+		ElementType:         types.StringType,
+	}
+	resp.Schema.Description = d.resource.description("Reads")
+	resp.Schema.MarkdownDescription = d.resource.markdownDescription("Reads")
+	resp.Schema.Attributes = attrs
+}
+
+func (d *CustomDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.resource.clients = pd.Clients
+	d.resource.operationTimeout = pd.OperationTimeout
+}
+
+func (d *CustomDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var name, namespace types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("metadata").AtName("name"), &name)...)
+	if d.resource.namespaced {
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("metadata").AtName("namespace"), &namespace)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	wc, diags := waitCriteriaFrom(ctx, req.Config, path.Root("wait_for"))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r := d.resource
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+	obj := &unstructured.Unstructured{}
+	obj.SetName(name.ValueString())
+	obj.SetNamespace(namespace.ValueString())
+	rc := r.resourceClient(obj)
+	var live *unstructured.Unstructured
+	var err error
+	if wc != nil {
+		live, err = waitForObject(ctx, rc, obj.GetName(), wc)
+		if err != nil {
+			resp.Diagnostics.AddError("Wait Failed", fmt.Sprintf("%s %q did not reach the expected state: %s", r.gvk.Kind, obj.GetName(), err))
+			return
+		}
+	} else {
+		live, err = rc.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			resp.Diagnostics.AddError("Object Not Found", fmt.Sprintf("%s %q doesn't exist.", r.gvk.Kind, obj.GetName()))
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s %q, got error: %s", r.gvk.Kind, obj.GetName(), err))
+			return
+		}
+	}
+
+	v, err := valueFromObject(r.schema, resp.State.Raw.Type(), live.Object)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert object", err.Error())
+		return
+	}
+	var av, cv map[string]tftypes.Value
+	if err := v.As(&av); err != nil {
+		resp.Diagnostics.AddError("Failed to convert object", err.Error())
+		return
+	}
+	if err := req.Config.Raw.As(&cv); err != nil {
+		resp.Diagnostics.AddError("Failed to read configuration", err.Error())
+		return
+	}
+	av["wait_for"] = cv["wait_for"]
+	av["jsonpath"] = cv["jsonpath"]
+	av["jsonpath_values"], err = jsonPathValues(cv["jsonpath"], live.Object)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("jsonpath"), "Invalid JSONPath Expression", err.Error())
+		return
+	}
+	resp.State.Raw = tftypes.NewValue(v.Type(), av)
+}
+
+// jsonPathValues evaluates the JSONPath expressions of exprs, a map of names
+// to expressions, against obj.
+func jsonPathValues(exprs tftypes.Value, obj map[string]interface{}) (tftypes.Value, error) {
+	mt := tftypes.Map{ElementType: tftypes.String}
+	if exprs.IsNull() {
+		return tftypes.NewValue(mt, nil), nil
+	}
+	var ev map[string]tftypes.Value
+	if err := exprs.As(&ev); err != nil {
+		return tftypes.Value{}, err
+	}
+	results := make(map[string]tftypes.Value, len(ev))
+	for n, e := range ev {
+		var expr string
+		if err := e.As(&expr); err != nil {
+			return tftypes.Value{}, err
+		}
+		jp, err := parseJSONPath(expr)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		var buf bytes.Buffer
+		if err := jp.Execute(&buf, obj); err != nil {
+			return tftypes.Value{}, fmt.Errorf("evaluating %q: %w", expr, err)
+		}
+		results[n] = tftypes.NewValue(tftypes.String, buf.String())
+	}
+	return tftypes.NewValue(mt, results), nil
+}
+
+// dataSourceAttributes returns the attributes of the resource of the kind
+// converted into computed data source attributes, leaving out metadata,
+// provider-defined attributes and the live object.
+func (r *CustomResource) dataSourceAttributes(ctx context.Context) (map[string]schema.Attribute, diag.Diagnostics) {
+	rs := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, rs)
+	attrs := make(map[string]schema.Attribute, len(rs.Schema.Attributes))
+	for n, a := range rs.Schema.Attributes {
+		if n == "metadata" || containsString(resourceAttributes, n) || (n == "object" && r.hasLiveObject()) {
+			continue
+		}
+		attrs[n] = dataSourceAttribute(a)
+	}
+	return attrs, rs.Diagnostics
+}
+
+// dataSourceUIDAttribute exposes the UID of objects read by data sources, by
+// which other objects such as events refer to them.
+var dataSourceUIDAttribute = schema.StringAttribute{
+	MarkdownDescription: "Unique identifier of the object, set by the API server.",
+	Computed:            true,
+}
+
+func dataSourceMetadataAttribute(namespaced bool) schema.Attribute {
+	attrs := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: "Name of the object.",
+			Required:            true,
+		},
+		"uid": dataSourceUIDAttribute,
+		"labels": schema.MapAttribute{
+			MarkdownDescription: "Labels used to organize and select objects.",
+			Computed:            true,
+			ElementType:         types.StringType,
+		},
+		"annotations": schema.MapAttribute{
+			MarkdownDescription: "Arbitrary non-identifying metadata.",
+			Computed:            true,
+			ElementType:         types.StringType,
+		},
+	}
+	if namespaced {
+		attrs["namespace"] = schema.StringAttribute{
+			MarkdownDescription: "Namespace of the object.",
+			Required:            true,
+		}
+	}
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Standard object metadata.",
+		Required:            true,
+		Attributes:          attrs,
+	}
+}
+
+// dataSourceAttribute returns the computed data source attribute matching the
+// resource attribute a.
+func dataSourceAttribute(a rschema.Attribute) schema.Attribute {
+	switch a := a.(type) {
+	case rschema.StringAttribute:
+		return schema.StringAttribute{CustomType: a.CustomType, Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.BoolAttribute:
+		return schema.BoolAttribute{Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.Int32Attribute:
+		return schema.Int32Attribute{Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.Int64Attribute:
+		return schema.Int64Attribute{Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.Float32Attribute:
+		return schema.Float32Attribute{Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.Float64Attribute:
+		return schema.Float64Attribute{Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.DynamicAttribute:
+		return schema.DynamicAttribute{Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.ListAttribute:
+		return schema.ListAttribute{ElementType: a.ElementType, Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.SetAttribute:
+		return schema.SetAttribute{ElementType: a.ElementType, Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.MapAttribute:
+		return schema.MapAttribute{ElementType: a.ElementType, Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.SingleNestedAttribute:
+		return schema.SingleNestedAttribute{Attributes: dataSourceNestedAttributes(a.Attributes), Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.ListNestedAttribute:
+		return schema.ListNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: dataSourceNestedAttributes(a.NestedObject.Attributes)}, Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.SetNestedAttribute:
+		return schema.SetNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: dataSourceNestedAttributes(a.NestedObject.Attributes)}, Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	case rschema.MapNestedAttribute:
+		return schema.MapNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: dataSourceNestedAttributes(a.NestedObject.Attributes)}, Computed: true, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+	}
+	return schema.DynamicAttribute{Computed: true, Description: a.GetDescription(), MarkdownDescription: a.GetMarkdownDescription()}
+}
+
+func dataSourceNestedAttributes(attrs map[string]rschema.Attribute) map[string]schema.Attribute {
+	da := make(map[string]schema.Attribute, len(attrs))
+	for n, a := range attrs {
+		da[n] = dataSourceAttribute(a)
+	}
+	return da
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func testCustomDataSourceSchema(t *testing.T, d datasource.DataSource) schema.Schema {
+	t.Helper()
+	resp := &datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	return resp.Schema
+}
+
+func TestCustomDataSourceSchema(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomDataSourceSchema(t, NewCustomDataSource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}))
+
+	md, ok := s.Attributes["metadata"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a metadata attribute, got %T", s.Attributes["metadata"])
+	}
+	if !md.Attributes["name"].IsRequired() || !md.Attributes["namespace"].IsRequired() {
+		t.Error("expected name and namespace to be required for a namespaced kind")
+	}
+	if !md.Attributes["labels"].IsComputed() {
+		t.Error("expected labels to be computed")
+	}
+	if !s.Attributes["jsonpath"].IsOptional() || !s.Attributes["jsonpath_values"].IsComputed() {
+		t.Error("unexpected jsonpath attribute flags")
+	}
+	sp, ok := s.Attributes["spec"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a spec attribute, got %T", s.Attributes["spec"])
+	}
+	for n, a := range sp.Attributes {
+		if !a.IsComputed() || a.IsOptional() || a.IsRequired() {
+			t.Errorf("expected spec.%s to be computed only", n)
+		}
+	}
+	if _, ok := s.Attributes["status"].(schema.SingleNestedAttribute); !ok {
+		t.Errorf("expected a status attribute, got %T", s.Attributes["status"])
+	}
+	for _, k := range resourceAttributes {
+		if _, ok := s.Attributes[k]; ok {
+			t.Errorf("unexpected attribute %q", k)
+		}
+	}
+}
+
+func TestCustomDataSourceSchemaClusterScoped(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomDataSourceSchema(t, NewCustomDataSource("v1", "example.com", names, v1.ClusterScoped, testCRDSchema(), schemaOptions{}))
+
+	md := s.Attributes["metadata"].(schema.SingleNestedAttribute)
+	if _, ok := md.Attributes["namespace"]; ok {
+		t.Error("unexpected namespace attribute for a cluster-scoped kind")
+	}
+}
+
+func TestCustomListDataSourceSchema(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomDataSourceSchema(t, NewCustomListDataSource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}))
+
+	for _, k := range []string{"namespace", "label_selector", "field_selector", "limit", "wait_for"} {
+		if a, ok := s.Attributes[k]; !ok || !a.IsOptional() {
+			t.Errorf("expected an optional %q attribute", k)
+		}
+	}
+	items, ok := s.Attributes["items"].(schema.ListNestedAttribute)
+	if !ok {
+		t.Fatalf("expected an items attribute, got %T", s.Attributes["items"])
+	}
+	if !items.IsComputed() {
+		t.Error("expected items to be computed")
+	}
+	md, ok := items.NestedObject.Attributes["metadata"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a metadata attribute, got %T", items.NestedObject.Attributes["metadata"])
+	}
+	if !md.Attributes["name"].IsComputed() || md.Attributes["name"].IsOptional() {
+		t.Error("expected the item name to be computed")
+	}
+	if _, ok := items.NestedObject.Attributes["spec"].(schema.SingleNestedAttribute); !ok {
+		t.Errorf("expected a spec attribute, got %T", items.NestedObject.Attributes["spec"])
+	}
+}
+
+func TestCustomManifestDataSourceSchema(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomDataSourceSchema(t, NewCustomManifestDataSource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}))
+
+	sp, ok := s.Attributes["spec"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a spec attribute, got %T", s.Attributes["spec"])
+	}
+	if !sp.Attributes["replicas"].IsRequired() || !sp.Attributes["image"].IsOptional() {
+		t.Error("unexpected spec attribute flags")
+	}
+	md, ok := s.Attributes["metadata"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a metadata attribute, got %T", s.Attributes["metadata"])
+	}
+	if n := md.Attributes["name"]; !n.IsOptional() || n.IsComputed() {
+		t.Error("expected the name to be optional")
+	}
+	if _, ok := s.Attributes["status"]; ok {
+		t.Error("unexpected status attribute")
+	}
+	for _, k := range []string{"yaml", "json"} {
+		if a, ok := s.Attributes[k]; !ok || !a.IsComputed() || a.IsSensitive() {
+			t.Errorf("expected a computed %q attribute", k)
+		}
+	}
+}
+
+func TestCustomManifestDataSourceSchemaSensitive(t *testing.T) {
+	t.Setenv("CRD_SENSITIVE_ATTRIBUTES", "spec.image")
+	opts, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomDataSourceSchema(t, NewCustomManifestDataSource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), opts))
+
+	if !s.Attributes["yaml"].IsSensitive() || !s.Attributes["json"].IsSensitive() {
+		t.Error("expected the manifests to be sensitive")
+	}
+}
+
+func TestJSONPathValues(t *testing.T) {
+	obj := map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase": "Ready",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Synced", "status": "False"},
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}
+	exprs := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"phase":   tftypes.NewValue(tftypes.String, "{.status.phase}"),
+		"ready":   tftypes.NewValue(tftypes.String, `.status.conditions[?(@.type=="Ready")].status`),
+		"missing": tftypes.NewValue(tftypes.String, "{.status.endpoint}"),
+	})
+	v, err := jsonPathValues(exprs, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results map[string]tftypes.Value
+	if err := v.As(&results); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"phase": "Ready", "ready": "True", "missing": ""}
+	for n, w := range want {
+		var got string
+		if err := results[n].As(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != w {
+			t.Errorf("%s: expected %q, got %q", n, w, got)
+		}
+	}
+
+	null, err := jsonPathValues(tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil), obj)
+	if err != nil || !null.IsNull() {
+		t.Errorf("expected a null result without expressions, got %v (%v)", null, err)
+	}
+	invalid := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"bad": tftypes.NewValue(tftypes.String, "{.status[}"),
+	})
+	if _, err := jsonPathValues(invalid, obj); err == nil {
+		t.Error("expected an error for an invalid expression")
+	}
+}
+
+func TestCustomStatusDataSourceSchema(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomDataSourceSchema(t, NewCustomStatusDataSource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}))
+
+	if _, ok := s.Attributes["status"].(schema.SingleNestedAttribute); !ok {
+		t.Errorf("expected a status attribute, got %T", s.Attributes["status"])
+	}
+	hw, ok := s.Attributes["healthy_when"].(schema.SingleNestedAttribute)
+	if !ok || !hw.IsRequired() {
+		t.Fatalf("expected a required healthy_when attribute, got %T", s.Attributes["healthy_when"])
+	}
+	if _, ok := hw.Attributes["interval"]; !ok {
+		t.Error("expected an interval attribute")
+	}
+	if !s.Attributes["healthy"].IsComputed() {
+		t.Error("expected healthy to be computed")
+	}
+	if _, ok := s.Attributes["spec"]; ok {
+		t.Error("unexpected spec attribute")
+	}
+}
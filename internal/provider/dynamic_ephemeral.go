@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ ephemeral.EphemeralResource = &CustomEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &CustomEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithRenew = &CustomEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &CustomEphemeralResource{}
+
+const (
+	// renewedAtAnnotation records when an ephemeral object was last renewed,
+	// so that controllers can tell objects still in use from abandoned ones.
+	renewedAtAnnotation = "terraform-provider-crd/renewed-at"
+	// ephemeralRenewInterval is how often ephemeral objects are renewed while
+	// Terraform runs.
+	ephemeralRenewInterval = 5 * time.Minute
+)
+
+func NewCustomEphemeralResource(v string, g string, n v1.CustomResourceDefinitionNames, scope v1.ResourceScope, s *spec.Schema, opts schemaOptions) ephemeral.EphemeralResource {
+	return &CustomEphemeralResource{
+		resource: NewCustomResource(v, g, n, scope, s, opts).(*CustomResource),
+	}
+}
+
+// CustomEphemeralResource creates an object of a custom resource kind for the
+// duration of a Terraform run and deletes it afterwards. Nothing is stored in
+// the state.
+type CustomEphemeralResource struct {
+	resource *CustomResource
+}
+
+func (e *CustomEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + e.resource.name
+}
+
+func (e *CustomEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	rs := &resource.SchemaResponse{}
+	e.resource.Schema(ctx, resource.SchemaRequest{}, rs)
+	resp.Diagnostics.Append(rs.Diagnostics...)
+
+	attrs := make(map[string]schema.Attribute, len(rs.Schema.Attributes))
+	for n, a := range rs.Schema.Attributes {
+		// Ephemeral objects are created directly rather than applied, and
+		// deleted when Terraform is done with them, so waiting is all they
+		// share with resources.
+		if n != "wait" && containsString(resourceAttributes, n) {
+			continue
+		}
+		// The objects they create are short-lived, and aren't read back.
+		if n == "object" && e.resource.hasLiveObject() {
+			continue
+		}
+		attrs[n] = ephemeralAttribute(a)
+	}
+	md := attrs["metadata"].(schema.SingleNestedAttribute)
+	md.Attributes["uid"] = schema.StringAttribute{
+		MarkdownDescription: "Unique identifier of the object, set by the API server.",
+		Computed:            true,
+	}
+	attrs["metadata"] = md
+	resp.Schema.Description = e.resource.description("Creates short-lived")
+	resp.Schema.MarkdownDescription = e.resource.markdownDescription("Creates short-lived")
+	resp.Schema.Attributes = attrs
+}
+
+func (e *CustomEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	e.resource.clients = pd.Clients
+	e.resource.fieldManager = pd.FieldManager
+	e.resource.commonLabels = pd.CommonLabels
+	e.resource.commonAnnotations = pd.CommonAnnotations
+	e.resource.operationTimeout = pd.OperationTimeout
+}
+
+func (e *CustomEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	r := e.resource
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+	obj, err := r.objectFromValue(req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	applied := r.withCommonMetadata(obj)
+	applied.SetAnnotations(mergeStringMaps(applied.GetAnnotations(), map[string]string{
+		renewedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+	}))
+
+	rc := r.resourceClient(obj)
+	live, err := rc.Create(ctx, applied, r.fieldManager.createOptions())
+	if err != nil {
+		resp.Diagnostics.Append(applyErrorDiagnostic("create", obj, err))
+		return
+	}
+	// Close only needs to know which object to delete.
+	ref := &unstructured.Unstructured{}
+	ref.SetName(live.GetName())
+	ref.SetNamespace(live.GetNamespace())
+	resp.Diagnostics.Append(setLastApplied(ctx, resp.Private, ref.Object)...)
+
+	waited, diags := r.waitFor(ctx, req.Config, rc, live.GetName())
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		resp.Diagnostics.Append(e.discard(ctx, rc, live.GetName())...)
+		return
+	}
+	if waited != nil {
+		live = waited
+	}
+
+	v, err := r.stateFromObject(live.Object, req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert object", err.Error())
+		resp.Diagnostics.Append(e.discard(ctx, rc, live.GetName())...)
+		return
+	}
+	resp.Result.Raw = v
+	resp.RenewAt = time.Now().Add(ephemeralRenewInterval)
+}
+
+// discard deletes the object named name, which Open created but failed to
+// open. Terraform only closes the ephemeral resources it opened, so the object
+// would be left behind otherwise. ctx may be done already, when waiting for
+// the object timed out, so the deletion gets a deadline of its own.
+func (e *CustomEphemeralResource) discard(ctx context.Context, rc dynamic.ResourceInterface, name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interruptedLookupTimeout)
+	defer cancel()
+	err := rc.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to delete %s %q, which failed to open, got error: %s", e.resource.gvk.Kind, name, err))
+	}
+	return diags
+}
+
+func (e *CustomEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	ref, diags := ephemeralObject(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if ref == nil {
+		return
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{renewedAtAnnotation: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build patch", err.Error())
+		return
+	}
+	r := e.resource
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+	_, err = r.resourceClient(ref).Patch(ctx, ref.GetName(), types.MergePatchType, patch, metav1.PatchOptions{FieldManager: r.fieldManager.name})
+	if apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Object Not Found", fmt.Sprintf("%s %q was deleted before Terraform was done with it.", r.gvk.Kind, ref.GetName()))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to renew %s %q, got error: %s", r.gvk.Kind, ref.GetName(), err))
+		return
+	}
+	resp.RenewAt = time.Now().Add(ephemeralRenewInterval)
+}
+
+func (e *CustomEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	ref, diags := ephemeralObject(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if ref == nil {
+		return
+	}
+	r := e.resource
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+	err := r.resourceClient(ref).Delete(ctx, ref.GetName(), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete %s %q, got error: %s", r.gvk.Kind, ref.GetName(), err))
+	}
+}
+
+// ephemeralObject returns the name and namespace of the object opened by an
+// ephemeral resource, as recorded in its private data.
+func ephemeralObject(ctx context.Context, p privateState) (*unstructured.Unstructured, diag.Diagnostics) {
+	la, diags := getLastApplied(ctx, p)
+	if diags.HasError() || la == nil {
+		return nil, diags
+	}
+	ref := &unstructured.Unstructured{}
+	if err := json.Unmarshal(la, &ref.Object); err != nil {
+		diags.AddError("Failed to read private data", err.Error())
+		return nil, diags
+	}
+	return ref, diags
+}
+
+// ephemeralAttribute returns the ephemeral resource attribute matching the
+// resource attribute a. Plan modifiers and defaults have no equivalent.
+func ephemeralAttribute(a rschema.Attribute) schema.Attribute {
+	switch a := a.(type) {
+	case rschema.StringAttribute:
+		return schema.StringAttribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.BoolAttribute:
+		return schema.BoolAttribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.Int32Attribute:
+		return schema.Int32Attribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.Int64Attribute:
+		return schema.Int64Attribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.Float32Attribute:
+		return schema.Float32Attribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.Float64Attribute:
+		return schema.Float64Attribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.DynamicAttribute:
+		return schema.DynamicAttribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.ListAttribute:
+		return schema.ListAttribute{ElementType: a.ElementType, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.SetAttribute:
+		return schema.SetAttribute{ElementType: a.ElementType, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.MapAttribute:
+		return schema.MapAttribute{ElementType: a.ElementType, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.SingleNestedAttribute:
+		return schema.SingleNestedAttribute{Attributes: ephemeralNestedAttributes(a.Attributes), CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.ListNestedAttribute:
+		return schema.ListNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: ephemeralNestedAttributes(a.NestedObject.Attributes)}, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.SetNestedAttribute:
+		return schema.SetNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: ephemeralNestedAttributes(a.NestedObject.Attributes)}, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	case rschema.MapNestedAttribute:
+		return schema.MapNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: ephemeralNestedAttributes(a.NestedObject.Attributes)}, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Computed: a.Computed, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}
+	}
+	return schema.DynamicAttribute{Optional: true, Description: a.GetDescription(), MarkdownDescription: a.GetMarkdownDescription()}
+}
+
+func ephemeralNestedAttributes(attrs map[string]rschema.Attribute) map[string]schema.Attribute {
+	ea := make(map[string]schema.Attribute, len(attrs))
+	for n, a := range attrs {
+		ea[n] = ephemeralAttribute(a)
+	}
+	return ea
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestCustomEphemeralResourceSchema(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	e := NewCustomEphemeralResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{})
+	resp := &ephemeral.SchemaResponse{}
+	e.Schema(context.Background(), ephemeral.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	s := resp.Schema
+
+	md, ok := s.Attributes["metadata"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a metadata attribute, got %T", s.Attributes["metadata"])
+	}
+	if !md.Attributes["namespace"].IsRequired() || !md.Attributes["uid"].IsComputed() {
+		t.Error("unexpected metadata attribute flags")
+	}
+	sp, ok := s.Attributes["spec"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a spec attribute, got %T", s.Attributes["spec"])
+	}
+	if !sp.Attributes["replicas"].IsRequired() || !sp.Attributes["image"].IsOptional() {
+		t.Error("unexpected spec attribute flags")
+	}
+	if st := s.Attributes["status"]; st == nil || !st.IsComputed() {
+		t.Error("expected status to be computed")
+	}
+	if _, ok := s.Attributes["wait"]; !ok {
+		t.Error("expected a wait attribute")
+	}
+	for _, k := range []string{"field_manager", "timeouts", "triggers", "prevent_deletion", "delete_strategy", "allow_adoption"} {
+		if _, ok := s.Attributes[k]; ok {
+			t.Errorf("unexpected attribute %q", k)
+		}
+	}
+}
+
+func TestSchemaOptionsFromEnvEphemeral(t *testing.T) {
+	t.Setenv("CRD_EPHEMERAL_RESOURCES", "widgets.example.com, gadgets.example.com")
+	opts, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.ephemeral["widgets.example.com"] || !opts.ephemeral["gadgets.example.com"] || len(opts.ephemeral) != 2 {
+		t.Errorf("unexpected ephemeral resources: %v", opts.ephemeral)
+	}
+}
+
+func TestCustomEphemeralResourceOpenWaitFailed(t *testing.T) {
+	ctx := context.Background()
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	e := NewCustomEphemeralResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomEphemeralResource)
+	gvr := rtschema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[rtschema.GroupVersionResource]string{gvr: "WidgetList"})
+	e.resource.clients = &KubernetesClients{Dynamic: client}
+	sresp := &ephemeral.SchemaResponse{}
+	e.Schema(ctx, ephemeral.SchemaRequest{}, sresp)
+
+	typ := sresp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	object := func(t tftypes.Object, set map[string]tftypes.Value) tftypes.Value {
+		av := make(map[string]tftypes.Value, len(t.AttributeTypes))
+		for n, at := range t.AttributeTypes {
+			av[n] = tftypes.NewValue(at, nil)
+		}
+		for n, v := range set {
+			av[n] = v
+		}
+		return tftypes.NewValue(t, av)
+	}
+	wt := typ.AttributeTypes["wait"].(tftypes.Object)
+	config := object(typ, map[string]tftypes.Value{
+		"metadata": object(typ.AttributeTypes["metadata"].(tftypes.Object), map[string]tftypes.Value{
+			"name":      tftypes.NewValue(tftypes.String, "test"),
+			"namespace": tftypes.NewValue(tftypes.String, "default"),
+		}),
+		"spec": object(typ.AttributeTypes["spec"].(tftypes.Object), map[string]tftypes.Value{
+			"replicas": tftypes.NewValue(tftypes.Number, 1),
+		}),
+		"wait": object(wt, map[string]tftypes.Value{
+			"conditions": tftypes.NewValue(wt.AttributeTypes["conditions"], map[string]tftypes.Value{
+				"Ready": tftypes.NewValue(tftypes.String, "True"),
+			}),
+			"timeout":  tftypes.NewValue(tftypes.String, "100ms"),
+			"interval": tftypes.NewValue(tftypes.String, "10ms"),
+		}),
+	})
+
+	resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: sresp.Schema}}
+	e.Open(ctx, ephemeral.OpenRequest{Config: tfsdk.Config{Schema: sresp.Schema, Raw: config}}, resp)
+	if !hasDiagnostic(resp.Diagnostics, "Wait Failed") {
+		t.Fatalf("expected the wait to fail, got %v", resp.Diagnostics)
+	}
+	_, err := client.Resource(gvr).Namespace("default").Get(ctx, "test", metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected the object to be deleted, got %v", err)
+	}
+}
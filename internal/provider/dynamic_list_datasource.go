@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ datasource.DataSource = &CustomListDataSource{}
+var _ datasource.DataSourceWithConfigure = &CustomListDataSource{}
+
+func NewCustomListDataSource(v string, g string, n v1.CustomResourceDefinitionNames, scope v1.ResourceScope, s *spec.Schema, opts schemaOptions) datasource.DataSource {
+	return &CustomListDataSource{
+		resource: NewCustomResource(v, g, n, scope, s, opts).(*CustomResource),
+	}
+}
+
+// CustomListDataSource lists the objects of a custom resource kind matching
+// the selectors in its configuration.
+type CustomListDataSource struct {
+	resource *CustomResource
+}
+
+type customListDataSourceModel struct {
+	Namespace     types.String `tfsdk:"namespace"`
+	LabelSelector types.String `tfsdk:"label_selector"`
+	FieldSelector types.String `tfsdk:"field_selector"`
+	Limit         types.Int64  `tfsdk:"limit"`
+}
+
+func (d *CustomListDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.resource.name + "_list"
+}
+
+func (d *CustomListDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	item, diags := d.resource.dataSourceAttributes(ctx)
+	resp.Diagnostics.Append(diags...)
+	md := dataSourceAttribute(metadataAttribute(d.resource.namespaced)).(schema.SingleNestedAttribute)
+	md.Attributes["uid"] = dataSourceUIDAttribute
+	item["metadata"] = md
+
+	attrs := map[string]schema.Attribute{
+		"label_selector": schema.StringAttribute{
+			MarkdownDescription: "Label selector restricting the listed objects, such as `app=web,tier!=cache`.",
+			Optional:            true,
+		},
+		"field_selector": schema.StringAttribute{
+			MarkdownDescription: "Field selector restricting the listed objects, such as `metadata.name=example`.",
+			Optional:            true,
+		},
+		"limit": schema.Int64Attribute{
+			MarkdownDescription: "Maximum number of objects to list. All matching objects are listed when not set.",
+			Optional:            true,
+		},
+		"wait_for": waitForAttribute("Block until at least one object matching the selectors satisfies all of the given criteria. Only the objects satisfying them are listed."),
+		"items": schema.ListNestedAttribute{
+			MarkdownDescription: "Objects matching the selectors, ordered by namespace and name.",
+			Computed:            true,
+			NestedObject:        schema.NestedAttributeObject{Attributes: item},
+		},
+	}
+	if d.resource.namespaced {
+		attrs["namespace"] = schema.StringAttribute{
+			MarkdownDescription: "Namespace to list objects in. Objects of all namespaces are listed when not set.",
+			Optional:            true,
+		}
+	}
+	resp.Schema.Description = d.resource.description("Lists")
+	resp.Schema.MarkdownDescription = d.resource.markdownDescription("Lists")
+	resp.Schema.Attributes = attrs
+}
+
+func (d *CustomListDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.resource.clients = pd.Clients
+	d.resource.operationTimeout = pd.OperationTimeout
+}
+
+func (d *CustomListDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data customListDataSourceModel
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("label_selector"), &data.LabelSelector)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("field_selector"), &data.FieldSelector)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("limit"), &data.Limit)...)
+	if d.resource.namespaced {
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("namespace"), &data.Namespace)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Limit.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("limit"), "Invalid Limit", "The limit can't be negative.")
+		return
+	}
+
+	wc, diags := waitCriteriaFrom(ctx, req.Config, path.Root("wait_for"))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r := d.resource
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+	gvr := r.gvk.GroupVersion().WithResource(r.plural)
+	rc := r.clients.Dynamic.Resource(gvr).Namespace(data.Namespace.ValueString())
+	opts := metav1.ListOptions{
+		LabelSelector: data.LabelSelector.ValueString(),
+		FieldSelector: data.FieldSelector.ValueString(),
+	}
+	var objs []unstructured.Unstructured
+	if wc == nil {
+		opts.Limit = data.Limit.ValueInt64()
+		var err error
+		objs, err = listObjects(ctx, rc, opts)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list %s objects, got error: %s", r.gvk.Kind, err))
+			return
+		}
+	} else {
+		var err error
+		objs, err = waitForObjects(ctx, func(ctx context.Context) ([]unstructured.Unstructured, error) {
+			return listObjects(ctx, rc, opts)
+		}, wc)
+		if err != nil {
+			resp.Diagnostics.AddError("Wait Failed", fmt.Sprintf("Waiting for %s objects failed: %s", r.gvk.Kind, err))
+			return
+		}
+		// Objects are matched against the criteria before the limit applies.
+		if l := data.Limit.ValueInt64(); l > 0 && int64(len(objs)) > l {
+			objs = objs[:l]
+		}
+	}
+
+	ct := resp.State.Raw.Type().(tftypes.Object)
+	et := ct.AttributeTypes["items"].(tftypes.List).ElementType
+	items := make([]tftypes.Value, 0, len(objs))
+	for _, obj := range objs {
+		v, err := valueFromObject(r.schema, et, obj.Object)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to convert object", fmt.Sprintf("%s %q: %s", r.gvk.Kind, obj.GetName(), err))
+			return
+		}
+		items = append(items, v)
+	}
+
+	var state map[string]tftypes.Value
+	if err := req.Config.Raw.As(&state); err != nil {
+		resp.Diagnostics.AddError("Failed to read configuration", err.Error())
+		return
+	}
+	state["items"] = tftypes.NewValue(tftypes.List{ElementType: et}, items)
+	resp.State.Raw = tftypes.NewValue(ct, state)
+}
+
+// listObjects lists the objects of rc matching opts. A limit in opts caps the
+// number of objects rather than the page size.
+func listObjects(ctx context.Context, rc dynamic.ResourceInterface, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	var objs []unstructured.Unstructured
+	for {
+		list, err := rc.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, list.Items...)
+		if opts.Limit > 0 || list.GetContinue() == "" {
+			return objs, nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/yaml"
+)
+
+var _ datasource.DataSource = &CustomManifestDataSource{}
+var _ datasource.DataSourceWithConfigure = &CustomManifestDataSource{}
+
+func NewCustomManifestDataSource(v string, g string, n v1.CustomResourceDefinitionNames, scope v1.ResourceScope, s *spec.Schema, opts schemaOptions) datasource.DataSource {
+	return &CustomManifestDataSource{
+		resource: NewCustomResource(v, g, n, scope, s, opts).(*CustomResource),
+	}
+}
+
+// CustomManifestDataSource renders the manifest of an object of a custom
+// resource kind without applying it. It takes the same attributes as the
+// resource of the kind, so that manifests handed over to other tools, such as
+// GitOps repositories, can be authored the same way.
+type CustomManifestDataSource struct {
+	resource *CustomResource
+}
+
+func (d *CustomManifestDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.resource.name + "_manifest"
+}
+
+func (d *CustomManifestDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	rs := &resource.SchemaResponse{}
+	d.resource.Schema(ctx, resource.SchemaRequest{}, rs)
+	resp.Diagnostics.Append(rs.Diagnostics...)
+
+	attrs := make(map[string]schema.Attribute, len(rs.Schema.Attributes)+2)
+	sensitive := false
+	for n, a := range rs.Schema.Attributes {
+		if containsString(resourceAttributes, n) {
+			continue
+		}
+		if ca, ok := configAttribute(a); ok {
+			attrs[n] = ca
+			sensitive = sensitive || hasSensitiveAttribute(ca)
+		}
+	}
+	// The manifests hold the values of sensitive attributes as they are.
+	attrs["yaml"] = schema.StringAttribute{
+		MarkdownDescription: "Manifest of the object in YAML.",
+		Computed:            true,
+		Sensitive:           sensitive,
+	}
+	attrs["json"] = schema.StringAttribute{
+		MarkdownDescription: "Manifest of the object in JSON.",
+		Computed:            true,
+		Sensitive:           sensitive,
+	}
+	resp.Schema.Description = d.resource.description("Renders the manifest of")
+	resp.Schema.MarkdownDescription = d.resource.markdownDescription("Renders the manifest of")
+	resp.Schema.Attributes = attrs
+}
+
+func (d *CustomManifestDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.resource.commonLabels = pd.CommonLabels
+	d.resource.commonAnnotations = pd.CommonAnnotations
+}
+
+func (d *CustomManifestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	obj, err := d.resource.objectFromValue(req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	obj = d.resource.withCommonMetadata(obj)
+
+	j, err := json.MarshalIndent(obj.Object, "", "  ")
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to render manifest", err.Error())
+		return
+	}
+	y, err := yaml.JSONToYAML(j)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to render manifest", err.Error())
+		return
+	}
+
+	var state map[string]tftypes.Value
+	if err := req.Config.Raw.As(&state); err != nil {
+		resp.Diagnostics.AddError("Failed to read configuration", err.Error())
+		return
+	}
+	state["json"] = tftypes.NewValue(tftypes.String, string(j))
+	state["yaml"] = tftypes.NewValue(tftypes.String, string(y))
+	resp.State.Raw = tftypes.NewValue(req.Config.Raw.Type(), state)
+}
+
+// configAttribute returns the data source attribute taking the configuration
+// of the resource attribute a, or false if a is only set by the API server.
+// Data sources have no defaults, so optional attributes aren't computed.
+func configAttribute(a rschema.Attribute) (schema.Attribute, bool) {
+	if a.IsComputed() && !a.IsOptional() {
+		return nil, false
+	}
+	switch a := a.(type) {
+	case rschema.StringAttribute:
+		return schema.StringAttribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.BoolAttribute:
+		return schema.BoolAttribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.Int32Attribute:
+		return schema.Int32Attribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.Int64Attribute:
+		return schema.Int64Attribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.Float32Attribute:
+		return schema.Float32Attribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.Float64Attribute:
+		return schema.Float64Attribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.DynamicAttribute:
+		return schema.DynamicAttribute{CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.ListAttribute:
+		return schema.ListAttribute{ElementType: a.ElementType, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.SetAttribute:
+		return schema.SetAttribute{ElementType: a.ElementType, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.MapAttribute:
+		return schema.MapAttribute{ElementType: a.ElementType, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.SingleNestedAttribute:
+		return schema.SingleNestedAttribute{Attributes: configAttributes(a.Attributes), CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.ListNestedAttribute:
+		return schema.ListNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: configAttributes(a.NestedObject.Attributes)}, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.SetNestedAttribute:
+		return schema.SetNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: configAttributes(a.NestedObject.Attributes)}, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	case rschema.MapNestedAttribute:
+		return schema.MapNestedAttribute{NestedObject: schema.NestedAttributeObject{Attributes: configAttributes(a.NestedObject.Attributes)}, CustomType: a.CustomType, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description, MarkdownDescription: a.MarkdownDescription, Validators: a.Validators}, true
+	}
+	return schema.DynamicAttribute{Optional: true, Description: a.GetDescription(), MarkdownDescription: a.GetMarkdownDescription()}, true
+}
+
+func configAttributes(attrs map[string]rschema.Attribute) map[string]schema.Attribute {
+	ca := make(map[string]schema.Attribute, len(attrs))
+	for n, a := range attrs {
+		if c, ok := configAttribute(a); ok {
+			ca[n] = c
+		}
+	}
+	return ca
+}
+
+// hasSensitiveAttribute reports whether a or any of its nested attributes is
+// sensitive.
+func hasSensitiveAttribute(a schema.Attribute) bool {
+	if a.IsSensitive() {
+		return true
+	}
+	var nested map[string]schema.Attribute
+	switch a := a.(type) {
+	case schema.SingleNestedAttribute:
+		nested = a.Attributes
+	case schema.ListNestedAttribute:
+		nested = a.NestedObject.Attributes
+	case schema.SetNestedAttribute:
+		nested = a.NestedObject.Attributes
+	case schema.MapNestedAttribute:
+		nested = a.NestedObject.Attributes
+	}
+	for _, na := range nested {
+		if hasSensitiveAttribute(na) {
+			return true
+		}
+	}
+	return false
+}
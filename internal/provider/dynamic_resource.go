@@ -2,37 +2,91 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
-	"github.com/stoewer/go-strcase"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &CustomResource{}
+var _ resource.ResourceWithConfigure = &CustomResource{}
+var _ resource.ResourceWithImportState = &CustomResource{}
+var _ resource.ResourceWithModifyPlan = &CustomResource{}
 
-var skipAttributes = map[string]interface{}{"kind": nil, "apiVersion": nil, "status": nil}
+var skipAttributes = map[string]interface{}{"kind": nil, "apiVersion": nil, "metadata": nil}
 
-// var _ resource.ResourceWithImportState = &CustomResource{}
+// resourceAttributes are provider-defined attributes which don't map to
+// fields of the Kubernetes object.
+var resourceAttributes = []string{"wait", "timeouts", "field_manager", "ignore_fields", "triggers", "prevent_deletion", "delete_strategy", "allow_adoption"}
 
-func NewCustomResource(v string, g string, n v1.CustomResourceDefinitionNames, s *spec.Schema) resource.Resource {
+func NewCustomResource(v string, g string, n v1.CustomResourceDefinitionNames, scope v1.ResourceScope, s *spec.Schema, opts schemaOptions) resource.Resource {
 	return &CustomResource{
-		name:   resourceName(v, g, n.Singular),
-		schema: s,
+		name:       opts.resourceName(v, g, n),
+		gvk:        rtschema.GroupVersionKind{Group: g, Version: v, Kind: n.Kind},
+		plural:     n.Plural,
+		namespaced: scope == v1.NamespaceScoped,
+		schema:     withObjectMeta(withSchemalessSpec(s)),
+		options:    opts,
 	}
 }
 
+// withSchemalessSpec returns a copy of s with a spec property accepting any
+// value when s preserves unknown fields at its root without declaring a spec.
+// The root of the schema holds provider-defined attributes as well, so it
+// can't be dynamic itself. Other schemas are returned as they are.
+func withSchemalessSpec(s *spec.Schema) *spec.Schema {
+	if !isSchemaless(s) {
+		return s
+	}
+	ws := *s
+	ws.Extensions = make(spec.Extensions, len(s.Extensions))
+	for k, v := range s.Extensions {
+		if k != "x-kubernetes-preserve-unknown-fields" {
+			ws.Extensions[k] = v
+		}
+	}
+	if _, ok := s.Properties["spec"]; !ok {
+		ws.Properties = make(map[string]spec.Schema, len(s.Properties)+1)
+		for k, p := range s.Properties {
+			ws.Properties[k] = p
+		}
+		ws.Properties["spec"] = *unknownFieldsSchema("Specification of the object.")
+	}
+	return &ws
+}
+
 // CustomResource defines the resource implementation.
 type CustomResource struct {
-	name   string
-	schema *spec.Schema
+	name       string
+	gvk        rtschema.GroupVersionKind
+	plural     string
+	namespaced bool
+	schema     *spec.Schema
+	options    schemaOptions
+	clients    *KubernetesClients
+
+	fieldManager      fieldManager
+	serverDryRun      bool
+	operationTimeout  time.Duration
+	commonLabels      map[string]string
+	commonAnnotations map[string]string
 }
 
 func (r *CustomResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -40,36 +94,812 @@ func (r *CustomResource) Metadata(ctx context.Context, req resource.MetadataRequ
 }
 
 func (r *CustomResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	ctx = r.logContext(ctx, schemaSubsystem)
+	start := time.Now()
 	attr := make(map[string]schema.Attribute)
 	rqat := make(map[string]bool)
 	for _, r := range r.schema.Required {
 		rqat[r] = true
 	}
+	names := attributeNames(r.schema)
+	for _, d := range attributeNameDiagnostics(path.Empty(), names) {
+		logSchemaDiagnostic(ctx, d)
+		resp.Diagnostics.Append(r.schemaDiagnostic(d))
+	}
 	for k, v := range r.schema.Properties {
 		if _, ok := skipAttributes[k]; ok {
 			continue
 		}
-		_, rq := rqat[k]
-		av := attributeFromOAPI(&v, rq)
+		m := optionalAttribute.nested(rqat[k])
+		if k == "status" {
+			// Status is written by controllers and only ever read back.
+			m = computedAttribute
+		}
+		n := names[k]
+		av, diags := attributeFromOAPI(&v, path.Root(n), m)
+		for _, d := range diags {
+			logSchemaDiagnostic(ctx, d)
+			resp.Diagnostics.Append(r.schemaDiagnostic(d))
+		}
 		if av == nil {
 			continue
 		}
-		attr[strcase.SnakeCase(k)] = av
+		attr[n] = av
+	}
+	withSensitivePaths(attr, path.Empty(), r.options.sensitive)
+	for _, d := range withWriteOnlyPaths(attr, path.Empty(), r.options.writeOnly) {
+		logSchemaDiagnostic(ctx, d)
+		resp.Diagnostics.Append(r.schemaDiagnostic(d))
+	}
+	for _, d := range withComputedPaths(attr, path.Empty(), r.options.computed) {
+		logSchemaDiagnostic(ctx, d)
+		resp.Diagnostics.Append(r.schemaDiagnostic(d))
 	}
-	resp.Schema.Version = 1
+	attr["metadata"] = metadataAttribute(r.namespaced)
+	attr["wait"] = waitAttribute()
+	attr["timeouts"] = timeoutsAttribute()
+	attr["field_manager"] = fieldManagerAttribute()
+	attr["ignore_fields"] = ignoreFieldsAttribute()
+	attr["triggers"] = triggersAttribute()
+	attr["prevent_deletion"] = preventDeletionAttribute()
+	attr["delete_strategy"] = deleteStrategyAttribute()
+	attr["allow_adoption"] = allowAdoptionAttribute()
+	if r.hasLiveObject() {
+		attr["object"] = liveObjectAttribute(hasSensitive(attr))
+	}
+	tflog.SubsystemDebug(ctx, schemaSubsystem, "Generated resource schema", map[string]interface{}{
+		"attributes": len(attr),
+		"latency_ms": time.Since(start).Milliseconds(),
+	})
+	resp.Schema.Version = schemaVersion
+	resp.Schema.Description = r.description("Manages")
+	resp.Schema.MarkdownDescription = r.markdownDescription("Manages")
 	resp.Schema.Attributes = attr
 }
 
+// description returns the description of a resource or data source of the
+// kind, whose action is a verb such as "Manages": the description of the CRD
+// schema, followed by the kind of object it acts on.
+func (r *CustomResource) description(action string) string {
+	d := fmt.Sprintf("%s %s objects of API version %s.", action, r.gvk.Kind, r.gvk.GroupVersion())
+	if r.schema.Description == "" {
+		return d
+	}
+	return r.schema.Description + "\n\n" + d
+}
+
+func (r *CustomResource) markdownDescription(action string) string {
+	d := fmt.Sprintf("%s `%s` objects of API version `%s`.", action, r.gvk.Kind, r.gvk.GroupVersion())
+	if r.schema.Description == "" {
+		return d
+	}
+	return r.schema.Description + "\n\n" + d
+}
+
+// schemaDiagnostic qualifies a diagnostic raised while converting the schema
+// with the custom resource it concerns.
+func (r *CustomResource) schemaDiagnostic(d diag.Diagnostic) diag.Diagnostic {
+	detail := fmt.Sprintf("%s (%s): %s", r.gvk.Kind, r.gvk.GroupVersion(), d.Detail())
+	if d.Severity() == diag.SeverityError {
+		return diag.NewErrorDiagnostic(d.Summary(), detail)
+	}
+	return diag.NewWarningDiagnostic(d.Summary(), detail)
+}
+
+func (r *CustomResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.clients = pd.Clients
+	r.fieldManager = pd.FieldManager
+	r.commonLabels = pd.CommonLabels
+	r.commonAnnotations = pd.CommonAnnotations
+	r.serverDryRun = pd.ServerDryRun
+	r.operationTimeout = pd.OperationTimeout
+}
+
+// ModifyPlan fails plans which delete protected objects, and submits the
+// planned object to the API server as a dry run, so that admission and
+// validation failures surface during plan.
+func (r *CustomResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if !req.State.Raw.IsNull() {
+		prevented, diags := deletionPrevented(ctx, req.State)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		deleted := req.Plan.Raw.IsNull()
+		if prevented && !deleted {
+			deleted, diags = r.replacementPlanned(ctx, req)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		if prevented && deleted {
+			obj, err := r.objectFromValue(req.State.Raw)
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+				return
+			}
+			resp.Diagnostics.Append(r.deletionPreventedDiagnostic(obj))
+			return
+		}
+	}
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	known, err := r.manifestKnown(req.Plan.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to inspect plan", err.Error())
+		return
+	}
+	if !known {
+		// The object can't be validated until all of its values are known.
+		return
+	}
+	obj, err := r.objectFromValue(req.Plan.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(validationRuleDiagnostics(r.schema, obj.Object, path.Empty())...)
+	if resp.Diagnostics.HasError() || r.clients == nil || !r.serverDryRun {
+		return
+	}
+
+	fm, diags := r.fieldManagerFor(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	obj, err = r.appliedObject(obj, req.Plan.Raw, req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	ctx, warnings := withWarnings(ctx)
+	_, err = serverSideApply(ctx, r.resourceClient(obj), obj, fm, metav1.DryRunAll)
+	resp.Diagnostics.Append(warnings.diagnostics(obj)...)
+	if req.ClientCapabilities.DeferralAllowed && prerequisiteAbsent(err) {
+		// The cluster, the definition or the namespace will be created by
+		// another part of the configuration.
+		resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonAbsentPrereq}
+		return
+	}
+	if err != nil && !apierrors.IsUnsupportedMediaType(err) {
+		resp.Diagnostics.Append(applyErrorDiagnostic("validate", obj, err))
+	}
+}
+
 func (r *CustomResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logContext(ctx, crudSubsystem)
+	start := time.Now()
+	defer func() { logOperation(ctx, "create", start, resp.Diagnostics) }()
+
+	timeout, diags := operationTimeout(ctx, req.Plan, "create", r.operationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fm, diags := r.fieldManagerFor(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	obj, err := r.objectFromValue(req.Plan.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	ctx = withObjectFields(ctx, obj)
+	rc := r.resourceClient(obj)
+	// The last applied configuration leaves out common metadata and
+	// write-only values, so that they aren't tracked as part of the resource.
+	applied, err := r.appliedObject(obj, req.Plan.Raw, req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+
+	ctx, warnings := withWarnings(ctx)
+	var live *unstructured.Unstructured
+	if obj.GetName() == "" {
+		// Server-side apply needs a name, so objects named by the API server
+		// are created directly.
+		live, err = rc.Create(ctx, applied, fm.createOptions())
+		if err != nil {
+			resp.Diagnostics.Append(applyErrorDiagnostic("create", obj, err))
+			return
+		}
+		obj.SetName(live.GetName())
+		ctx = withObjectFields(ctx, obj)
+	} else {
+		adopt, diags := adoptionAllowed(ctx, req.Plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Server-side apply would silently take over an existing object.
+		_, err = rc.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		exists := err == nil
+		switch {
+		case exists && !adopt:
+			resp.Diagnostics.AddError(
+				"Resource Already Exists",
+				fmt.Sprintf("%s %q already exists in the cluster. Import it, or set allow_adoption = true, to manage it with Terraform.", r.gvk.Kind, obj.GetName()),
+			)
+			return
+		case exists:
+			tflog.Info(ctx, "Adopting existing object")
+			fm.force = true
+		case !apierrors.IsNotFound(err):
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s %q, got error: %s", r.gvk.Kind, obj.GetName(), err))
+			return
+		}
+
+		live, err = serverSideApply(ctx, rc, applied, fm)
+		if apierrors.IsUnsupportedMediaType(err) {
+			if exists {
+				// Without a last applied configuration, fields missing
+				// from the configuration are left as they are.
+				live, err = threeWayUpdate(ctx, rc, applied, nil, fm)
+			} else {
+				live, err = rc.Create(ctx, applied, fm.createOptions())
+			}
+		}
+		if err != nil && ctx.Err() != nil {
+			// The object may have been created before the operation was
+			// interrupted. It is then recorded, and tainted by the error,
+			// rather than left behind.
+			err = contextError(ctx)
+			live = createdObject(ctx, rc, obj.GetName())
+		}
+		if err != nil {
+			resp.Diagnostics.Append(applyErrorDiagnostic("create", obj, err))
+			if live == nil {
+				return
+			}
+		}
+	}
+	resp.Diagnostics.Append(warnings.diagnostics(obj)...)
+	resp.Diagnostics.Append(setLastApplied(ctx, resp.Private, obj.Object)...)
+
+	if err == nil {
+		waited, diags := r.waitFor(ctx, req.Plan, rc, obj.GetName())
+		resp.Diagnostics.Append(diags...)
+		if waited != nil {
+			live = waited
+		}
+	}
+
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, live)...)
+	resp.Diagnostics.Append(setRecordedUID(ctx, resp.Private, live)...)
+
+	v, err := r.appliedState(req.Plan.Raw, live)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert object", err.Error())
+		return
+	}
+	resp.State.Raw = v
 }
 
 func (r *CustomResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logContext(ctx, crudSubsystem)
+	start := time.Now()
+	defer func() { logOperation(ctx, "read", start, resp.Diagnostics) }()
+
+	timeout, diags := operationTimeout(ctx, req.State, "read", r.operationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ignored, diags := ignoredFields(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fm, diags := r.fieldManagerFor(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	obj, err := r.objectFromValue(req.State.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	ctx = withObjectFields(ctx, obj)
+
+	live, err := r.resourceClient(obj).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if req.ClientCapabilities.DeferralAllowed && clusterUnavailable(err) {
+		resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonAbsentPrereq}
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s %q, got error: %s", r.gvk.Kind, obj.GetName(), err))
+		return
+	}
+	uid, diags := recordedUID(ctx, req.Private, req.Identity)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if replacedObject(uid, live) {
+		resp.Diagnostics.AddWarning("Object Replaced Outside of Terraform", fmt.Sprintf(
+			"%s %q was deleted and recreated outside of Terraform, so it is no longer the object this resource manages. Import it to manage it again.",
+			r.gvk.Kind, obj.GetName()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, live)...)
+	resp.Diagnostics.Append(setRecordedUID(ctx, resp.Private, live)...)
+	returned := live.DeepCopy()
+	owned := ownedFields(live, fm.name)
+	pruneServerFields(live.Object)
+
+	// Only fields present in the last applied configuration are tracked.
+	// Without one, as is the case right after import, the whole object is.
+	// Of those, fields taken over by other field managers are left out.
+	la, diags := getLastApplied(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	lo := live.Object
+	if la != nil {
+		var ref map[string]interface{}
+		if err := json.Unmarshal(la, &ref); err != nil {
+			resp.Diagnostics.AddError("Failed to decode last applied configuration", err.Error())
+			return
+		}
+		lo = pruneObject(alignListMaps(r.schema, lo, ref), ref).(map[string]interface{})
+	} else {
+		pruneUnconfiguredFields(lo)
+	}
+	if owned != nil {
+		lo = ownedObject(lo, owned)
+	}
+	if st, ok := live.Object["status"]; ok {
+		lo["status"] = st
+	}
+
+	v, err := r.stateFromObject(lo, req.State.Raw)
+	if err == nil {
+		v, err = withPriorValues(v, req.State.Raw, ignored)
+	}
+	if err == nil {
+		v, err = r.withLiveObject(v, returned.Object)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert object", err.Error())
+		return
+	}
+	resp.State.Raw = v
 }
 
 func (r *CustomResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logContext(ctx, crudSubsystem)
+	start := time.Now()
+	defer func() { logOperation(ctx, "update", start, resp.Diagnostics) }()
+
+	timeout, diags := operationTimeout(ctx, req.Plan, "update", r.operationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fm, diags := r.fieldManagerFor(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	obj, err := r.objectFromValue(req.Plan.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	ctx = withObjectFields(ctx, obj)
+	rc := r.resourceClient(obj)
+	applied, err := r.appliedObject(obj, req.Plan.Raw, req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	ignored, diags := ignoredFields(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	uid, diags := recordedUID(ctx, req.Private, req.Identity)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(ignored) > 0 || uid != "" {
+		current, err := rc.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s %q, got error: %s", r.gvk.Kind, obj.GetName(), err))
+			return
+		}
+		// Applying would take over the object which replaced the managed one.
+		if replacedObject(uid, current) {
+			resp.Diagnostics.AddError("Object Replaced Outside of Terraform", fmt.Sprintf(
+				"%s %q was deleted and recreated outside of Terraform, so it is no longer the object this resource manages. Refresh the state and plan again.",
+				r.gvk.Kind, obj.GetName()))
+			return
+		}
+		applied = r.withLiveFields(applied, current.Object, ignored)
+	}
+
+	ctx, warnings := withWarnings(ctx)
+	live, err := serverSideApply(ctx, rc, applied, fm)
+	if apierrors.IsUnsupportedMediaType(err) {
+		var original []byte
+		original, err = r.lastApplied(ctx, req)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read last applied configuration", err.Error())
+			return
+		}
+		live, err = threeWayUpdate(ctx, rc, applied, original, fm)
+	}
+	resp.Diagnostics.Append(warnings.diagnostics(obj)...)
+	if err != nil {
+		resp.Diagnostics.Append(applyErrorDiagnostic("update", obj, err))
+		return
+	}
+	resp.Diagnostics.Append(setLastApplied(ctx, resp.Private, obj.Object)...)
+
+	waited, diags := r.waitFor(ctx, req.Plan, rc, obj.GetName())
+	resp.Diagnostics.Append(diags...)
+	if waited != nil {
+		live = waited
+	}
+
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, live)...)
+	resp.Diagnostics.Append(setRecordedUID(ctx, resp.Private, live)...)
+
+	v, err := r.appliedState(req.Plan.Raw, live)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert object", err.Error())
+		return
+	}
+	resp.State.Raw = v
+}
+
+// lastApplied returns the configuration recorded by the previous apply. Without
+// one, the prior state is the best approximation of what was last applied.
+func (r *CustomResource) lastApplied(ctx context.Context, req resource.UpdateRequest) ([]byte, error) {
+	la, diags := getLastApplied(ctx, req.Private)
+	if diags.HasError() {
+		return nil, fmt.Errorf("%s", diags.Errors()[0].Detail())
+	}
+	if la != nil {
+		return la, nil
+	}
+	prior, err := r.objectFromValue(req.State.Raw)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(prior.Object)
 }
 
 func (r *CustomResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logContext(ctx, crudSubsystem)
+	start := time.Now()
+	defer func() { logOperation(ctx, "delete", start, resp.Diagnostics) }()
+
+	timeout, diags := operationTimeout(ctx, req.State, "delete", r.operationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prevented, diags := deletionPrevented(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	strategy, diags := deleteStrategy(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	obj, err := r.objectFromValue(req.State.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build manifest", err.Error())
+		return
+	}
+	ctx = withObjectFields(ctx, obj)
+	if prevented {
+		resp.Diagnostics.Append(r.deletionPreventedDiagnostic(obj))
+		return
+	}
+
+	uid, diags := recordedUID(ctx, req.Private, req.Identity)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	switch strategy {
+	case deleteStrategyAbandon:
+		tflog.Info(ctx, "Abandoning object")
+		return
+	case deleteStrategyOrphan:
+		resp.Diagnostics.Append(r.orphan(ctx, req.State, obj, uid)...)
+		return
+	}
+	var opts metav1.DeleteOptions
+	if uid != "" {
+		// Objects recreated outside of Terraform are left alone.
+		opts.Preconditions = metav1.NewUIDPreconditions(uid)
+	}
+	err = r.resourceClient(obj).Delete(ctx, obj.GetName(), opts)
+	if apierrors.IsConflict(err) && uid != "" {
+		resp.Diagnostics.AddWarning("Object Replaced Outside of Terraform", fmt.Sprintf(
+			"%s %q was deleted and recreated outside of Terraform, so it was left in place.", r.gvk.Kind, obj.GetName()))
+		return
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete %s %q, got error: %s", r.gvk.Kind, obj.GetName(), err))
+	}
+}
+
+// orphan leaves obj, the object of the resource value held by g, in place
+// and gives up the ownership of its fields.
+func (r *CustomResource) orphan(ctx context.Context, g attributeGetter, obj *unstructured.Unstructured, uid string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	fm, d := r.fieldManagerFor(ctx, g)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	rc := r.resourceClient(obj)
+	live, err := rc.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) || (err == nil && replacedObject(uid, live)) {
+		return diags
+	}
+	if err == nil {
+		tflog.Info(ctx, "Orphaning object", map[string]interface{}{"field_manager": fm.name})
+		err = relinquishFields(ctx, rc, live, fm.name)
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to give up the fields of %s %q, got error: %s", r.gvk.Kind, obj.GetName(), err))
+	}
+	return diags
+}
+
+func (r *CustomResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = r.logContext(ctx, crudSubsystem)
+	start := time.Now()
+	defer func() { logOperation(ctx, "import", start, resp.Diagnostics) }()
+
+	var namespace, name, uid string
+	if req.ID == "" && req.Identity != nil {
+		var diags diag.Diagnostics
+		namespace, name, uid, diags = r.importIdentity(ctx, req.Identity)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		var err error
+		namespace, name, err = parseImportID(req.ID, r.namespaced)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+			return
+		}
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(r.gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	ctx = withObjectFields(ctx, obj)
+	live, err := r.resourceClient(obj).Get(ctx, name, metav1.GetOptions{})
+	if req.ClientCapabilities.DeferralAllowed && clusterUnavailable(err) {
+		resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonAbsentPrereq}
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to import %s %q, got error: %s", r.gvk.Kind, name, err))
+		return
+	}
+	if uid != "" && string(live.GetUID()) != uid {
+		resp.Diagnostics.AddError("Object Replaced", fmt.Sprintf("%s %q has UID %s, not %s as in the import identity.", r.gvk.Kind, name, live.GetUID(), uid))
+		return
+	}
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, live)...)
+	resp.Diagnostics.Append(setRecordedUID(ctx, resp.Private, live)...)
+	returned := live.DeepCopy()
+	pruneServerFields(live.Object)
+	pruneUnconfiguredFields(live.Object)
+
+	v, err := valueFromObject(r.schema, resp.State.Schema.Type().TerraformType(ctx), live.Object)
+	if err == nil {
+		v, err = r.withLiveObject(v, returned.Object)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert object", err.Error())
+		return
+	}
+	resp.State.Raw = v
+}
+
+// parseImportID splits an import ID of the form "namespace/name", or just
+// "name" for cluster-scoped kinds.
+func parseImportID(id string, namespaced bool) (string, string, error) {
+	parts := strings.Split(id, "/")
+	switch {
+	case namespaced && len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	case namespaced:
+		return "", "", fmt.Errorf("expected an import ID of the form \"namespace/name\", got %q", id)
+	case len(parts) == 1 && parts[0] != "":
+		return "", parts[0], nil
+	default:
+		return "", "", fmt.Errorf("expected an import ID of the form \"name\" for a cluster-scoped kind, got %q", id)
+	}
+}
+
+// objectFromValue builds the Kubernetes manifest described by a resource value.
+func (r *CustomResource) objectFromValue(v tftypes.Value) (*unstructured.Unstructured, error) {
+	o, err := objectFromValue(r.schema, v)
+	if err != nil {
+		return nil, err
+	}
+	mo, ok := o.(map[string]interface{})
+	if !ok {
+		mo = make(map[string]interface{})
+	}
+	// Status is written by controllers and never sent to the API server.
+	delete(mo, "status")
+	obj := &unstructured.Unstructured{Object: mo}
+	obj.SetGroupVersionKind(r.gvk)
+	return obj, nil
+}
+
+// stateFromObject converts obj into a resource value of the same type as prior.
+// Provider-defined attributes keep their prior values.
+func (r *CustomResource) stateFromObject(obj map[string]interface{}, prior tftypes.Value) (tftypes.Value, error) {
+	v, err := valueFromObject(r.schema, prior.Type(), obj)
+	if err != nil || prior.IsNull() {
+		return v, err
+	}
+	var av, pv map[string]tftypes.Value
+	if err := v.As(&av); err != nil {
+		return tftypes.Value{}, err
+	}
+	if err := prior.As(&pv); err != nil {
+		return tftypes.Value{}, err
+	}
+	for _, n := range resourceAttributes {
+		if p, ok := pv[n]; ok {
+			av[n] = p
+		}
+	}
+	return tftypes.NewValue(v.Type(), av), nil
+}
+
+// appliedState completes the planned value of a resource with the values the
+// API server populated in live: the generated name, the object's status, the
+// computed attributes left out of the configuration and the live object.
+func (r *CustomResource) appliedState(plan tftypes.Value, live *unstructured.Unstructured) (tftypes.Value, error) {
+	plan, err := r.withLiveValues(plan, live)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	var av map[string]tftypes.Value
+	if err := plan.As(&av); err != nil {
+		return tftypes.Value{}, err
+	}
+
+	var mv map[string]tftypes.Value
+	if err := av["metadata"].As(&mv); err != nil {
+		return tftypes.Value{}, err
+	}
+	mv["name"] = tftypes.NewValue(tftypes.String, live.GetName())
+	av["metadata"] = tftypes.NewValue(av["metadata"].Type(), mv)
+
+	if st, ok := av["status"]; ok {
+		sp := r.schema.Properties["status"]
+		sv, err := valueFromObject(&sp, st.Type(), live.Object["status"])
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		av["status"] = sv
+	}
+	return r.withLiveObject(tftypes.NewValue(plan.Type(), av), live.Object)
+}
+
+// manifestKnown reports whether all the values making up the Kubernetes object
+// in v are known.
+func (r *CustomResource) manifestKnown(v tftypes.Value) (bool, error) {
+	var av map[string]tftypes.Value
+	if err := v.As(&av); err != nil {
+		return false, err
+	}
+	known := true
+	for n, a := range av {
+		// av is the map held by v, so attributes which aren't part of the
+		// object are skipped rather than deleted.
+		if containsString(resourceAttributes, n) || n == "status" || (n == "object" && r.hasLiveObject()) {
+			continue
+		}
+		if a.IsFullyKnown() {
+			continue
+		}
+		// Computed attributes left out of the configuration are left out of
+		// the object as well.
+		err := tftypes.Walk(a, func(p *tftypes.AttributePath, e tftypes.Value) (bool, error) {
+			if e.IsKnown() {
+				return true, nil
+			}
+			known = known && r.options.computed[strings.TrimSuffix(n+"."+attributePathString(p), ".")]
+			return false, nil
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return known, nil
+}
+
+// resourceClient returns a dynamic client scoped to the object's namespace.
+func (r *CustomResource) resourceClient(obj *unstructured.Unstructured) dynamic.ResourceInterface {
+	gvr := r.gvk.GroupVersion().WithResource(r.plural)
+	if r.namespaced {
+		return r.clients.Dynamic.Resource(gvr).Namespace(obj.GetNamespace())
+	}
+	return r.clients.Dynamic.Resource(gvr)
+}
+
+// attributeMode determines whether a generated attribute is set in the
+// configuration or populated by the provider.
+type attributeMode int
+
+const (
+	optionalAttribute attributeMode = iota
+	requiredAttribute
+	computedAttribute
+)
+
+// nested returns the mode of an attribute nested within one of mode m.
+// Attributes nested within computed ones are computed themselves.
+func (m attributeMode) nested(required bool) attributeMode {
+	switch {
+	case m == computedAttribute:
+		return computedAttribute
+	case required:
+		return requiredAttribute
+	default:
+		return optionalAttribute
+	}
 }
 
 func resourceName(version string, group string, kind string) string {
@@ -77,58 +907,110 @@ func resourceName(version string, group string, kind string) string {
 	return fmt.Sprintf("%s_%s_%s", g, version, kind)
 }
 
-func attributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
+// attributeFromOAPI converts the OpenAPI schema of the field at path p into a
+// framework attribute. Fields which can't be converted are reported as warnings
+// and yield a dynamic attribute, so that they can still be set.
+func attributeFromOAPI(s *spec.Schema, p path.Path, m attributeMode) (schema.Attribute, diag.Diagnostics) {
+	if s != nil && len(s.AllOf) > 0 {
+		s = flattenAllOf(s)
+	}
+	a, diags := attributeTypeFromOAPI(s, p, m)
+	if a != nil && m != computedAttribute && isImmutable(s) {
+		a = withImmutableModifier(a)
+	}
+	if a != nil && isSensitive(s, p) {
+		a = withSensitive(a, true)
+	}
+	return a, diags
+}
+
+// attributeTypeFromOAPI picks the kind of attribute matching s.
+func attributeTypeFromOAPI(s *spec.Schema, p path.Path, m attributeMode) (schema.Attribute, diag.Diagnostics) {
+	var diags diag.Diagnostics
 	if s == nil {
-		log.Fatal("nil input schema")
+		diags.AddAttributeWarning(p, "Missing Attribute Schema", fmt.Sprintf("Attribute %s has no schema and is treated as dynamic.", p))
+		return dynamicAttributeFromOAPI(&spec.Schema{}, m), diags
 	}
-	if v, ok := s.Extensions["x-kubernetes-preserve-unknown-fields"]; ok {
-		bv, ok := v.(bool)
-		if ok && bv {
-			return dynamicAttributeFromOAPI(s, r)
-		}
+	s = withRulesDescription(s)
+	if isSchemaless(s) {
+		return dynamicAttributeFromOAPI(s, m), nil
+	}
+	if isQuantity(s) {
+		return quantityAttributeFromOAPI(s, m), nil
+	}
+	if isIntOrString(s) {
+		return intOrStringAttributeFromOAPI(s, m), nil
+	}
+	if isAlternatives(s) {
+		diags.AddAttributeWarning(p, "Ambiguous Attribute Type", fmt.Sprintf("Attribute %s accepts one of several schemas and is treated as dynamic.", p))
+		return dynamicAttributeFromOAPI(s, m), diags
 	}
 	switch {
+	case s.Type.Contains("string") && isDateTime(s):
+		return dateTimeAttributeFromOAPI(s, m), nil
 	case s.Type.Contains("string"):
-		return stringAttributeFromOAPI(s, r)
+		return stringAttributeFromOAPI(s, m), nil
 	case s.Type.Contains("integer"):
+		// Integers without a format are unbounded in OpenAPI, and the API
+		// server stores them as 64-bit integers.
 		switch s.Format {
 		case "int32":
-			return int32AttributeFromOAPI(s, r)
-		case "int64":
-			return int64AttributeFromOAPI(s, r)
+			return int32AttributeFromOAPI(s, m), nil
+		case "int64", "":
+			return int64AttributeFromOAPI(s, m), nil
 		}
 	case s.Type.Contains("number"):
 		switch s.Format {
-		case "float":
-			return floatAttributeFromOAPI(s, r)
+		case "float", "":
+			return floatAttributeFromOAPI(s, m), nil
 		case "double":
-			return doubleAttributeFromOAPI(s, r)
+			return doubleAttributeFromOAPI(s, m), nil
 		}
 	case s.Type.Contains("boolean"):
-		return boolAttributeFromOAPI(s, r)
+		return boolAttributeFromOAPI(s, m), nil
 	case len(s.Type) == 0:
-		log.Printf("unknown attribute type: %#v", *s)
+		diags.AddAttributeWarning(p, "Unknown Attribute Type", fmt.Sprintf("Attribute %s does not declare a type and is treated as dynamic.", p))
+		return dynamicAttributeFromOAPI(s, m), diags
 	case s.Type.Contains("object"):
 		switch {
 		case len(s.Properties) > 0:
-			return singleNestedAttributeFromOAPI(s, r)
-		case s.AdditionalProperties.Allows && len(s.Properties) == 0:
-			if isOAPIPrimitive(s.AdditionalProperties.Schema.Type) {
-				return mapAttributeFromOAPI(s, r)
+			return singleNestedAttributeFromOAPI(s, p, m)
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Allows && s.AdditionalProperties.Schema != nil:
+			if isOAPIPrimitive(s.AdditionalProperties.Schema.Type) || isIntOrString(s.AdditionalProperties.Schema) {
+				return mapAttributeFromOAPI(s, p, m)
 			} else {
-				return mapNestedAttributeFromOAPI(s, r)
+				return mapNestedAttributeFromOAPI(s, p, m)
 			}
 		}
 	case s.Type.Contains("array"):
-		if isOAPIPrimitive(s.Items.Schema.Type) {
-			return listAttributeFromOAPI(s, r)
+		if s.Items == nil || s.Items.Schema == nil {
+			diags.AddAttributeWarning(p, "Unknown Attribute Type", fmt.Sprintf("Array attribute %s does not declare an item schema and is treated as dynamic.", p))
+			return dynamicAttributeFromOAPI(s, m), diags
+		}
+		if isOAPIPrimitive(s.Items.Schema.Type) || isIntOrString(s.Items.Schema) {
+			return listAttributeFromOAPI(s, p, m)
 		} else {
-			return listNestedAttributeFromOAPI(s, r)
+			return listNestedAttributeFromOAPI(s, p, m)
 		}
-	default:
-		log.Printf("unsupported attribute type: %#v", s.Type)
 	}
-	return nil
+	diags.AddAttributeWarning(p, "Unsupported Attribute Type", fmt.Sprintf("Attribute %s has unsupported type %q (format %q) and is treated as dynamic.", p, strings.Join(s.Type, ","), s.Format))
+	return dynamicAttributeFromOAPI(s, m), diags
+}
+
+// isSchemaless reports whether the fields of values described by s are left
+// to the values themselves. That is the case for fields preserving unknown
+// fields and for embedded Kubernetes objects, whose apiVersion and kind
+// determine their structure.
+func isSchemaless(s *spec.Schema) bool {
+	if s == nil {
+		return false
+	}
+	for _, ext := range []string{"x-kubernetes-preserve-unknown-fields", "x-kubernetes-embedded-resource"} {
+		if v, ok := s.Extensions[ext].(bool); ok && v {
+			return true
+		}
+	}
+	return false
 }
 
 func isOAPIPrimitive(t spec.StringOrArray) bool {
@@ -146,6 +1028,34 @@ func isOAPIPrimitive(t spec.StringOrArray) bool {
 	}
 }
 
+// isOAPISet reports whether the array schema s has set semantics, in which
+// case the order of its elements is irrelevant.
+func isOAPISet(s *spec.Schema) bool {
+	if s.UniqueItems {
+		return true
+	}
+	lt, _ := s.Extensions.GetString("x-kubernetes-list-type")
+	return lt == "set"
+}
+
+// elementTypeFromOAPI returns the type of the elements of maps and lists whose
+// element schema is es, or nil if it isn't a primitive.
+func elementTypeFromOAPI(es *spec.Schema) attr.Type {
+	if isQuantity(es) {
+		return QuantityType{}
+	}
+	if isIntOrString(es) {
+		return IntOrStringType{}
+	}
+	if isDateTime(es) {
+		return DateTimeType{}
+	}
+	if len(es.Type) == 0 {
+		return nil
+	}
+	return fwtypeFromOAPIPrimitive(es.Type[0], es.Format)
+}
+
 func fwtypeFromOAPIPrimitive(t string, f string) attr.Type {
 	switch t {
 	case "string":
@@ -156,145 +1066,307 @@ func fwtypeFromOAPIPrimitive(t string, f string) attr.Type {
 		switch f {
 		case "int32":
 			return basetypes.Int32Type{}
-		case "int64":
+		case "int64", "":
 			return basetypes.Int64Type{}
 		}
 	case "number":
 		switch f {
 		case "float":
 			return basetypes.Float32Type{}
-		case "double":
+		case "double", "":
 			return basetypes.Float64Type{}
 		}
 	}
 	return nil
 }
 
-func stringAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
+func stringAttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := stringDefaultFromOAPI(s, m)
 	return schema.StringAttribute{
-		Description: s.Description,
-		Required:    r,
-		Optional:    !r,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		Validators:          stringValidatorsFromOAPI(s, m),
+		Default:             d,
 	}
 }
 
-func boolAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
+func intOrStringAttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := stringDefaultFromOAPI(s, m)
+	return schema.StringAttribute{
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		CustomType:          IntOrStringType{},
+		Default:             d,
+	}
+}
+
+func quantityAttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := stringDefaultFromOAPI(s, m)
+	return schema.StringAttribute{
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		CustomType:          QuantityType{},
+		Default:             d,
+	}
+}
+
+func dateTimeAttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := stringDefaultFromOAPI(s, m)
+	return schema.StringAttribute{
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		CustomType:          DateTimeType{},
+		Validators:          stringValidatorsFromOAPI(s, m),
+		Default:             d,
+	}
+}
+
+func boolAttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := boolDefaultFromOAPI(s, m)
 	return schema.BoolAttribute{
-		Description: s.Description,
-		Required:    r,
-		Optional:    !r,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		Default:             d,
 	}
 }
 
-func int32AttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
+func int32AttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := int32DefaultFromOAPI(s, m)
 	return schema.Int32Attribute{
-		Description: s.Description,
-		Required:    r,
-		Optional:    !r,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		Validators:          int32ValidatorsFromOAPI(s, m),
+		Default:             d,
 	}
 }
 
-func int64AttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
+func int64AttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := int64DefaultFromOAPI(s, m)
 	return schema.Int64Attribute{
-		Description: s.Description,
-		Required:    r,
-		Optional:    !r,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		Validators:          int64ValidatorsFromOAPI(s, m),
+		Default:             d,
 	}
 }
 
-func floatAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
+func floatAttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := float64DefaultFromOAPI(s, m)
 	return schema.Float64Attribute{
-		Description: s.Description,
-		Required:    r,
-		Optional:    !r,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		Validators:          float64ValidatorsFromOAPI(s, m),
+		Default:             d,
 	}
 }
 
-func doubleAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
+func doubleAttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
+	d := float32DefaultFromOAPI(s, m)
 	return schema.Float32Attribute{
-		Description: s.Description,
-		Required:    r,
-		Optional:    !r,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute || d != nil,
+		Validators:          float32ValidatorsFromOAPI(s, m),
+		Default:             d,
 	}
 }
 
-func dynamicAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
+func dynamicAttributeFromOAPI(s *spec.Schema, m attributeMode) schema.Attribute {
 	return schema.DynamicAttribute{
-		Description: s.Description,
-		Required:    r,
-		Optional:    !r,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute,
+	}
+}
+
+// hasDynamicType reports whether t is dynamic or holds dynamic values.
+func hasDynamicType(t attr.Type) bool {
+	switch t := t.(type) {
+	case basetypes.DynamicTypable:
+		return true
+	case attr.TypeWithElementType:
+		return hasDynamicType(t.ElementType())
+	case attr.TypeWithAttributeTypes:
+		for _, at := range t.AttributeTypes() {
+			if hasDynamicType(at) {
+				return true
+			}
+		}
 	}
+	return false
+}
+
+// hasDynamicElements reports whether the elements of a collection, described
+// by es, are dynamic values themselves, such as embedded Kubernetes objects.
+func hasDynamicElements(es *spec.Schema) bool {
+	return isAlternatives(es) || isSchemaless(es)
 }
 
-func singleNestedAttributeFromOAPI(s *spec.Schema, r bool) schema.SingleNestedAttribute {
+// dynamicCollectionFromOAPI returns a dynamic attribute for the collection
+// described by s, whose elements hold dynamic values. Terraform can't type
+// collections of dynamic values, so the collection as a whole is dynamic.
+func dynamicCollectionFromOAPI(s *spec.Schema, p path.Path, m attributeMode) (schema.Attribute, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	diags.AddAttributeWarning(p, "Dynamic Collection Elements", fmt.Sprintf("Attribute %s holds elements which are or have dynamic fields and is treated as dynamic.", p))
+	return dynamicAttributeFromOAPI(s, m), diags
+}
+
+func singleNestedAttributeFromOAPI(s *spec.Schema, p path.Path, m attributeMode) (schema.SingleNestedAttribute, diag.Diagnostics) {
+	var diags diag.Diagnostics
 	att := schema.SingleNestedAttribute{
-		Required:   r,
-		Optional:   !r,
-		Attributes: make(map[string]schema.Attribute),
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		Attributes:          make(map[string]schema.Attribute),
 	}
 	rqat := make(map[string]bool)
 	for _, r := range s.Required {
 		rqat[r] = true
 	}
-	for k, p := range s.Properties {
-		_, rq := rqat[k]
-		av := attributeFromOAPI(&p, rq)
+	names := attributeNames(s)
+	diags.Append(attributeNameDiagnostics(p, names)...)
+	for k, ps := range s.Properties {
+		n := names[k]
+		av, d := attributeFromOAPI(&ps, p.AtName(n), m.nested(rqat[k]))
+		diags.Append(d...)
 		if av == nil {
 			continue
 		}
-		att.Attributes[strcase.SnakeCase(k)] = av
+		att.Attributes[n] = av
 	}
-	return att
+	return att, diags
 }
 
-func mapAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
-	et := fwtypeFromOAPIPrimitive(s.AdditionalProperties.Schema.Type[0], s.AdditionalProperties.Schema.Format)
+func mapAttributeFromOAPI(s *spec.Schema, p path.Path, m attributeMode) (schema.Attribute, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	et := elementTypeFromOAPI(s.AdditionalProperties.Schema)
 	if et == nil {
-		log.Fatalln("failed to determine primitive type from OpenAPI")
+		diags.AddAttributeWarning(p, "Unsupported Attribute Type", fmt.Sprintf("Map attribute %s has unsupported element type %q (format %q) and is treated as dynamic.", p, strings.Join(s.AdditionalProperties.Schema.Type, ","), s.AdditionalProperties.Schema.Format))
+		return dynamicAttributeFromOAPI(s, m), diags
 	}
 	return schema.MapAttribute{
-		Required:    r,
-		Optional:    !r,
-		Description: s.Description,
-		ElementType: et,
-	}
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		ElementType:         et,
+	}, diags
 }
 
-func listAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
-	et := fwtypeFromOAPIPrimitive(s.Items.Schema.Type[0], s.Items.Schema.Format)
+func listAttributeFromOAPI(s *spec.Schema, p path.Path, m attributeMode) (schema.Attribute, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	et := elementTypeFromOAPI(s.Items.Schema)
 	if et == nil {
-		log.Fatalln("failed to determine primitive type from OpenAPI")
+		diags.AddAttributeWarning(p, "Unsupported Attribute Type", fmt.Sprintf("List attribute %s has unsupported element type %q (format %q) and is treated as dynamic.", p, strings.Join(s.Items.Schema.Type, ","), s.Items.Schema.Format))
+		return dynamicAttributeFromOAPI(s, m), diags
 	}
-	return schema.ListAttribute{
-		Required:    r,
-		Optional:    !r,
-		Description: s.Description,
-		ElementType: et,
+	if isOAPISet(s) {
+		return schema.SetAttribute{
+			Required:            m == requiredAttribute,
+			Optional:            m == optionalAttribute,
+			Computed:            m == computedAttribute,
+			Description:         s.Description,
+			MarkdownDescription: s.Description,
+			ElementType:         et,
+			Validators:          setValidatorsFromOAPI(s, m),
+		}, diags
 	}
+	return schema.ListAttribute{
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		ElementType:         et,
+		Validators:          listValidatorsFromOAPI(s, m),
+	}, diags
 }
 
-func mapNestedAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
-	no, ok := singleNestedAttributeFromOAPI(s.AdditionalProperties.Schema, true).GetNestedObject().(schema.NestedAttributeObject)
+func mapNestedAttributeFromOAPI(s *spec.Schema, p path.Path, m attributeMode) (schema.Attribute, diag.Diagnostics) {
+	if hasDynamicElements(s.AdditionalProperties.Schema) {
+		return dynamicCollectionFromOAPI(s, p, m)
+	}
+	sn, diags := singleNestedAttributeFromOAPI(s.AdditionalProperties.Schema, p, m)
+	no, ok := sn.GetNestedObject().(schema.NestedAttributeObject)
 	if !ok {
-		log.Fatalf("missmatched types - should not happen")
+		diags.AddError("Internal Error", fmt.Sprintf("Unexpected nested object type %T for attribute %s. Please report this issue to the provider developers.", sn.GetNestedObject(), p))
+		return nil, diags
 	}
-	return schema.MapNestedAttribute{
-		Required:     r,
-		Optional:     !r,
-		Description:  s.Description,
-		NestedObject: no,
+	if hasDynamicType(no.Type()) {
+		return dynamicCollectionFromOAPI(s, p, m)
 	}
+	return schema.MapNestedAttribute{
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		NestedObject:        no,
+	}, diags
 }
 
-func listNestedAttributeFromOAPI(s *spec.Schema, r bool) schema.Attribute {
-	no, ok := singleNestedAttributeFromOAPI(s.Items.Schema, true).GetNestedObject().(schema.NestedAttributeObject)
+func listNestedAttributeFromOAPI(s *spec.Schema, p path.Path, m attributeMode) (schema.Attribute, diag.Diagnostics) {
+	if hasDynamicElements(s.Items.Schema) {
+		return dynamicCollectionFromOAPI(s, p, m)
+	}
+	sn, diags := singleNestedAttributeFromOAPI(s.Items.Schema, p, m)
+	no, ok := sn.GetNestedObject().(schema.NestedAttributeObject)
 	if !ok {
-		log.Fatalf("missmatched types - should not happen")
+		diags.AddError("Internal Error", fmt.Sprintf("Unexpected nested object type %T for attribute %s. Please report this issue to the provider developers.", sn.GetNestedObject(), p))
+		return nil, diags
 	}
-	return schema.ListNestedAttribute{
-		Required:     r,
-		Optional:     !r,
-		Description:  s.Description,
-		NestedObject: no,
+	if hasDynamicType(no.Type()) {
+		return dynamicCollectionFromOAPI(s, p, m)
 	}
+	if isOAPISet(s) {
+		return schema.SetNestedAttribute{
+			Required:            m == requiredAttribute,
+			Optional:            m == optionalAttribute,
+			Computed:            m == computedAttribute,
+			Description:         s.Description,
+			MarkdownDescription: s.Description,
+			NestedObject:        no,
+			Validators:          setValidatorsFromOAPI(s, m),
+		}, diags
+	}
+	return schema.ListNestedAttribute{
+		Required:            m == requiredAttribute,
+		Optional:            m == optionalAttribute,
+		Computed:            m == computedAttribute,
+		Description:         s.Description,
+		MarkdownDescription: s.Description,
+		NestedObject:        no,
+		Validators:          listValidatorsFromOAPI(s, m),
+	}, diags
 }
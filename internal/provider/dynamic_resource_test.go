@@ -0,0 +1,497 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestParseImportID(t *testing.T) {
+	cases := []struct {
+		id         string
+		namespaced bool
+		namespace  string
+		name       string
+		err        bool
+	}{
+		{id: "default/example", namespaced: true, namespace: "default", name: "example"},
+		{id: "example", namespaced: true, err: true},
+		{id: "default/", namespaced: true, err: true},
+		{id: "example", namespaced: false, name: "example"},
+		{id: "default/example", namespaced: false, err: true},
+		{id: "", namespaced: false, err: true},
+	}
+	for _, c := range cases {
+		ns, n, err := parseImportID(c.id, c.namespaced)
+		if c.err {
+			if err == nil {
+				t.Errorf("%q: expected error", c.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.id, err)
+			continue
+		}
+		if ns != c.namespace || n != c.name {
+			t.Errorf("%q: got %q/%q, want %q/%q", c.id, ns, n, c.namespace, c.name)
+		}
+	}
+}
+
+func testCRDSchema() *spec.Schema {
+	return &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"object"},
+			Properties: map[string]spec.Schema{
+				"apiVersion": *spec.StringProperty(),
+				"kind":       *spec.StringProperty(),
+				"metadata": {
+					SchemaProps: spec.SchemaProps{
+						AllOf: []spec.Schema{*spec.RefSchema("#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta")},
+					},
+				},
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type:     []string{"object"},
+						Required: []string{"replicas"},
+						Properties: map[string]spec.Schema{
+							"replicas": *spec.Int64Property(),
+							"image":    *spec.StringProperty(),
+						},
+					},
+				},
+				"status": {
+					SchemaProps: spec.SchemaProps{
+						Type: []string{"object"},
+						Properties: map[string]spec.Schema{
+							"phase": *spec.StringProperty(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func testCustomResourceSchema(t *testing.T, r resource.Resource) schema.Schema {
+	t.Helper()
+	resp := &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	return resp.Schema
+}
+
+func TestCustomResourceSchema(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomResourceSchema(t, NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}))
+
+	md, ok := s.Attributes["metadata"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a metadata attribute, got %T", s.Attributes["metadata"])
+	}
+	if !md.Attributes["namespace"].IsRequired() {
+		t.Error("expected namespace to be required for a namespaced kind")
+	}
+	sp, ok := s.Attributes["spec"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a spec attribute, got %T", s.Attributes["spec"])
+	}
+	if !sp.Attributes["replicas"].IsRequired() || !sp.Attributes["image"].IsOptional() {
+		t.Error("unexpected spec attribute flags")
+	}
+	st, ok := s.Attributes["status"].(schema.SingleNestedAttribute)
+	if !ok {
+		t.Fatalf("expected a status attribute, got %T", s.Attributes["status"])
+	}
+	if !st.IsComputed() || st.IsOptional() || !st.Attributes["phase"].IsComputed() {
+		t.Error("expected status to be computed")
+	}
+	for _, k := range []string{"api_version", "kind"} {
+		if _, ok := s.Attributes[k]; ok {
+			t.Errorf("unexpected attribute %q", k)
+		}
+	}
+}
+
+func TestCustomResourceSchemaClusterScoped(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomResourceSchema(t, NewCustomResource("v1", "example.com", names, v1.ClusterScoped, testCRDSchema(), schemaOptions{}))
+
+	md := s.Attributes["metadata"].(schema.SingleNestedAttribute)
+	if _, ok := md.Attributes["namespace"]; ok {
+		t.Error("unexpected namespace attribute for a cluster-scoped kind")
+	}
+}
+
+func TestCustomResourceDescription(t *testing.T) {
+	crd := testCRDSchema()
+	crd.Description = "Widget is a **test** kind."
+	sp := crd.Properties["spec"]
+	sp.Description = "Desired state of the `Widget`."
+	crd.Properties["spec"] = sp
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	s := testCustomResourceSchema(t, NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, crd, schemaOptions{}))
+
+	if want := "Widget is a **test** kind.\n\nManages `Widget` objects of API version `example.com/v1`."; s.MarkdownDescription != want {
+		t.Errorf("unexpected resource description: %q", s.MarkdownDescription)
+	}
+	if d := s.Attributes["spec"].GetMarkdownDescription(); d != sp.Description {
+		t.Errorf("unexpected spec description: %q", d)
+	}
+}
+
+func TestCustomResourceSchemaless(t *testing.T) {
+	root := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}}}
+	root.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+	names := v1.CustomResourceDefinitionNames{Kind: "Blob", Singular: "blob", Plural: "blobs"}
+	r := NewCustomResource("v1", "example.com", names, v1.ClusterScoped, root, schemaOptions{})
+	s := testCustomResourceSchema(t, r)
+
+	if _, ok := s.Attributes["spec"].(schema.DynamicAttribute); !ok {
+		t.Fatalf("expected a dynamic spec attribute, got %T", s.Attributes["spec"])
+	}
+	if _, ok := s.Attributes["metadata"].(schema.SingleNestedAttribute); !ok {
+		t.Errorf("expected a metadata attribute, got %T", s.Attributes["metadata"])
+	}
+	if isSchemaless(r.(*CustomResource).schema) {
+		t.Error("expected the root schema to declare its fields")
+	}
+}
+
+func TestAttributeFromOAPIUnsupported(t *testing.T) {
+	cases := map[string]*spec.Schema{
+		"nil":          nil,
+		"untyped":      {},
+		"bad format":   {SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Format: "uint8"}},
+		"no items":     {SchemaProps: spec.SchemaProps{Type: []string{"array"}}},
+		"bad map":      spec.MapProperty(&spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"number"}, Format: "decimal"}}),
+		"empty object": {SchemaProps: spec.SchemaProps{Type: []string{"object"}}},
+	}
+	for name, s := range cases {
+		a, diags := attributeFromOAPI(s, path.Root("spec").AtName("field"), optionalAttribute)
+		if _, ok := a.(schema.DynamicAttribute); !ok {
+			t.Errorf("%s: expected a dynamic attribute, got %T", name, a)
+		}
+		if diags.WarningsCount() != 1 || diags.HasError() {
+			t.Errorf("%s: expected a single warning, got %v", name, diags)
+		}
+	}
+}
+
+func TestAttributeFromOAPIFormatless(t *testing.T) {
+	cases := map[string]struct {
+		s    *spec.Schema
+		want attr.Type
+	}{
+		"integer":      {&spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"integer"}}}, types.Int64Type},
+		"number":       {&spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"number"}}}, types.Float64Type},
+		"integer list": {spec.ArrayProperty(&spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"integer"}}}), types.ListType{ElemType: types.Int64Type}},
+		"number map":   {spec.MapProperty(&spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"number"}}}), types.MapType{ElemType: types.Float64Type}},
+	}
+	for name, c := range cases {
+		a, diags := attributeFromOAPI(c.s, path.Root("spec").AtName("field"), optionalAttribute)
+		if len(diags) != 0 {
+			t.Errorf("%s: unexpected diagnostics: %v", name, diags)
+		}
+		if got := a.GetType(); !got.Equal(c.want) {
+			t.Errorf("%s: expected %s, got %s", name, c.want, got)
+		}
+	}
+}
+
+// testSpecResourceSchema returns the validated schema of a resource whose
+// spec holds properties.
+func testSpecResourceSchema(t *testing.T, properties map[string]spec.Schema) schema.Schema {
+	t.Helper()
+	s := testCRDSchema()
+	s.Properties["spec"] = spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: properties}}
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	rs := testCustomResourceSchema(t, NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, s, schemaOptions{}))
+	if diags := rs.ValidateImplementation(context.Background()); diags.HasError() {
+		t.Fatalf("invalid schema: %v", diags)
+	}
+	return rs
+}
+
+func TestCustomResourceSchemaDynamicCollections(t *testing.T) {
+	item := func(props map[string]spec.Schema) *spec.Schema {
+		return &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: props}}
+	}
+	ports := item(map[string]spec.Schema{"port": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}}}})
+	untyped := item(map[string]spec.Schema{"value": {}, "name": *spec.StringProperty()})
+	nested := item(map[string]spec.Schema{"inner": *spec.ArrayProperty(untyped)})
+	set := spec.ArrayProperty(untyped)
+	set.UniqueItems = true
+
+	s := testSpecResourceSchema(t, map[string]spec.Schema{
+		"ports":   *spec.ArrayProperty(ports),
+		"values":  *spec.ArrayProperty(untyped),
+		"entries": *spec.MapProperty(untyped),
+		"set":     *set,
+		"nested":  *item(map[string]spec.Schema{"outer": *spec.ArrayProperty(nested)}),
+	})
+	sp := s.Attributes["spec"].(schema.SingleNestedAttribute)
+	if _, ok := sp.Attributes["ports"].(schema.ListNestedAttribute); !ok {
+		t.Errorf("expected ports to be a list of objects, got %T", sp.Attributes["ports"])
+	}
+	for _, n := range []string{"values", "entries", "set"} {
+		if _, ok := sp.Attributes[n].(schema.DynamicAttribute); !ok {
+			t.Errorf("expected %s to be dynamic, got %T", n, sp.Attributes[n])
+		}
+	}
+	outer := sp.Attributes["nested"].(schema.SingleNestedAttribute).Attributes["outer"]
+	if _, ok := outer.(schema.DynamicAttribute); !ok {
+		t.Errorf("expected nested.outer to be dynamic, got %T", outer)
+	}
+}
+
+func TestCustomResourceSchemaAlternativesInCollections(t *testing.T) {
+	alternatives := &spec.Schema{SchemaProps: spec.SchemaProps{
+		OneOf: []spec.Schema{*spec.StringProperty(), *spec.Int64Property()},
+	}}
+	item := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"value": *alternatives, "name": *spec.StringProperty()},
+	}}
+	s := testSpecResourceSchema(t, map[string]spec.Schema{
+		"fields":    *spec.ArrayProperty(item),
+		"values":    *spec.ArrayProperty(alternatives),
+		"field_map": *spec.MapProperty(item),
+		"value_map": *spec.MapProperty(alternatives),
+	})
+	sp := s.Attributes["spec"].(schema.SingleNestedAttribute)
+	for _, n := range []string{"fields", "values", "field_map", "value_map"} {
+		if _, ok := sp.Attributes[n].(schema.DynamicAttribute); !ok {
+			t.Errorf("expected %s to be dynamic, got %T", n, sp.Attributes[n])
+		}
+	}
+}
+
+func TestAttributeFromOAPIEmbeddedResource(t *testing.T) {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"apiVersion": *spec.StringProperty(),
+			"kind":       *spec.StringProperty(),
+		},
+	}}
+	s.AddExtension("x-kubernetes-embedded-resource", true)
+	a, diags := attributeFromOAPI(s, path.Root("spec").AtName("template"), optionalAttribute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if _, ok := a.(schema.DynamicAttribute); !ok {
+		t.Errorf("expected a dynamic attribute, got %T", a)
+	}
+}
+
+func TestCustomResourceSchemaEmbeddedResourcesInCollections(t *testing.T) {
+	embedded := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"apiVersion": *spec.StringProperty(),
+			"kind":       *spec.StringProperty(),
+		},
+	}}
+	embedded.AddExtension("x-kubernetes-embedded-resource", true)
+	preserved := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}}}
+	preserved.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+	template := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"name": *spec.StringProperty(), "template": *embedded},
+	}}
+	s := testSpecResourceSchema(t, map[string]spec.Schema{
+		"templates": *spec.ArrayProperty(template),
+		"resources": *spec.ArrayProperty(embedded),
+		"values":    *spec.MapProperty(preserved),
+	})
+	sp := s.Attributes["spec"].(schema.SingleNestedAttribute)
+	for _, n := range []string{"templates", "resources", "values"} {
+		if _, ok := sp.Attributes[n].(schema.DynamicAttribute); !ok {
+			t.Errorf("expected %s to be dynamic, got %T", n, sp.Attributes[n])
+		}
+	}
+}
+
+func TestWithCommonMetadata(t *testing.T) {
+	r := &CustomResource{
+		commonLabels:      map[string]string{"managed-by": "terraform", "team": "platform"},
+		commonAnnotations: map[string]string{"cost-center": "42"},
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetLabels(map[string]string{"team": "apps"})
+
+	applied := r.withCommonMetadata(obj)
+	want := map[string]string{"managed-by": "terraform", "team": "apps"}
+	if got := applied.GetLabels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected labels: %v", got)
+	}
+	if got := applied.GetAnnotations(); got["cost-center"] != "42" {
+		t.Errorf("unexpected annotations: %v", got)
+	}
+	if got := obj.GetLabels(); len(got) != 1 {
+		t.Errorf("expected the configured object to be left as is, got labels %v", got)
+	}
+}
+
+func TestAttributeFromOAPISet(t *testing.T) {
+	tags := spec.ArrayProperty(spec.StringProperty())
+	tags.UniqueItems = true
+	tags.MaxItems = ptr(int64(3))
+	a, diags := attributeFromOAPI(tags, path.Root("spec").AtName("tags"), optionalAttribute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	sa, ok := a.(schema.SetAttribute)
+	if !ok {
+		t.Fatalf("expected a set attribute, got %T", a)
+	}
+	if len(sa.Validators) != 1 {
+		t.Errorf("expected a size validator, got %d validators", len(sa.Validators))
+	}
+
+	ports := spec.ArrayProperty(&spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"port": *spec.Int64Property()},
+	}})
+	ports.AddExtension("x-kubernetes-list-type", "set")
+	a, _ = attributeFromOAPI(ports, path.Root("spec").AtName("ports"), optionalAttribute)
+	if _, ok := a.(schema.SetNestedAttribute); !ok {
+		t.Errorf("expected a set nested attribute, got %T", a)
+	}
+
+	ports.Extensions = nil
+	a, _ = attributeFromOAPI(ports, path.Root("spec").AtName("ports"), optionalAttribute)
+	if _, ok := a.(schema.ListNestedAttribute); !ok {
+		t.Errorf("expected a list nested attribute, got %T", a)
+	}
+}
+
+func TestAttributeFromOAPIDefault(t *testing.T) {
+	policy := spec.StringProperty()
+	policy.Default = "IfNotPresent"
+	a, _ := attributeFromOAPI(policy, path.Root("spec").AtName("pull_policy"), optionalAttribute)
+	sa, ok := a.(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("expected a string attribute, got %T", a)
+	}
+	if !sa.IsOptional() || !sa.IsComputed() || sa.Default == nil {
+		t.Errorf("expected an optional and computed attribute with a default, got %#v", sa)
+	}
+
+	replicas := spec.Int64Property()
+	replicas.Default = float64(1)
+	a, _ = attributeFromOAPI(replicas, path.Root("spec").AtName("replicas"), requiredAttribute)
+	if ia := a.(schema.Int64Attribute); ia.IsComputed() || ia.Default != nil {
+		t.Errorf("expected no default for a required attribute, got %#v", ia)
+	}
+	a, _ = attributeFromOAPI(replicas, path.Root("spec").AtName("replicas"), optionalAttribute)
+	if ia := a.(schema.Int64Attribute); !ia.IsComputed() || ia.Default == nil {
+		t.Errorf("expected a default for an optional attribute, got %#v", ia)
+	}
+}
+
+func TestCustomResourceLegacyObject(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, legacyObjectSchema(), schemaOptions{}).(*CustomResource)
+	s := testCustomResourceSchema(t, r)
+	if _, ok := s.Attributes["object"].(schema.DynamicAttribute); !ok {
+		t.Fatalf("expected a dynamic object attribute, got %T", s.Attributes["object"])
+	}
+
+	live := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "test", "namespace": "default"},
+		"spec":       map[string]interface{}{"size": "large"},
+		"data":       map[string]interface{}{"color": "blue"},
+		"status":     map[string]interface{}{"phase": "Ready"},
+	}
+	v, err := valueFromObject(r.schema, s.Type().TerraformType(context.Background()), live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := r.objectFromValue(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "test", "namespace": "default"},
+		"spec":       map[string]interface{}{"size": "large"},
+		"data":       map[string]interface{}{"color": "blue"},
+	}
+	if !reflect.DeepEqual(obj.Object, want) {
+		t.Errorf("unexpected object:\n got: %#v\nwant: %#v", obj.Object, want)
+	}
+}
+
+func TestModifyPlanServerDryRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled bool
+		status   int
+		reason   metav1.StatusReason
+		requests int
+		err      bool
+	}{
+		{name: "accepted", status: http.StatusOK, requests: 1},
+		{name: "rejected", status: http.StatusUnprocessableEntity, reason: metav1.StatusReasonInvalid, requests: 1, err: true},
+		{name: "denied by a webhook", status: http.StatusForbidden, reason: metav1.StatusReasonForbidden, requests: 1, err: true},
+		{name: "apply unsupported", status: http.StatusUnsupportedMediaType, reason: metav1.StatusReasonUnsupportedMediaType, requests: 1},
+		{name: "disabled", disabled: true, status: http.StatusUnprocessableEntity, reason: metav1.StatusReasonInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			r, plan := testWidgetResource(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.Method != http.MethodPatch || r.URL.Query().Get("dryRun") != metav1.DryRunAll {
+					t.Errorf("expected a dry run apply, got %s %s", r.Method, r.URL)
+				}
+				if tt.status != http.StatusOK {
+					writeStatus(w, tt.status, tt.reason, nil)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"test","namespace":"default"}}`))
+			})
+			r.serverDryRun = !tt.disabled
+
+			p := plan(nil)
+			resp := &resource.ModifyPlanResponse{Plan: p}
+			r.ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+				Plan:   p,
+				Config: tfsdk.Config(p),
+				State:  tfsdk.State{Schema: p.Schema, Raw: tftypes.NewValue(p.Raw.Type(), nil)},
+			}, resp)
+			if requests != tt.requests {
+				t.Errorf("expected %d requests, got %d", tt.requests, requests)
+			}
+			if resp.Diagnostics.HasError() != tt.err {
+				t.Errorf("expected error %t, got %v", tt.err, resp.Diagnostics)
+			}
+			if tt.err && !strings.Contains(resp.Diagnostics[0].Detail(), "Unable to validate Widget") {
+				t.Errorf("expected the rejection of the API server, got %v", resp.Diagnostics)
+			}
+		})
+	}
+}
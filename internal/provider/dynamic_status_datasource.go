@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ datasource.DataSource = &CustomStatusDataSource{}
+var _ datasource.DataSourceWithConfigure = &CustomStatusDataSource{}
+
+// defaultStatusTimeout is how long status data sources poll objects by
+// default. It is kept short, as they are read on every plan.
+const defaultStatusTimeout = 30 * time.Second
+
+func NewCustomStatusDataSource(v string, g string, n v1.CustomResourceDefinitionNames, scope v1.ResourceScope, s *spec.Schema, opts schemaOptions) datasource.DataSource {
+	return &CustomStatusDataSource{
+		resource: NewCustomResource(v, g, n, scope, s, opts).(*CustomResource),
+	}
+}
+
+// CustomStatusDataSource reports whether an object of a custom resource kind
+// is healthy, polling its status for a short while. Unlike the wait_for
+// attribute of other data sources, objects which don't become healthy in
+// time aren't an error, so that check blocks can assert on them.
+type CustomStatusDataSource struct {
+	resource *CustomResource
+}
+
+func (d *CustomStatusDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.resource.name + "_status"
+}
+
+func (d *CustomStatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attrs, diags := d.resource.dataSourceAttributes(ctx)
+	resp.Diagnostics.Append(diags...)
+	status, ok := attrs["status"]
+	if !ok {
+		status = schema.DynamicAttribute{
+			MarkdownDescription: "Status of the object.",
+			Computed:            true,
+		}
+	}
+
+	healthyWhen := waitForAttribute("Criteria the object satisfies when healthy. The object is polled until it satisfies them or the timeout, which defaults to `30s`, expires.")
+	healthyWhen.Optional, healthyWhen.Required = false, true
+	resp.Schema.Description = d.resource.description("Polls the status of")
+	resp.Schema.MarkdownDescription = d.resource.markdownDescription("Polls the status of")
+	resp.Schema.Attributes = map[string]schema.Attribute{
+		"metadata":     dataSourceMetadataAttribute(d.resource.namespaced),
+		"status":       status,
+		"healthy_when": healthyWhen,
+		"healthy": schema.BoolAttribute{
+			MarkdownDescription: "Whether the object satisfied the `healthy_when` criteria before the timeout expired.",
+			Computed:            true,
+		},
+	}
+}
+
+func (d *CustomStatusDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.resource.clients = pd.Clients
+	d.resource.operationTimeout = pd.OperationTimeout
+}
+
+func (d *CustomStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var name, namespace types.String
+	var wm *WaitModel
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("metadata").AtName("name"), &name)...)
+	if d.resource.namespaced {
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("metadata").AtName("namespace"), &namespace)...)
+	}
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("healthy_when"), &wm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	wc, err := newWaitCriteria(ctx, wm)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("healthy_when"), "Invalid Wait Configuration", err.Error())
+		return
+	}
+	if wm.Timeout.IsNull() {
+		wc.timeout = defaultStatusTimeout
+	}
+
+	r := d.resource
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+	obj := &unstructured.Unstructured{}
+	obj.SetName(name.ValueString())
+	obj.SetNamespace(namespace.ValueString())
+	live, err := waitForObject(ctx, r.resourceClient(obj), obj.GetName(), wc)
+	healthy := err == nil
+	switch {
+	case err != nil && !errors.Is(err, errWaitTimedOut):
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s %q, got error: %s", r.gvk.Kind, obj.GetName(), err))
+		return
+	case live == nil:
+		resp.Diagnostics.AddError("Object Not Found", fmt.Sprintf("%s %q doesn't exist.", r.gvk.Kind, obj.GetName()))
+		return
+	}
+
+	var state map[string]tftypes.Value
+	if err := req.Config.Raw.As(&state); err != nil {
+		resp.Diagnostics.AddError("Failed to read configuration", err.Error())
+		return
+	}
+	at := req.Config.Raw.Type().(tftypes.Object).AttributeTypes
+	sp := r.schema.Properties["status"]
+	state["status"], err = valueFromObject(&sp, at["status"], live.Object["status"])
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert object", err.Error())
+		return
+	}
+	state["healthy"] = tftypes.NewValue(tftypes.Bool, healthy)
+	resp.State.Raw = tftypes.NewValue(req.Config.Raw.Type(), state)
+}
@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ datasource.DataSource = &EventsDataSource{}
+var _ datasource.DataSourceWithConfigure = &EventsDataSource{}
+
+func NewEventsDataSource() datasource.DataSource {
+	return &EventsDataSource{}
+}
+
+// EventsDataSource lists the events recorded about an object, such as the
+// reconciliation failures reported by the controller of a custom resource.
+type EventsDataSource struct {
+	clients *KubernetesClients
+}
+
+type eventsDataSourceModel struct {
+	UID       types.String `tfsdk:"uid"`
+	Namespace types.String `tfsdk:"namespace"`
+	Type      types.String `tfsdk:"type"`
+	Events    []eventModel `tfsdk:"events"`
+}
+
+type eventModel struct {
+	Type          types.String `tfsdk:"type"`
+	Reason        types.String `tfsdk:"reason"`
+	Message       types.String `tfsdk:"message"`
+	Count         types.Int64  `tfsdk:"count"`
+	Source        types.String `tfsdk:"source"`
+	LastTimestamp types.String `tfsdk:"last_timestamp"`
+}
+
+func (d *EventsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_events"
+}
+
+func (d *EventsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the events recorded about an object, such as a custom resource, identified by its UID.",
+		Attributes: map[string]schema.Attribute{
+			"uid": schema.StringAttribute{
+				MarkdownDescription: "UID of the object the events are about, as exposed by the data sources of custom resource kinds, such as `data.crd_example_com_v1_widget.example.metadata.uid`.",
+				Required:            true,
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "Namespace of the object. Events of all namespaces are searched when not set.",
+				Optional:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Type of the events to list, `Normal` or `Warning`. Events of all types are listed when not set.",
+				Optional:            true,
+			},
+			"events": schema.ListNestedAttribute{
+				MarkdownDescription: "Events about the object, oldest first.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Type of the event, `Normal` or `Warning`.",
+							Computed:            true,
+						},
+						"reason": schema.StringAttribute{
+							MarkdownDescription: "Short, machine-readable reason of the event.",
+							Computed:            true,
+						},
+						"message": schema.StringAttribute{
+							MarkdownDescription: "Human-readable description of the event.",
+							Computed:            true,
+						},
+						"count": schema.Int64Attribute{
+							MarkdownDescription: "Number of times the event occurred.",
+							Computed:            true,
+						},
+						"source": schema.StringAttribute{
+							MarkdownDescription: "Component which reported the event.",
+							Computed:            true,
+						},
+						"last_timestamp": schema.StringAttribute{
+							MarkdownDescription: "Time the event last occurred, in RFC 3339 format.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *EventsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.clients = pd.Clients
+}
+
+func (d *EventsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data eventsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	selector := fields.Set{"involvedObject.uid": data.UID.ValueString()}
+	if !data.Type.IsNull() {
+		selector["type"] = data.Type.ValueString()
+	}
+	gvr := corev1.SchemeGroupVersion.WithResource("events")
+	list, err := d.clients.Dynamic.Resource(gvr).Namespace(data.Namespace.ValueString()).List(ctx, metav1.ListOptions{
+		FieldSelector: selector.AsSelector().String(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list events, got error: %s", err))
+		return
+	}
+	events := make([]corev1.Event, 0, len(list.Items))
+	for _, u := range list.Items {
+		var e corev1.Event
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &e); err != nil {
+			resp.Diagnostics.AddError("Failed to convert event", fmt.Sprintf("Event %q: %s", u.GetName(), err))
+			return
+		}
+		events = append(events, e)
+	}
+	data.Events = eventModels(events)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// eventModels returns the models of events, oldest first. Events reported
+// through the events.k8s.io API carry their details in different fields,
+// which are used when the core ones are empty.
+func eventModels(events []corev1.Event) []eventModel {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	models := make([]eventModel, 0, len(events))
+	for _, e := range events {
+		count := e.Count
+		if count == 0 && e.Series != nil {
+			count = e.Series.Count
+		}
+		if count == 0 {
+			count = 1
+		}
+		source := e.Source.Component
+		if source == "" {
+			source = e.ReportingController
+		}
+		m := eventModel{
+			Type:          types.StringValue(e.Type),
+			Reason:        types.StringValue(e.Reason),
+			Message:       types.StringValue(e.Message),
+			Count:         types.Int64Value(int64(count)),
+			Source:        types.StringValue(source),
+			LastTimestamp: types.StringNull(),
+		}
+		if t := eventTime(e); !t.IsZero() {
+			m.LastTimestamp = types.StringValue(t.UTC().Format(time.RFC3339))
+		}
+		models = append(models, m)
+	}
+	return models
+}
+
+// eventTime returns the time e last occurred.
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.FirstTimestamp.Time
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEventModels(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	events := []corev1.Event{
+		{
+			Type:          "Warning",
+			Reason:        "ReconcileFailed",
+			Message:       "secret not found",
+			Count:         3,
+			Source:        corev1.EventSource{Component: "widget-controller"},
+			LastTimestamp: metav1.NewTime(t0.Add(time.Minute)),
+		},
+		{
+			Type:                "Normal",
+			Reason:              "Created",
+			ReportingController: "example.com/widget-controller",
+			EventTime:           metav1.NewMicroTime(t0),
+		},
+	}
+	models := eventModels(events)
+	if len(models) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(models))
+	}
+	if models[0].Reason.ValueString() != "Created" {
+		t.Errorf("expected events ordered by time, got %s first", models[0].Reason.ValueString())
+	}
+	if models[0].Count.ValueInt64() != 1 || models[0].Source.ValueString() != "example.com/widget-controller" {
+		t.Errorf("unexpected event: %+v", models[0])
+	}
+	if models[0].LastTimestamp.ValueString() != "2024-05-01T10:00:00Z" {
+		t.Errorf("unexpected timestamp: %s", models[0].LastTimestamp.ValueString())
+	}
+	if models[1].Count.ValueInt64() != 3 || models[1].Source.ValueString() != "widget-controller" {
+		t.Errorf("unexpected event: %+v", models[1])
+	}
+}
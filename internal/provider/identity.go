@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ resource.ResourceWithIdentity = &CustomResource{}
+
+// IdentityModel describes the identity of generated resources: the object
+// they manage, down to its UID, so that an object recreated under the same
+// name is a different resource.
+type IdentityModel struct {
+	APIVersion types.String `tfsdk:"api_version"`
+	Kind       types.String `tfsdk:"kind"`
+	Namespace  types.String `tfsdk:"namespace"`
+	Name       types.String `tfsdk:"name"`
+	UID        types.String `tfsdk:"uid"`
+}
+
+func (r *CustomResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"api_version": identityschema.StringAttribute{
+				Description:       "API version of the object, such as example.com/v1.",
+				RequiredForImport: true,
+			},
+			"kind": identityschema.StringAttribute{
+				Description:       "Kind of the object.",
+				RequiredForImport: true,
+			},
+			"namespace": identityschema.StringAttribute{
+				Description:       "Namespace of the object, required for namespaced kinds.",
+				OptionalForImport: true,
+			},
+			"name": identityschema.StringAttribute{
+				Description:       "Name of the object.",
+				RequiredForImport: true,
+			},
+			"uid": identityschema.StringAttribute{
+				Description:       "Unique identifier of the object. When set on import, the object must have it.",
+				OptionalForImport: true,
+			},
+		},
+	}
+}
+
+// identityOf returns the identity of the resource managing obj.
+func identityOf(obj *unstructured.Unstructured) IdentityModel {
+	m := IdentityModel{
+		APIVersion: types.StringValue(obj.GetAPIVersion()),
+		Kind:       types.StringValue(obj.GetKind()),
+		Name:       types.StringValue(obj.GetName()),
+		UID:        types.StringValue(string(obj.GetUID())),
+	}
+	if obj.GetNamespace() != "" {
+		m.Namespace = types.StringValue(obj.GetNamespace())
+	}
+	return m
+}
+
+// setIdentity sets identity, when Terraform supports identities, to that of
+// the resource managing live.
+func setIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, live *unstructured.Unstructured) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, identityOf(live))
+}
+
+// importIdentity returns the namespace, name and UID of the object identified
+// by identity, an import identity of the resource.
+func (r *CustomResource) importIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity) (string, string, string, diag.Diagnostics) {
+	var m IdentityModel
+	diags := identity.Get(ctx, &m)
+	if diags.HasError() {
+		return "", "", "", diags
+	}
+	if m.APIVersion.ValueString() != r.gvk.GroupVersion().String() || m.Kind.ValueString() != r.gvk.Kind {
+		diags.AddError("Invalid Import Identity", fmt.Sprintf("Expected an identity of a %s of API version %s, got a %s of API version %s.",
+			r.gvk.Kind, r.gvk.GroupVersion(), m.Kind.ValueString(), m.APIVersion.ValueString()))
+	}
+	switch {
+	case m.Name.ValueString() == "":
+		diags.AddError("Invalid Import Identity", "The identity has no name.")
+	case r.namespaced && m.Namespace.ValueString() == "":
+		diags.AddError("Invalid Import Identity", fmt.Sprintf("%s is namespaced, so the identity requires a namespace.", r.gvk.Kind))
+	case !r.namespaced && m.Namespace.ValueString() != "":
+		diags.AddError("Invalid Import Identity", fmt.Sprintf("%s is cluster-scoped, so the identity can't have a namespace.", r.gvk.Kind))
+	}
+	return m.Namespace.ValueString(), m.Name.ValueString(), m.UID.ValueString(), diags
+}
+
+// uidKey is the private state key holding the UID of the object a resource
+// manages. Unlike identities, private state is kept by every version of
+// Terraform.
+const uidKey = "uid"
+
+// setRecordedUID records the UID of live in private state.
+func setRecordedUID(ctx context.Context, p privateState, live *unstructured.Unstructured) diag.Diagnostics {
+	uid, err := json.Marshal(string(live.GetUID()))
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to record UID", err.Error())
+		return diags
+	}
+	return p.SetKey(ctx, uidKey, uid)
+}
+
+// recordedUID returns the UID of the object the resource manages, as recorded
+// in private state or, failing that, in identity, or an empty string if
+// neither records one.
+func recordedUID(ctx context.Context, p privateState, identity *tfsdk.ResourceIdentity) (string, diag.Diagnostics) {
+	b, diags := p.GetKey(ctx, uidKey)
+	if diags.HasError() {
+		return "", diags
+	}
+	if b != nil {
+		var uid string
+		if err := json.Unmarshal(b, &uid); err != nil {
+			diags.AddError("Failed to decode recorded UID", err.Error())
+			return "", diags
+		}
+		if uid != "" {
+			return uid, diags
+		}
+	}
+	if identity == nil || identity.Raw.IsNull() {
+		return "", diags
+	}
+	var m IdentityModel
+	if d := identity.Get(ctx, &m); d.HasError() {
+		return "", diags
+	}
+	return m.UID.ValueString(), diags
+}
+
+// replacedObject reports whether live is another object than the one with the
+// recorded uid, which happens when an object is deleted and recreated under
+// the same name outside of Terraform.
+func replacedObject(uid string, live *unstructured.Unstructured) bool {
+	return uid != "" && uid != string(live.GetUID())
+}
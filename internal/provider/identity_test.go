@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// testIdentity returns an empty identity of r.
+func testIdentity(t *testing.T, r *CustomResource) *tfsdk.ResourceIdentity {
+	t.Helper()
+	ctx := context.Background()
+	resp := &resource.IdentitySchemaResponse{}
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if diags := resp.IdentitySchema.ValidateImplementation(ctx); diags.HasError() {
+		t.Fatalf("invalid identity schema: %v", diags)
+	}
+	return &tfsdk.ResourceIdentity{Schema: resp.IdentitySchema, Raw: tftypes.NewValue(resp.IdentitySchema.Type().TerraformType(ctx), nil)}
+}
+
+func TestResourceIdentity(t *testing.T) {
+	ctx := context.Background()
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+
+	live := &unstructured.Unstructured{}
+	live.SetAPIVersion("example.com/v1")
+	live.SetKind("Widget")
+	live.SetNamespace("default")
+	live.SetName("test")
+	live.SetUID("1234")
+
+	identity := testIdentity(t, r)
+	if diags := setIdentity(ctx, identity, live); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	namespace, name, uid, diags := r.importIdentity(ctx, identity)
+	if diags.HasError() || namespace != "default" || name != "test" || uid != "1234" {
+		t.Errorf("unexpected import identity %q %q %q: %v", namespace, name, uid, diags)
+	}
+
+	uid, diags = recordedUID(ctx, testPrivateState{}, identity)
+	if diags.HasError() || uid != "1234" {
+		t.Errorf("expected the UID of the identity, got %q: %v", uid, diags)
+	}
+	if replacedObject(uid, live) {
+		t.Error("expected the same object not to be reported as replaced")
+	}
+	recreated := live.DeepCopy()
+	recreated.SetUID("5678")
+	if !replacedObject(uid, recreated) {
+		t.Error("expected an object with another UID to be reported as replaced")
+	}
+
+	other := testIdentity(t, r)
+	if diags := other.Set(ctx, IdentityModel{
+		APIVersion: types.StringValue("example.com/v1"),
+		Kind:       types.StringValue("Gadget"),
+		Name:       types.StringValue("test"),
+	}); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if _, _, _, diags := r.importIdentity(ctx, other); !diags.HasError() {
+		t.Error("expected an error for an identity of another kind without a namespace")
+	}
+}
+
+// testPrivateState holds private state data in memory.
+type testPrivateState map[string][]byte
+
+func (p testPrivateState) GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p testPrivateState) SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics {
+	p[key] = value
+	return nil
+}
+
+func TestRecordedUID(t *testing.T) {
+	ctx := context.Background()
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+
+	uid, diags := recordedUID(ctx, testPrivateState{}, nil)
+	if diags.HasError() || uid != "" {
+		t.Errorf("expected no recorded UID, got %q: %v", uid, diags)
+	}
+
+	live := &unstructured.Unstructured{}
+	live.SetAPIVersion("example.com/v1")
+	live.SetKind("Widget")
+	live.SetName("test")
+	live.SetUID("1234")
+	identity := testIdentity(t, r)
+	if diags := setIdentity(ctx, identity, live); diags.HasError() {
+		t.Fatal(diags)
+	}
+	private := testPrivateState{}
+	recreated := live.DeepCopy()
+	recreated.SetUID("5678")
+	if diags := setRecordedUID(ctx, private, recreated); diags.HasError() {
+		t.Fatal(diags)
+	}
+	uid, diags = recordedUID(ctx, private, identity)
+	if diags.HasError() || uid != "5678" {
+		t.Errorf("expected the UID recorded in private state to take precedence, got %q: %v", uid, diags)
+	}
+}
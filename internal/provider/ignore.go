@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func ignoreFieldsAttribute() schema.Attribute {
+	return schema.ListAttribute{
+		MarkdownDescription: "Attribute paths, such as `spec.replicas`, of fields changed by controllers or webhooks. " +
+			"Their changes don't show up as drift, and updates leave their values as they are in the cluster. They are set when the object is created.",
+		Optional:    true,
+		ElementType: types.StringType,
+	}
+}
+
+// ignoredFields returns the attribute paths listed in the ignore_fields
+// attribute of the resource value held by g.
+func ignoredFields(ctx context.Context, g attributeGetter) ([]string, diag.Diagnostics) {
+	var paths []string
+	diags := g.GetAttribute(ctx, path.Root("ignore_fields"), &paths)
+	return paths, diags
+}
+
+// fieldPath returns the path of the field holding the attribute at the dotted
+// attribute path p of values described by s. Attribute names within dynamic
+// values are field names already.
+func fieldPath(s *spec.Schema, p string) []string {
+	var fields []string
+	for _, n := range strings.Split(p, ".") {
+		var field string
+		field, s = fieldOf(s, n)
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// fieldOf returns the name of the field holding the attribute n of objects
+// described by s, along with the schema of the field, or nil if s doesn't
+// describe it.
+func fieldOf(s *spec.Schema, n string) (string, *spec.Schema) {
+	if s != nil && len(s.AllOf) > 0 {
+		s = flattenAllOf(s)
+	}
+	if s == nil || isSchemaless(s) {
+		return n, nil
+	}
+	field := n
+	for k, an := range attributeNames(s) {
+		if an == n {
+			field = k
+			break
+		}
+	}
+	if ps, ok := s.Properties[field]; ok {
+		return field, &ps
+	}
+	return field, nil
+}
+
+// withLiveFields sets the ignored fields at paths of obj, the object applied
+// on update, to their values in live, or removes them if live has none.
+// Ignored fields are those controllers change after Terraform set them, such
+// as the replicas of scaled objects, so updates apply their live values
+// rather than overwrite them.
+func (r *CustomResource) withLiveFields(obj *unstructured.Unstructured, live map[string]interface{}, paths []string) *unstructured.Unstructured {
+	if len(paths) == 0 {
+		return obj
+	}
+	obj = obj.DeepCopy()
+	for _, p := range paths {
+		fp := fieldPath(r.schema, p)
+		v, ok, _ := unstructured.NestedFieldCopy(live, fp...)
+		if ok {
+			_ = unstructured.SetNestedField(obj.Object, v, fp...)
+		} else {
+			unstructured.RemoveNestedField(obj.Object, fp...)
+		}
+	}
+	return obj
+}
+
+// withPriorValues returns v with the attributes at paths set to their values
+// in prior. Reads keep the values of ignored fields from the prior state, so
+// that their changes don't show up as drift.
+func withPriorValues(v, prior tftypes.Value, paths []string) (tftypes.Value, error) {
+	if len(paths) == 0 || prior.IsNull() {
+		return v, nil
+	}
+	ignored := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		ignored[p] = true
+	}
+	return tftypes.Transform(v, func(p *tftypes.AttributePath, e tftypes.Value) (tftypes.Value, error) {
+		if len(p.Steps()) == 0 || !ignored[attributePathString(p)] {
+			return e, nil
+		}
+		pv, _, err := tftypes.WalkAttributePath(prior, p)
+		if err != nil {
+			return e, nil
+		}
+		if pv, ok := pv.(tftypes.Value); ok && pv.Type().Equal(e.Type()) {
+			return pv, nil
+		}
+		return e, nil
+	})
+}
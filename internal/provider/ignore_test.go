@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestFieldPath(t *testing.T) {
+	values := unknownFieldsSchema("")
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"spec": {SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"replicaCount": *spec.Int64Property(),
+					"helmValues":   *values,
+				},
+			}},
+		},
+	}}
+	cases := map[string][]string{
+		"spec.replica_count":             {"spec", "replicaCount"},
+		"spec.helm_values.image.pullTag": {"spec", "helmValues", "image", "pullTag"},
+		"spec.unknown_field":             {"spec", "unknown_field"},
+	}
+	for p, want := range cases {
+		if got := fieldPath(s, p); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", p, want, got)
+		}
+	}
+}
+
+func TestWithLiveFields(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": int64(1), "image": "nginx"},
+	}}
+	live := map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": int64(5), "image": "nginx:old"},
+	}
+	got := r.withLiveFields(obj, live, []string{"spec.replicas"})
+	want := map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": int64(5), "image": "nginx"},
+	}
+	if !reflect.DeepEqual(got.Object, want) {
+		t.Errorf("unexpected object:\n got: %#v\nwant: %#v", got.Object, want)
+	}
+	if obj.Object["spec"].(map[string]interface{})["replicas"] != int64(1) {
+		t.Error("expected the applied object to be left as it is")
+	}
+}
+
+func TestWithPriorValues(t *testing.T) {
+	st := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"replicas": tftypes.Number, "image": tftypes.String}}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"spec": st}}
+	value := func(replicas int64, image string) tftypes.Value {
+		return tftypes.NewValue(typ, map[string]tftypes.Value{
+			"spec": tftypes.NewValue(st, map[string]tftypes.Value{
+				"replicas": tftypes.NewValue(tftypes.Number, replicas),
+				"image":    tftypes.NewValue(tftypes.String, image),
+			}),
+		})
+	}
+	got, err := withPriorValues(value(5, "nginx:new"), value(1, "nginx"), []string{"spec.replicas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := value(1, "nginx:new"); !got.Equal(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ planmodifier.String = immutableModifier{}
+var _ planmodifier.Bool = immutableModifier{}
+var _ planmodifier.Int32 = immutableModifier{}
+var _ planmodifier.Int64 = immutableModifier{}
+var _ planmodifier.Float32 = immutableModifier{}
+var _ planmodifier.Float64 = immutableModifier{}
+var _ planmodifier.Dynamic = immutableModifier{}
+var _ planmodifier.List = immutableModifier{}
+var _ planmodifier.Set = immutableModifier{}
+var _ planmodifier.Map = immutableModifier{}
+var _ planmodifier.Object = immutableModifier{}
+
+// isImmutable reports whether s carries the CEL transition rule which keeps
+// a field from changing once set, as generated by kubebuilder for
+// `+kubebuilder:validation:XValidation:rule="self == oldSelf"`.
+func isImmutable(s *spec.Schema) bool {
+	for _, r := range validationRulesFromOAPI(s) {
+		switch strings.Join(strings.Fields(r.Rule), "") {
+		case "self==oldSelf", "oldSelf==self":
+			return true
+		}
+	}
+	return false
+}
+
+// immutableModifier plans the replacement of the resource when the value of
+// an immutable field changes. The API server only checks transition rules
+// when the field is set both before and after the update, so setting or
+// removing the field is left to an in-place update.
+type immutableModifier struct{}
+
+func (m immutableModifier) Description(ctx context.Context) string {
+	return "Changing the value of this attribute once set forces the replacement of the resource."
+}
+
+func (m immutableModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m immutableModifier) requiresReplace(state, plan attr.Value) bool {
+	if state.IsNull() || plan.IsNull() || plan.IsUnknown() {
+		return false
+	}
+	return !state.Equal(plan)
+}
+
+func (m immutableModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyBool(ctx context.Context, req planmodifier.BoolRequest, resp *planmodifier.BoolResponse) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyInt32(ctx context.Context, req planmodifier.Int32Request, resp *planmodifier.Int32Response) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyFloat32(ctx context.Context, req planmodifier.Float32Request, resp *planmodifier.Float32Response) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyFloat64(ctx context.Context, req planmodifier.Float64Request, resp *planmodifier.Float64Response) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyDynamic(ctx context.Context, req planmodifier.DynamicRequest, resp *planmodifier.DynamicResponse) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyList(ctx context.Context, req planmodifier.ListRequest, resp *planmodifier.ListResponse) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifySet(ctx context.Context, req planmodifier.SetRequest, resp *planmodifier.SetResponse) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyMap(ctx context.Context, req planmodifier.MapRequest, resp *planmodifier.MapResponse) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+func (m immutableModifier) PlanModifyObject(ctx context.Context, req planmodifier.ObjectRequest, resp *planmodifier.ObjectResponse) {
+	resp.RequiresReplace = m.requiresReplace(req.StateValue, req.PlanValue)
+}
+
+// withImmutableModifier returns a with an immutableModifier added to its plan
+// modifiers.
+func withImmutableModifier(a schema.Attribute) schema.Attribute {
+	m := immutableModifier{}
+	switch a := a.(type) {
+	case schema.StringAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.BoolAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.Int32Attribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.Int64Attribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.Float32Attribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.Float64Attribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.DynamicAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.ListAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.ListNestedAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.SetAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.SetNestedAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.MapAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.MapNestedAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	case schema.SingleNestedAttribute:
+		a.PlanModifiers = append(a.PlanModifiers, m)
+		return a
+	}
+	return a
+}
+
+// hasImmutableModifier reports whether a plans the replacement of the
+// resource when its value changes, as added by withImmutableModifier.
+func hasImmutableModifier(a schema.Attribute) bool {
+	switch a := a.(type) {
+	case schema.StringAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.BoolAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.Int32Attribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.Int64Attribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.Float32Attribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.Float64Attribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.DynamicAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.ListAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.ListNestedAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.SetAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.SetNestedAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.MapAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.MapNestedAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	case schema.SingleNestedAttribute:
+		return containsImmutableModifier(a.PlanModifiers)
+	}
+	return false
+}
+
+func containsImmutableModifier[M any](modifiers []M) bool {
+	for _, m := range modifiers {
+		if _, ok := any(m).(immutableModifier); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestImmutableAttribute(t *testing.T) {
+	s := spec.StringProperty()
+	s.AddExtension("x-kubernetes-validations", []interface{}{
+		map[string]interface{}{"rule": "self ==  oldSelf", "message": "Value is immutable"},
+	})
+	a, diags := attributeFromOAPI(s, path.Root("spec").AtName("class"), optionalAttribute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	sa, ok := a.(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("expected a string attribute, got %T", a)
+	}
+	if len(sa.PlanModifiers) != 1 {
+		t.Errorf("expected an immutable plan modifier, got %v", sa.PlanModifiers)
+	}
+
+	a, _ = attributeFromOAPI(s, path.Root("status").AtName("class"), computedAttribute)
+	if sa := a.(schema.StringAttribute); len(sa.PlanModifiers) != 0 {
+		t.Errorf("expected computed attributes to be left as is, got %v", sa.PlanModifiers)
+	}
+}
+
+func TestImmutableModifier(t *testing.T) {
+	cases := []struct {
+		state, plan types.String
+		replace     bool
+	}{
+		{types.StringValue("a"), types.StringValue("b"), true},
+		{types.StringValue("a"), types.StringValue("a"), false},
+		{types.StringNull(), types.StringValue("a"), false},
+		{types.StringValue("a"), types.StringNull(), false},
+		{types.StringValue("a"), types.StringUnknown(), false},
+	}
+	for _, c := range cases {
+		req := planmodifier.StringRequest{StateValue: c.state, PlanValue: c.plan}
+		resp := &planmodifier.StringResponse{PlanValue: c.plan}
+		immutableModifier{}.PlanModifyString(context.Background(), req, resp)
+		if resp.RequiresReplace != c.replace {
+			t.Errorf("%s -> %s: got replace %t, want %t", c.state, c.plan, resp.RequiresReplace, c.replace)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ basetypes.StringTypable = IntOrStringType{}
+var _ basetypes.StringValuableWithSemanticEquals = IntOrStringValue{}
+
+// isIntOrString reports whether s is marked with x-kubernetes-int-or-string,
+// meaning the field holds either an integer or a string.
+func isIntOrString(s *spec.Schema) bool {
+	if s == nil {
+		return false
+	}
+	v, ok := s.Extensions["x-kubernetes-int-or-string"].(bool)
+	return ok && v
+}
+
+// intOrStringFromValue returns the manifest form of an int-or-string field
+// set to sv: an integer if sv reads as one, or else sv itself.
+func intOrStringFromValue(sv string) interface{} {
+	if iv, err := strconv.ParseInt(sv, 10, 64); err == nil {
+		return iv
+	}
+	return sv
+}
+
+// IntOrStringType is the type of attributes generated for int-or-string
+// fields. Values are held as strings, with integers in their decimal form, so
+// that configurations can use either 80 or "80".
+type IntOrStringType struct {
+	basetypes.StringType
+}
+
+func (t IntOrStringType) Equal(o attr.Type) bool {
+	other, ok := o.(IntOrStringType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t IntOrStringType) String() string {
+	return "IntOrStringType"
+}
+
+func (t IntOrStringType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return IntOrStringValue{StringValue: in}, nil
+}
+
+func (t IntOrStringType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	av, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	sv, ok := av.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", av)
+	}
+	v, diags := t.ValueFromString(ctx, sv)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to IntOrStringValue: %v", diags)
+	}
+	return v, nil
+}
+
+func (t IntOrStringType) ValueType(ctx context.Context) attr.Value {
+	return IntOrStringValue{}
+}
+
+// IntOrStringValue is a value of IntOrStringType.
+type IntOrStringValue struct {
+	basetypes.StringValue
+}
+
+func (v IntOrStringValue) Equal(o attr.Value) bool {
+	other, ok := o.(IntOrStringValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v IntOrStringValue) Type(ctx context.Context) attr.Type {
+	return IntOrStringType{}
+}
+
+// StringSemanticEquals treats integers written differently, such as "080" and
+// "80", as equal.
+func (v IntOrStringValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	nv, d := newValuable.ToStringValue(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return false, diags
+	}
+	if v.ValueString() == nv.ValueString() {
+		return true, diags
+	}
+	a, ok := intOrStringFromValue(v.ValueString()).(int64)
+	if !ok {
+		return false, diags
+	}
+	b, ok := intOrStringFromValue(nv.ValueString()).(int64)
+	return ok && a == b, diags
+}
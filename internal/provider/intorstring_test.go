@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func intOrStringProperty() *spec.Schema {
+	s := &spec.Schema{}
+	s.AddExtension("x-kubernetes-int-or-string", true)
+	return s
+}
+
+func TestIntOrStringSemanticEquals(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"80", "80", true},
+		{"080", "80", true},
+		{"http", "http", true},
+		{"80", "http", false},
+		{"50%", "50", false},
+	}
+	for _, c := range cases {
+		v := IntOrStringValue{StringValue: types.StringValue(c.a)}
+		eq, diags := v.StringSemanticEquals(context.Background(), IntOrStringValue{StringValue: types.StringValue(c.b)})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if eq != c.equal {
+			t.Errorf("%q == %q: got %t, want %t", c.a, c.b, eq, c.equal)
+		}
+	}
+}
+
+func TestIntOrStringObjectValue(t *testing.T) {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"targetPort": *intOrStringProperty()},
+	}}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"target_port": tftypes.String}}
+
+	for _, o := range []interface{}{int64(8080), "http"} {
+		obj := map[string]interface{}{"targetPort": o}
+		v, err := valueFromObject(s, typ, obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := objectFromValue(s, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, obj) {
+			t.Errorf("round trip mismatch:\n got: %#v\nwant: %#v", got, obj)
+		}
+	}
+
+	a, diags := attributeFromOAPI(intOrStringProperty(), path.Root("spec").AtName("target_port"), optionalAttribute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if sa, ok := a.(schema.StringAttribute); !ok || sa.CustomType == nil {
+		t.Errorf("expected a string attribute of IntOrStringType, got %#v", a)
+	}
+}
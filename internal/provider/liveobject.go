@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// liveObjectAttribute returns the object attribute, which holds the object as
+// the API server returned it, so that configurations can refer to the fields
+// it populated, whether or not the schema of the kind describes them. It
+// can't be sensitive in parts, so it is sensitive as a whole if any of the
+// attributes of the kind are, and write-only fields are left out of it, as
+// they are never stored.
+func liveObjectAttribute(sensitive bool) schema.Attribute {
+	return schema.DynamicAttribute{
+		MarkdownDescription: "The object as last returned by the API server, including the fields it populated, such as `metadata.uid` or `status`, " +
+			"and those the schema of the kind doesn't describe. Managed fields and write-only fields are left out.",
+		Computed:  true,
+		Sensitive: sensitive,
+	}
+}
+
+// hasLiveObject reports whether resources of the kind have an object
+// attribute, which is the case unless the kind has a field by that name, as
+// do kinds whose definitions preserve unknown fields.
+func (r *CustomResource) hasLiveObject() bool {
+	for _, n := range attributeNames(r.schema) {
+		if n == "object" {
+			return false
+		}
+	}
+	return true
+}
+
+// withLiveObject returns v, a resource value, with its object attribute set to
+// live.
+func (r *CustomResource) withLiveObject(v tftypes.Value, live map[string]interface{}) (tftypes.Value, error) {
+	if !r.hasLiveObject() || v.IsNull() {
+		return v, nil
+	}
+	var av map[string]tftypes.Value
+	if err := v.As(&av); err != nil {
+		return tftypes.Value{}, err
+	}
+	if _, ok := av["object"]; !ok {
+		return v, nil
+	}
+	obj := make(map[string]interface{}, len(live))
+	for k, f := range live {
+		obj[k] = f
+	}
+	if len(r.options.writeOnly) > 0 {
+		obj = runtime.DeepCopyJSON(obj)
+		for p := range r.options.writeOnly {
+			withoutField(r.schema, obj, strings.Split(p, "."))
+		}
+	}
+	if md, ok := live["metadata"].(map[string]interface{}); ok {
+		m := make(map[string]interface{}, len(md))
+		for k, f := range md {
+			if k != "managedFields" {
+				m[k] = f
+			}
+		}
+		obj["metadata"] = m
+	}
+	ov, err := dynamicValueFromObject(obj)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	av["object"] = ov
+	return tftypes.NewValue(v.Type(), av), nil
+}
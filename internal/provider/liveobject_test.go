@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestCustomResourceLiveObject(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+	s := testCustomResourceSchema(t, r)
+	oa, ok := s.Attributes["object"].(schema.DynamicAttribute)
+	if !ok || !oa.IsComputed() || oa.IsOptional() {
+		t.Fatalf("expected a computed dynamic object attribute, got %#v", s.Attributes["object"])
+	}
+
+	live := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata": map[string]interface{}{
+			"name":          "test",
+			"namespace":     "default",
+			"uid":           "6d2c1a4e",
+			"managedFields": []interface{}{map[string]interface{}{"manager": "terraform-provider-crd"}},
+		},
+		"spec":   map[string]interface{}{"replicas": int64(2), "image": "nginx"},
+		"status": map[string]interface{}{"phase": "Ready", "observedGeneration": int64(1)},
+	}
+	v, err := valueFromObject(r.schema, s.Type().TerraformType(context.Background()), live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = r.withLiveObject(v, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ov, _, err := tftypes.WalkAttributePath(v, tftypes.NewAttributePath().WithAttributeName("object"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var obj map[string]tftypes.Value
+	if err := ov.(tftypes.Value).As(&obj); err != nil {
+		t.Fatal(err)
+	}
+	var md map[string]tftypes.Value
+	if err := obj["metadata"].As(&md); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := md["managedFields"]; ok {
+		t.Error("expected managed fields to be left out")
+	}
+	if !md["uid"].Equal(tftypes.NewValue(tftypes.String, "6d2c1a4e")) {
+		t.Errorf("expected the UID of the object, got %s", md["uid"])
+	}
+	if _, ok := obj["status"]; !ok {
+		t.Error("expected the status of the object")
+	}
+	if _, ok := live["metadata"].(map[string]interface{})["managedFields"]; !ok {
+		t.Error("expected the live object to be left as it was")
+	}
+
+	legacy := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, legacyObjectSchema(), schemaOptions{}).(*CustomResource)
+	if legacy.hasLiveObject() {
+		t.Error("expected kinds with an object field to have no live object attribute")
+	}
+}
+
+func TestCustomResourceLiveObjectSecrets(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	crd := testCRDSchema()
+	crd.Properties["spec"].Properties["users"] = *spec.ArrayProperty(&spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"userName": *spec.StringProperty(), "loginHint": *spec.StringProperty()},
+	}})
+
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, crd, schemaOptions{}).(*CustomResource)
+	if oa := testCustomResourceSchema(t, r).Attributes["object"]; oa.IsSensitive() {
+		t.Error("expected the object attribute of kinds without secrets not to be sensitive")
+	}
+	r = NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, crd, schemaOptions{
+		sensitive: map[string]bool{"spec.image": true},
+	}).(*CustomResource)
+	if oa := testCustomResourceSchema(t, r).Attributes["object"]; !oa.IsSensitive() {
+		t.Error("expected the object attribute of kinds with sensitive attributes to be sensitive")
+	}
+
+	r = NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, crd, schemaOptions{
+		writeOnly: map[string]bool{"spec.image": true, "spec.users.login_hint": true},
+	}).(*CustomResource)
+	s := testCustomResourceSchema(t, r)
+	live := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "test", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"image":    "nginx",
+			"users":    []interface{}{map[string]interface{}{"userName": "admin", "loginHint": "hunter2"}},
+		},
+	}
+	v, err := valueFromObject(r.schema, s.Type().TerraformType(context.Background()), live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = r.withLiveObject(v, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ov, _, err := tftypes.WalkAttributePath(v, tftypes.NewAttributePath().WithAttributeName("object").WithAttributeName("spec"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sp map[string]tftypes.Value
+	if err := ov.(tftypes.Value).As(&sp); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sp["image"]; ok {
+		t.Error("expected write-only fields to be left out")
+	}
+	var users []tftypes.Value
+	if err := sp["users"].As(&users); err != nil {
+		t.Fatal(err)
+	}
+	var user map[string]tftypes.Value
+	if err := users[0].As(&user); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := user["loginHint"]; ok || !user["userName"].Equal(tftypes.NewValue(tftypes.String, "admin")) {
+		t.Errorf("expected write-only fields to be left out of list elements, got %v", user)
+	}
+	if live["spec"].(map[string]interface{})["image"] != "nginx" {
+		t.Error("expected the live object to be left as it was")
+	}
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The provider logs to subsystems, so that the logs of each concern can be
+// told apart and have their own level, set by environment variables such as
+// TF_LOG_PROVIDER_CRD_DISCOVERY.
+const (
+	// discoverySubsystem logs the lookup of custom resource definitions and
+	// their schemas.
+	discoverySubsystem = "discovery"
+	// schemaSubsystem logs the conversion of schemas into resources.
+	schemaSubsystem = "schema"
+	// crudSubsystem logs the operations on objects.
+	crudSubsystem = "crud"
+)
+
+// logLevelEnv prefixes the environment variables setting the level of each
+// subsystem.
+const logLevelEnv = "TF_LOG_PROVIDER_CRD"
+
+// withSubsystem returns ctx set up to log to subsystem, with the fields of the
+// root logger, such as the request ID, included.
+func withSubsystem(ctx context.Context, subsystem string) context.Context {
+	return tflog.NewSubsystem(ctx, subsystem, tflog.WithLevelFromEnv(logLevelEnv, subsystem), tflog.WithRootFields())
+}
+
+// logContext returns ctx set up to log to subsystem with the group, version
+// and kind of the resource.
+func (r *CustomResource) logContext(ctx context.Context, subsystem string) context.Context {
+	ctx = withSubsystem(ctx, subsystem)
+	return tflog.SubsystemSetField(ctx, subsystem, "gvk", r.gvk.String())
+}
+
+// logOperation logs the outcome of the operation op, started at start, to the
+// CRUD subsystem.
+func logOperation(ctx context.Context, op string, start time.Time, diags diag.Diagnostics) {
+	fields := map[string]interface{}{
+		"operation":  op,
+		"latency_ms": time.Since(start).Milliseconds(),
+	}
+	if diags.HasError() {
+		fields["error"] = diags.Errors()[0].Summary()
+		tflog.SubsystemWarn(ctx, crudSubsystem, "Operation failed", fields)
+		return
+	}
+	tflog.SubsystemDebug(ctx, crudSubsystem, "Operation completed", fields)
+}
+
+// withObjectFields returns ctx logging the name and namespace of obj to the
+// CRUD subsystem.
+func withObjectFields(ctx context.Context, obj *unstructured.Unstructured) context.Context {
+	ctx = tflog.SubsystemSetField(ctx, crudSubsystem, "name", obj.GetName())
+	if ns := obj.GetNamespace(); ns != "" {
+		ctx = tflog.SubsystemSetField(ctx, crudSubsystem, "namespace", ns)
+	}
+	return ctx
+}
+
+// logSchemaDiagnostic logs d, raised while converting a schema, to the schema
+// subsystem along with the path of the attribute it concerns.
+func logSchemaDiagnostic(ctx context.Context, d diag.Diagnostic) {
+	fields := map[string]interface{}{"detail": d.Detail()}
+	if dp, ok := d.(diag.DiagnosticWithPath); ok {
+		fields["attribute_path"] = dp.Path().String()
+	}
+	tflog.SubsystemDebug(ctx, schemaSubsystem, d.Summary(), fields)
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLogging(t *testing.T) {
+	var out bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &out)
+
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+	obj := &unstructured.Unstructured{}
+	obj.SetName("test")
+	obj.SetNamespace("default")
+	crud := withObjectFields(r.logContext(ctx, crudSubsystem), obj)
+	var diags diag.Diagnostics
+	diags.AddError("Client Error", "unreachable")
+	logOperation(crud, "read", time.Now().Add(-time.Second), diags)
+
+	var d diag.Diagnostics
+	d.AddAttributeWarning(path.Root("spec").AtName("size"), "Unknown Attribute Type", "treated as dynamic")
+	logSchemaDiagnostic(r.logContext(ctx, schemaSubsystem), d[0])
+
+	entries, err := tflogtest.MultilineJSONDecode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %v", entries)
+	}
+	op := entries[0]
+	if op["@module"] != "provider.crud" || op["@level"] != "warn" {
+		t.Errorf("expected a warning of the CRUD subsystem, got %v", op)
+	}
+	for k, want := range map[string]interface{}{"gvk": "example.com/v1, Kind=Widget", "name": "test", "namespace": "default", "operation": "read", "error": "Client Error"} {
+		if op[k] != want {
+			t.Errorf("expected %s to be %v, got %v", k, want, op[k])
+		}
+	}
+	if ms, _ := op["latency_ms"].(float64); ms < 1000 {
+		t.Errorf("expected the latency of the operation, got %v", op["latency_ms"])
+	}
+	sd := entries[1]
+	if sd["@module"] != "provider.schema" || sd["attribute_path"] != "spec.size" {
+		t.Errorf("expected the attribute path logged by the schema subsystem, got %v", sd)
+	}
+}
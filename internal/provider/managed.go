@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ownedFields returns the set of fields of live owned by manager, in the
+// FieldsV1 format of managed fields, or nil if live records none. Fields
+// written through subresources, such as status, are left out. Reads only
+// track the fields owned by the field manager of the resource, so that values
+// other controllers set, or took over, don't show up as drift.
+func ownedFields(live *unstructured.Unstructured, manager string) map[string]interface{} {
+	var owned map[string]interface{}
+	for _, mf := range live.GetManagedFields() {
+		if mf.Manager != manager || mf.Subresource != "" || mf.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(mf.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if owned == nil {
+			owned = make(map[string]interface{})
+		}
+		mergeFields(owned, fields)
+	}
+	return owned
+}
+
+// mergeFields adds the fields of src to dst, both field sets.
+func mergeFields(dst, src map[string]interface{}) {
+	for k, sf := range src {
+		sm, _ := sf.(map[string]interface{})
+		dm, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dst[k] = sm
+			continue
+		}
+		// An empty set owns the whole value.
+		if len(sm) == 0 || len(dm) == 0 {
+			dst[k] = map[string]interface{}{}
+			continue
+		}
+		mergeFields(dm, sm)
+	}
+}
+
+// ownedObject returns obj less the fields missing from owned, keeping the
+// fields identifying the object, which field managers don't own.
+func ownedObject(obj map[string]interface{}, owned map[string]interface{}) map[string]interface{} {
+	o, _ := ownedValue(obj, owned).(map[string]interface{})
+	if o == nil {
+		o = make(map[string]interface{})
+	}
+	for _, k := range []string{"apiVersion", "kind"} {
+		if v, ok := obj[k]; ok {
+			o[k] = v
+		}
+	}
+	if md, ok := obj["metadata"].(map[string]interface{}); ok {
+		om, _ := o["metadata"].(map[string]interface{})
+		if om == nil {
+			om = make(map[string]interface{})
+		}
+		for _, k := range []string{"name", "namespace"} {
+			if v, ok := md[k]; ok {
+				om[k] = v
+			}
+		}
+		o["metadata"] = om
+	}
+	return o
+}
+
+// ownedValue returns v less the fields missing from fields. An empty field set
+// owns v as a whole.
+func ownedValue(v interface{}, fields map[string]interface{}) interface{} {
+	if len(fields) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		o := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			f, ok := fields["f:"+k]
+			if !ok {
+				continue
+			}
+			fm, _ := f.(map[string]interface{})
+			o[k] = ownedValue(e, fm)
+		}
+		return o
+	case []interface{}:
+		o := make([]interface{}, 0, len(v))
+		for i, e := range v {
+			f, ok := elementFields(e, i, fields)
+			if !ok {
+				continue
+			}
+			o = append(o, ownedValue(e, f))
+		}
+		return o
+	}
+	return v
+}
+
+// elementFields returns the field set of fields identifying e, the element at
+// index i of a list, by the values of its keys, by its own value or by its
+// index, and whether there is one.
+func elementFields(e interface{}, i int, fields map[string]interface{}) (map[string]interface{}, bool) {
+	for k, f := range fields {
+		fm, _ := f.(map[string]interface{})
+		switch {
+		case strings.HasPrefix(k, "k:"):
+			var key map[string]interface{}
+			em, ok := e.(map[string]interface{})
+			if !ok || json.Unmarshal([]byte(k[2:]), &key) != nil {
+				continue
+			}
+			ek := make(map[string]interface{}, len(key))
+			for n := range key {
+				ek[n] = em[n]
+			}
+			if sameJSON(ek, key) {
+				return fm, true
+			}
+		case strings.HasPrefix(k, "v:"):
+			var val interface{}
+			if json.Unmarshal([]byte(k[2:]), &val) == nil && sameJSON(e, val) {
+				return fm, true
+			}
+		case strings.HasPrefix(k, "i:"):
+			if n, err := strconv.Atoi(k[2:]); err == nil && n == i {
+				return fm, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// sameJSON reports whether a and b have the same JSON encoding, which makes
+// integers and the floats decoded from field sets compare equal.
+func sameJSON(a, b interface{}) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	return err == nil && string(ja) == string(jb)
+}
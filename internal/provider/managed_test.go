@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestOwnedObject(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata": map[string]interface{}{
+			"name":      "test",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"app": "test", "injected": "true"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"image":    "nginx",
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+				map[string]interface{}{"port": int64(9090), "protocol": "TCP"},
+			},
+			"tags": []interface{}{"a", "b"},
+		},
+	}}
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:   "terraform-provider-crd",
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:app":{}}},` +
+				`"f:spec":{"f:image":{},"f:ports":{"k:{\"port\":80}":{".":{},"f:port":{}}},"f:tags":{"v:\"a\"":{}}}}`)},
+		},
+		{
+			Manager:   "terraform-provider-crd",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:ports":{"k:{\"port\":80}":{"f:protocol":{}}}}}`)},
+		},
+		{
+			Manager:   "hpa-controller",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		},
+		{
+			Manager:     "terraform-provider-crd",
+			Operation:   metav1.ManagedFieldsOperationApply,
+			Subresource: "status",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)},
+		},
+	})
+
+	if owned := ownedFields(live, "kubectl"); owned != nil {
+		t.Errorf("expected no fields owned by other managers, got %v", owned)
+	}
+	owned := ownedFields(live, "terraform-provider-crd")
+	if _, ok := owned["f:status"]; ok {
+		t.Error("expected fields written through subresources to be left out")
+	}
+	pruneServerFields(live.Object)
+	got := ownedObject(live.Object, owned)
+	want := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata": map[string]interface{}{
+			"name":      "test",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"app": "test"},
+		},
+		"spec": map[string]interface{}{
+			"image": "nginx",
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+			},
+			"tags": []interface{}{"a"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,332 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+var _ function.Function = &ManifestDecodeFunction{}
+var _ function.Function = &ManifestDecodeMultiFunction{}
+var _ function.Function = &ManifestEncodeFunction{}
+var _ function.Function = &MergeManifestsFunction{}
+
+func NewManifestDecodeFunction() function.Function {
+	return &ManifestDecodeFunction{}
+}
+
+// ManifestDecodeFunction parses a manifest holding a single object.
+type ManifestDecodeFunction struct{}
+
+func (f *ManifestDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "manifest_decode"
+}
+
+func (f *ManifestDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Decode a Kubernetes manifest",
+		MarkdownDescription: "Parses a YAML or JSON manifest holding a single object into an object value, whose attributes are named after the fields of the manifest.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "manifest",
+				MarkdownDescription: "Manifest to decode.",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ManifestDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var manifest string
+	resp.Error = req.Arguments.Get(ctx, &manifest)
+	if resp.Error != nil {
+		return
+	}
+	objs, err := decodeManifests(manifest)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	if len(objs) != 1 {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("expected a single object, got %d; use manifest_decode_multi to decode manifests holding several", len(objs)))
+		return
+	}
+	v, err := dynamicValueFromObject(objs[0])
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = setDynamicResult(ctx, resp, v)
+}
+
+func NewManifestDecodeMultiFunction() function.Function {
+	return &ManifestDecodeMultiFunction{}
+}
+
+// ManifestDecodeMultiFunction parses a manifest holding any number of
+// objects, such as the YAML documents of a release bundle.
+type ManifestDecodeMultiFunction struct{}
+
+func (f *ManifestDecodeMultiFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "manifest_decode_multi"
+}
+
+func (f *ManifestDecodeMultiFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Decode a multi-document Kubernetes manifest",
+		MarkdownDescription: "Parses a YAML manifest of documents separated by `---`, or a JSON manifest, into a tuple of object values in the order they appear. Empty documents are skipped.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "manifest",
+				MarkdownDescription: "Manifest to decode.",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ManifestDecodeMultiFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var manifest string
+	resp.Error = req.Arguments.Get(ctx, &manifest)
+	if resp.Error != nil {
+		return
+	}
+	objs, err := decodeManifests(manifest)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	items := make([]interface{}, 0, len(objs))
+	for _, o := range objs {
+		items = append(items, o)
+	}
+	v, err := dynamicValueFromObject(items)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = setDynamicResult(ctx, resp, v)
+}
+
+func NewManifestEncodeFunction() function.Function {
+	return &ManifestEncodeFunction{}
+}
+
+// ManifestEncodeFunction renders objects as a YAML manifest, the inverse of
+// manifest_decode and manifest_decode_multi.
+type ManifestEncodeFunction struct{}
+
+func (f *ManifestEncodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "manifest_encode"
+}
+
+func (f *ManifestEncodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Encode a Kubernetes manifest",
+		MarkdownDescription: "Renders an object value as a YAML manifest, with its fields sorted the way `kubectl` prints them and null attributes left out. A list or tuple of objects is rendered as a manifest of documents separated by `---`.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:                "object",
+				MarkdownDescription: "Object to encode, with attributes named after the fields of the manifest, or a list of such objects.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ManifestEncodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var arg types.Dynamic
+	resp.Error = req.Arguments.Get(ctx, &arg)
+	if resp.Error != nil {
+		return
+	}
+	v, err := arg.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	o, err := objectFromValue(nil, v)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	manifest, err := encodeManifests(o)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, manifest)
+}
+
+func NewMergeManifestsFunction() function.Function {
+	return &MergeManifestsFunction{}
+}
+
+// MergeManifestsFunction applies an overlay to a base object the way a JSON
+// merge patch is applied.
+type MergeManifestsFunction struct{}
+
+func (f *MergeManifestsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "merge_manifests"
+}
+
+func (f *MergeManifestsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Merge an overlay into a base object",
+		MarkdownDescription: "Merges `overlay` into `base` following the JSON merge patch rules of RFC 7386: objects and maps are merged recursively, null attributes of `overlay` remove the fields of `base`, and any other value of `overlay`, lists included, replaces the one of `base`. Custom resources carry no strategic merge metadata, so lists aren't merged by key.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:                "base",
+				MarkdownDescription: "Object to merge into.",
+			},
+			function.DynamicParameter{
+				Name:                "overlay",
+				MarkdownDescription: "Object to merge.",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *MergeManifestsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var base, overlay types.Dynamic
+	resp.Error = req.Arguments.Get(ctx, &base, &overlay)
+	if resp.Error != nil {
+		return
+	}
+	bv, err := base.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	ov, err := overlay.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	v, err := mergePatch(bv, ov)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = setDynamicResult(ctx, resp, v)
+}
+
+// mergePatch merges patch into v following RFC 7386. Maps are merged like
+// objects, and the result of merging them is an object.
+func mergePatch(v, patch tftypes.Value) (tftypes.Value, error) {
+	if !isObjectValue(patch) {
+		return patch, nil
+	}
+	merged := map[string]tftypes.Value{}
+	if isObjectValue(v) && !v.IsNull() {
+		if err := v.As(&merged); err != nil {
+			return tftypes.Value{}, err
+		}
+	}
+	var pm map[string]tftypes.Value
+	if err := patch.As(&pm); err != nil {
+		return tftypes.Value{}, err
+	}
+	for k, pv := range pm {
+		if pv.IsNull() {
+			delete(merged, k)
+			continue
+		}
+		mv, ok := merged[k]
+		if !ok {
+			mv = tftypes.NewValue(tftypes.DynamicPseudoType, nil)
+		}
+		mv, err := mergePatch(mv, pv)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		merged[k] = mv
+	}
+	at := make(map[string]tftypes.Type, len(merged))
+	for k, mv := range merged {
+		at[k] = mv.Type()
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: at}, merged), nil
+}
+
+func isObjectValue(v tftypes.Value) bool {
+	return v.Type().Is(tftypes.Object{}) || v.Type().Is(tftypes.Map{})
+}
+
+// decodeManifests parses the objects of a YAML or JSON manifest, skipping
+// empty documents.
+func decodeManifests(manifest string) ([]map[string]interface{}, error) {
+	r := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
+	objs := []map[string]interface{}{}
+	for i := 1; ; i++ {
+		doc, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document %d: %w", i, err)
+		}
+		j, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", i, err)
+		}
+		var o interface{}
+		d := json.NewDecoder(bytes.NewReader(j))
+		d.UseNumber()
+		if err := d.Decode(&o); err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", i, err)
+		}
+		switch o := o.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			objs = append(objs, o)
+		default:
+			return nil, fmt.Errorf("document %d doesn't hold an object", i)
+		}
+	}
+}
+
+// encodeManifests renders an object, or a list of objects, as YAML.
+func encodeManifests(o interface{}) (string, error) {
+	objs, ok := o.([]interface{})
+	if !ok {
+		objs = []interface{}{o}
+	}
+	docs := make([]string, 0, len(objs))
+	for i, obj := range objs {
+		if _, ok := obj.(map[string]interface{}); !ok {
+			if len(objs) == 1 {
+				return "", errors.New("expected an object")
+			}
+			return "", fmt.Errorf("element %d isn't an object", i)
+		}
+		y, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(y))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// setDynamicResult sets the result of a function returning a dynamic value.
+func setDynamicResult(ctx context.Context, resp *function.RunResponse, v tftypes.Value) *function.FuncError {
+	av, err := types.DynamicType.ValueFromTerraform(ctx, v)
+	if err != nil {
+		return function.NewFuncError(err.Error())
+	}
+	return resp.Result.Set(ctx, av)
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// runFunction calls f with args and returns its result as a Terraform value.
+func runFunction(t *testing.T, f function.Function, args ...attr.Value) (tftypes.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+	dresp := &function.DefinitionResponse{}
+	f.Definition(ctx, function.DefinitionRequest{}, dresp)
+	result, ferr := dresp.Definition.Return.NewResultData(ctx)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	resp := &function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+	if resp.Error != nil {
+		return tftypes.Value{}, resp.Error
+	}
+	v := resp.Result.Value()
+	if dv, ok := v.(types.Dynamic); ok {
+		v = dv.UnderlyingValue()
+	}
+	tv, err := v.ToTerraformValue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tv, nil
+}
+
+func TestManifestDecodeFunction(t *testing.T) {
+	v, ferr := runFunction(t, NewManifestDecodeFunction(), types.StringValue(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: example
+spec:
+  replicas: 3
+  ports: [80, "http"]
+`))
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	var obj map[string]tftypes.Value
+	if err := v.As(&obj); err != nil {
+		t.Fatal(err)
+	}
+	var kind string
+	if err := obj["kind"].As(&kind); err != nil || kind != "Widget" {
+		t.Errorf("expected kind Widget, got %q (err: %v)", kind, err)
+	}
+	var spec map[string]tftypes.Value
+	if err := obj["spec"].As(&spec); err != nil {
+		t.Fatal(err)
+	}
+	var replicas big.Float
+	if err := spec["replicas"].As(&replicas); err != nil || replicas.Cmp(big.NewFloat(3)) != 0 {
+		t.Errorf("expected 3 replicas, got %s (err: %v)", replicas.String(), err)
+	}
+	if !spec["ports"].Type().Is(tftypes.Tuple{}) {
+		t.Errorf("expected ports to be a tuple, got %s", spec["ports"].Type())
+	}
+
+	if _, ferr := runFunction(t, NewManifestDecodeFunction(), types.StringValue("kind: A\n---\nkind: B\n")); ferr == nil {
+		t.Error("expected an error decoding several objects")
+	}
+	if _, ferr := runFunction(t, NewManifestDecodeFunction(), types.StringValue("- a\n- b\n")); ferr == nil {
+		t.Error("expected an error decoding a list")
+	}
+}
+
+func TestManifestDecodeMultiFunction(t *testing.T) {
+	v, ferr := runFunction(t, NewManifestDecodeMultiFunction(), types.StringValue("---\nkind: A\n---\n# nothing\n---\nkind: B\n"))
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	var items []tftypes.Value
+	if err := v.As(&items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(items))
+	}
+	var obj map[string]tftypes.Value
+	var kind string
+	if err := items[1].As(&obj); err != nil {
+		t.Fatal(err)
+	}
+	if err := obj["kind"].As(&kind); err != nil || kind != "B" {
+		t.Errorf("expected kind B, got %q (err: %v)", kind, err)
+	}
+
+	v, ferr = runFunction(t, NewManifestDecodeMultiFunction(), types.StringValue(`{"kind": "A"}`))
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	if err := v.As(&items); err != nil || len(items) != 1 {
+		t.Errorf("expected a JSON manifest to decode into 1 object, got %d (err: %v)", len(items), err)
+	}
+}
+
+func TestManifestEncodeFunction(t *testing.T) {
+	obj := types.ObjectValueMust(
+		map[string]attr.Type{
+			"kind":       types.StringType,
+			"apiVersion": types.StringType,
+			"spec":       types.ObjectType{AttrTypes: map[string]attr.Type{"replicas": types.NumberType, "paused": types.BoolType}},
+			"status":     types.StringType,
+		},
+		map[string]attr.Value{
+			"kind":       types.StringValue("Widget"),
+			"apiVersion": types.StringValue("example.com/v1"),
+			"spec": types.ObjectValueMust(
+				map[string]attr.Type{"replicas": types.NumberType, "paused": types.BoolType},
+				map[string]attr.Value{"replicas": types.NumberValue(big.NewFloat(3)), "paused": types.BoolValue(false)},
+			),
+			"status": types.StringNull(),
+		},
+	)
+	want := "apiVersion: example.com/v1\nkind: Widget\nspec:\n  paused: false\n  replicas: 3\n"
+	v, ferr := runFunction(t, NewManifestEncodeFunction(), types.DynamicValue(obj))
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	var got string
+	if err := v.As(&got); err != nil || got != want {
+		t.Errorf("expected %q, got %q (err: %v)", want, got, err)
+	}
+
+	list := types.TupleValueMust([]attr.Type{obj.Type(context.Background()), obj.Type(context.Background())}, []attr.Value{obj, obj})
+	v, ferr = runFunction(t, NewManifestEncodeFunction(), types.DynamicValue(list))
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	if err := v.As(&got); err != nil || got != want+"---\n"+want {
+		t.Errorf("expected two documents, got %q (err: %v)", got, err)
+	}
+
+	if _, ferr := runFunction(t, NewManifestEncodeFunction(), types.DynamicValue(types.StringValue("Widget"))); ferr == nil {
+		t.Error("expected an error encoding a string")
+	}
+}
+
+func TestMergeManifestsFunction(t *testing.T) {
+	ctx := context.Background()
+	decode := func(manifest string) attr.Value {
+		v, ferr := runFunction(t, NewManifestDecodeFunction(), types.StringValue(manifest))
+		if ferr != nil {
+			t.Fatal(ferr)
+		}
+		av, err := types.DynamicType.ValueFromTerraform(ctx, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return av
+	}
+	base := decode(`
+kind: Widget
+metadata:
+  name: example
+  labels: {app: web, tier: frontend}
+spec:
+  replicas: 1
+  ports: [80, 443]
+`)
+	overlay := decode(`
+metadata:
+  labels: {tier: null, env: prod}
+spec:
+  replicas: 3
+  ports: [8080]
+`)
+	v, ferr := runFunction(t, NewMergeManifestsFunction(), base, overlay)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	o, err := objectFromValue(nil, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := encodeManifests(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `kind: Widget
+metadata:
+  labels:
+    app: web
+    env: prod
+  name: example
+spec:
+  ports:
+  - 8080
+  replicas: 3
+`
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// objectMetaSchema describes the subset of ObjectMeta exposed by the generated
+// metadata attribute. It stands in for the metadata property of CRD schemas,
+// which only refers to ObjectMeta and can't be converted as it is.
+var objectMetaSchema = spec.Schema{
+	SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"name":         *spec.StringProperty(),
+			"namespace":    *spec.StringProperty(),
+			"generateName": *spec.StringProperty(),
+			"uid":          *spec.StringProperty(),
+			"labels":       *spec.MapProperty(spec.StringProperty()),
+			"annotations":  *spec.MapProperty(spec.StringProperty()),
+		},
+	},
+}
+
+// withObjectMeta returns a copy of s with its metadata property replaced by
+// objectMetaSchema.
+func withObjectMeta(s *spec.Schema) *spec.Schema {
+	ws := *s
+	ws.Properties = make(map[string]spec.Schema, len(s.Properties)+1)
+	for k, p := range s.Properties {
+		ws.Properties[k] = p
+	}
+	ws.Properties["metadata"] = objectMetaSchema
+	return &ws
+}
+
+// minNamespaceLength rejects empty namespaces, which the API server would
+// otherwise reject when the object is created.
+var minNamespaceLength = int64(1)
+
+// metadataAttribute returns the metadata attribute of resources. Only those
+// of namespaced kinds have a namespace, which is then required, so that
+// objects of the wrong scope are rejected when planning rather than by the
+// API server.
+func metadataAttribute(namespaced bool) schema.Attribute {
+	attrs := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: "Name of the object, unique within its namespace. Computed when `generate_name` is set instead.",
+			Optional:            true,
+			Computed:            true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplaceIfConfigured(),
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"generate_name": schema.StringAttribute{
+			MarkdownDescription: "Prefix the API server uses to generate a unique name when `name` is not set.",
+			Optional:            true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"labels": schema.MapAttribute{
+			MarkdownDescription: "Labels used to organize and select objects.",
+			Optional:            true,
+			ElementType:         types.StringType,
+		},
+		"annotations": schema.MapAttribute{
+			MarkdownDescription: "Arbitrary non-identifying metadata.",
+			Optional:            true,
+			ElementType:         types.StringType,
+		},
+	}
+	if namespaced {
+		attrs["namespace"] = schema.StringAttribute{
+			MarkdownDescription: "Namespace of the object.",
+			Required:            true,
+			Validators:          []validator.String{stringLengthValidator{min: &minNamespaceLength}},
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		}
+	}
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Standard object metadata.",
+		Required:            true,
+		Validators:          []validator.Object{metadataNameValidator{}},
+		Attributes:          attrs,
+	}
+}
+
+var _ validator.Object = metadataNameValidator{}
+
+// metadataNameValidator checks that exactly one of name and generate_name is set.
+type metadataNameValidator struct{}
+
+func (v metadataNameValidator) Description(ctx context.Context) string {
+	return "exactly one of name or generate_name must be set"
+}
+
+func (v metadataNameValidator) MarkdownDescription(ctx context.Context) string {
+	return "exactly one of `name` or `generate_name` must be set"
+}
+
+func (v metadataNameValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	attrs := req.ConfigValue.Attributes()
+	name, gen := attrs["name"], attrs["generate_name"]
+	if name == nil || gen == nil || name.IsUnknown() || gen.IsUnknown() {
+		return
+	}
+	switch {
+	case name.IsNull() && gen.IsNull():
+		resp.Diagnostics.AddAttributeError(req.Path, "Missing Object Name", "One of name or generate_name must be set.")
+	case !name.IsNull() && !gen.IsNull():
+		resp.Diagnostics.AddAttributeError(req.Path, "Conflicting Object Name", "Only one of name or generate_name can be set.")
+	}
+}
+
+// withCommonMetadata returns a copy of obj carrying the labels and annotations
+// set for all resources in the provider configuration. Those set on the object
+// itself take precedence.
+func (r *CustomResource) withCommonMetadata(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if len(r.commonLabels) == 0 && len(r.commonAnnotations) == 0 {
+		return obj
+	}
+	applied := obj.DeepCopy()
+	applied.SetLabels(mergeStringMaps(r.commonLabels, obj.GetLabels()))
+	applied.SetAnnotations(mergeStringMaps(r.commonAnnotations, obj.GetAnnotations()))
+	return applied
+}
+
+// mergeStringMaps returns the union of base and overrides, preferring the
+// values of overrides, or nil if both are empty.
+func mergeStringMaps(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		m[k] = v
+	}
+	for k, v := range overrides {
+		m[k] = v
+	}
+	return m
+}
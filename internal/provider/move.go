@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ resource.ResourceWithMoveState = &CustomResource{}
+
+// MoveState moves the states of resources of other Kubernetes providers
+// managing objects of the same kind, so that moved blocks switch them over
+// without recreating the objects.
+func (r *CustomResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{StateMover: r.moveFromKubernetesManifest},
+		{StateMover: r.moveFromKubectlManifest},
+	}
+}
+
+// isSourceProvider reports whether address, in HOSTNAME/NAMESPACE/TYPE form,
+// is that of the provider source, in NAMESPACE/TYPE form, on any registry.
+func isSourceProvider(address, source string) bool {
+	return strings.HasSuffix(address, "/"+source)
+}
+
+// moveFromKubernetesManifest moves the state of kubernetes_manifest resources
+// of the hashicorp/kubernetes provider, which hold their manifest as a
+// dynamic value.
+func (r *CustomResource) moveFromKubernetesManifest(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	if req.SourceTypeName != "kubernetes_manifest" || !isSourceProvider(req.SourceProviderAddress, "hashicorp/kubernetes") {
+		return
+	}
+	if req.SourceRawState == nil || req.SourceRawState.JSON == nil {
+		resp.Diagnostics.AddError("Unable to Move State", "The source state has no JSON representation.")
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(req.SourceRawState.JSON))
+	dec.UseNumber()
+	var prior map[string]interface{}
+	if err := dec.Decode(&prior); err != nil {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Unable to decode the source state: %s", err))
+		return
+	}
+	// The manifest holds the configured fields only, unlike the object,
+	// which holds those the API server populated as well.
+	var manifest map[string]interface{}
+	if m, ok := prior["manifest"].(map[string]interface{}); ok {
+		dv, _ := dynamicStateValue(nil, m)
+		manifest, _ = dv.(map[string]interface{})
+	}
+	if manifest == nil {
+		resp.Diagnostics.AddError("Unable to Move State", "The source state has no manifest.")
+		return
+	}
+	r.moveManifest(ctx, manifest, resp)
+}
+
+// moveFromKubectlManifest moves the state of kubectl_manifest resources of
+// the gavinbunney/kubectl provider, or of its alekc/kubectl fork, which hold
+// their manifest as YAML.
+func (r *CustomResource) moveFromKubectlManifest(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	if req.SourceTypeName != "kubectl_manifest" || !(isSourceProvider(req.SourceProviderAddress, "gavinbunney/kubectl") || isSourceProvider(req.SourceProviderAddress, "alekc/kubectl")) {
+		return
+	}
+	if req.SourceRawState == nil || req.SourceRawState.JSON == nil {
+		resp.Diagnostics.AddError("Unable to Move State", "The source state has no JSON representation.")
+		return
+	}
+	var prior struct {
+		YAMLBody string `json:"yaml_body"`
+	}
+	if err := json.Unmarshal(req.SourceRawState.JSON, &prior); err != nil {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Unable to decode the source state: %s", err))
+		return
+	}
+	manifests, err := decodeManifests(prior.YAMLBody)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Unable to parse yaml_body: %s", err))
+		return
+	}
+	if len(manifests) != 1 {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Expected yaml_body to hold a single manifest, got %d.", len(manifests)))
+		return
+	}
+	r.moveManifest(ctx, manifests[0], resp)
+}
+
+// moveManifest sets the target state of resp to the object described by
+// manifest, and records the manifest as the last applied configuration, so
+// that only its fields are tracked.
+func (r *CustomResource) moveManifest(ctx context.Context, manifest map[string]interface{}, resp *resource.MoveStateResponse) {
+	obj := &unstructured.Unstructured{Object: manifest}
+	if gvk := obj.GroupVersionKind(); gvk != r.gvk {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf(
+			"The source resource manages a %s of API version %s, not a %s of API version %s.",
+			gvk.Kind, gvk.GroupVersion(), r.gvk.Kind, r.gvk.GroupVersion()))
+		return
+	}
+	v, err := valueFromObject(r.schema, resp.TargetState.Schema.Type().TerraformType(ctx), manifest)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Unable to convert the manifest: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(setLastApplied(ctx, resp.TargetPrivate, manifest)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.TargetState.Raw = v
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// testMoveState runs the state movers of r on the source state raw, as the
+// framework would, returning the response of the first one handling it.
+func testMoveState(t *testing.T, r *CustomResource, address, typeName, raw string) *resource.MoveStateResponse {
+	t.Helper()
+	ctx := context.Background()
+	s := testCustomResourceSchema(t, r)
+	req := resource.MoveStateRequest{
+		SourceProviderAddress: address,
+		SourceTypeName:        typeName,
+		SourceRawState:        &tfprotov6.RawState{JSON: []byte(raw)},
+	}
+	for _, m := range r.MoveState(ctx) {
+		resp := &resource.MoveStateResponse{TargetState: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+		// The framework initializes the private state, whose type is
+		// internal to it.
+		reflect.ValueOf(&resp.TargetPrivate).Elem().Set(reflect.New(reflect.TypeOf(resp.TargetPrivate).Elem()))
+		m.StateMover(ctx, req, resp)
+		if resp.Diagnostics.HasError() || !resp.TargetState.Raw.IsNull() {
+			return resp
+		}
+	}
+	return nil
+}
+
+func TestMoveStateFromKubernetesManifest(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+	raw := `{
+		"manifest": {
+			"type": ["object", {}],
+			"value": {
+				"apiVersion": "example.com/v1",
+				"kind": "Widget",
+				"metadata": {"name": "test", "namespace": "default"},
+				"spec": {"replicas": 3, "image": "nginx"}
+			}
+		},
+		"object": null,
+		"field_manager": null
+	}`
+
+	if resp := testMoveState(t, r, "registry.terraform.io/hashicorp/aws", "kubernetes_manifest", raw); resp != nil {
+		t.Errorf("expected states of other providers to be left to other movers, got %v", resp.Diagnostics)
+	}
+
+	resp := testMoveState(t, r, "registry.terraform.io/hashicorp/kubernetes", "kubernetes_manifest", raw)
+	if resp == nil || resp.Diagnostics.HasError() {
+		t.Fatalf("expected the state to be moved, got %v", resp)
+	}
+	var av, spec map[string]tftypes.Value
+	if err := resp.TargetState.Raw.As(&av); err != nil {
+		t.Fatal(err)
+	}
+	if err := av["spec"].As(&spec); err != nil {
+		t.Fatal(err)
+	}
+	if !spec["image"].Equal(tftypes.NewValue(tftypes.String, "nginx")) {
+		t.Errorf("unexpected image: %s", spec["image"])
+	}
+	la, diags := getLastApplied(context.Background(), resp.TargetPrivate)
+	if diags.HasError() || len(la) == 0 {
+		t.Errorf("expected the manifest to be recorded as last applied, got %s %v", la, diags)
+	}
+
+	mismatched := `{"manifest": {"type": ["object", {}], "value": {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "test"}}}}`
+	if resp := testMoveState(t, r, "registry.terraform.io/hashicorp/kubernetes", "kubernetes_manifest", mismatched); resp == nil || !resp.Diagnostics.HasError() {
+		t.Error("expected an error for a manifest of another kind")
+	}
+}
+
+func TestMoveStateFromKubectlManifest(t *testing.T) {
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+	raw := `{
+		"yaml_body": "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: test\n  namespace: default\nspec:\n  replicas: 3\n  image: nginx\n",
+		"yaml_incluster": "sha256",
+		"kind": "Widget",
+		"name": "test"
+	}`
+
+	for _, address := range []string{"registry.terraform.io/gavinbunney/kubectl", "registry.terraform.io/alekc/kubectl"} {
+		resp := testMoveState(t, r, address, "kubectl_manifest", raw)
+		if resp == nil || resp.Diagnostics.HasError() {
+			t.Fatalf("%s: expected the state to be moved, got %v", address, resp)
+		}
+		var av, spec map[string]tftypes.Value
+		if err := resp.TargetState.Raw.As(&av); err != nil {
+			t.Fatal(err)
+		}
+		if err := av["spec"].As(&spec); err != nil {
+			t.Fatal(err)
+		}
+		if !spec["replicas"].Equal(tftypes.NewValue(tftypes.Number, 3)) {
+			t.Errorf("%s: unexpected replicas: %s", address, spec["replicas"])
+		}
+	}
+
+	multi := `{"yaml_body": "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: a\n---\napiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: b\n"}`
+	if resp := testMoveState(t, r, "registry.terraform.io/gavinbunney/kubectl", "kubectl_manifest", multi); resp == nil || !resp.Diagnostics.HasError() {
+		t.Error("expected an error for several manifests")
+	}
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stoewer/go-strcase"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Strategies for naming the generated resources.
+const (
+	// namingFull joins the group, version and singular name of kinds, as in
+	// networking_istio_io_v1beta1_virtualservice.
+	namingFull = "full"
+	// namingNoDomain keeps only the first label of the group, as in
+	// networking_v1beta1_virtualservice.
+	namingNoDomain = "no_domain"
+	// namingKind uses the singular name of kinds alone, as in
+	// virtualservice.
+	namingKind = "kind"
+	// namingShortName uses the first short name of kinds, as in vs, falling
+	// back to their singular name.
+	namingShortName = "short_name"
+)
+
+// resourceNamePattern matches the names resources can be renamed to.
+var resourceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// resourceName returns the name of the resources generated for the version
+// of the kind of group with names, without the provider prefix. Rename rules
+// for the version take precedence over those for the whole definition, which
+// take precedence over the naming strategy.
+func (o schemaOptions) resourceName(version, group string, names apiextv1.CustomResourceDefinitionNames) string {
+	crd := names.Plural + "." + group
+	if n, ok := o.renames[crd+"/"+version]; ok {
+		return n
+	}
+	if n, ok := o.renames[crd]; ok {
+		return n
+	}
+	switch o.naming {
+	case namingNoDomain:
+		return resourceName(version, strings.SplitN(group, ".", 2)[0], names.Singular)
+	case namingKind:
+		return names.Singular
+	case namingShortName:
+		if len(names.ShortNames) > 0 {
+			return names.ShortNames[0]
+		}
+		return names.Singular
+	default:
+		return resourceName(version, group, names.Singular)
+	}
+}
+
+// resourceNameCollisions returns the names given to more than one of kinds,
+// mapped to the kinds sharing them, formatted like kind (group/version).
+func resourceNameCollisions(kinds []customKind) map[string][]string {
+	owners := make(map[string][]string)
+	for _, k := range kinds {
+		owners[k.name] = append(owners[k.name], fmt.Sprintf("%s (%s/%s)", k.names.Kind, k.group, k.version))
+	}
+	for n, o := range owners {
+		if len(o) < 2 {
+			delete(owners, n)
+		}
+	}
+	return owners
+}
+
+// attributeNames maps the properties of s to the names of the attributes
+// generated for them, which are the snake_case forms of the field names.
+// When several properties share that form, such as hostIP and hostIp, the one
+// already written in snake_case, or else the first in lexical order, keeps
+// it and the others get a numbered suffix.
+//
+// Field names can't be kept as they are: the framework rejects schemas with
+// attribute names other than lowercase letters, digits and underscores, which
+// rules out the camelCase names of most fields.
+func attributeNames(s *spec.Schema) map[string]string {
+	if s == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := keys[i] == strcase.SnakeCase(keys[i]), keys[j] == strcase.SnakeCase(keys[j])
+		if ki != kj {
+			return ki
+		}
+		return keys[i] < keys[j]
+	})
+
+	names := make(map[string]string, len(keys))
+	taken := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		n := strcase.SnakeCase(k)
+		for i := 2; taken[n]; i++ {
+			n = fmt.Sprintf("%s_%d", strcase.SnakeCase(k), i)
+		}
+		names[k] = n
+		taken[n] = true
+	}
+	return names
+}
+
+// attributeNameDiagnostics warns about the properties whose attribute names
+// were disambiguated by attributeNames. p is the path of the attribute holding
+// them.
+func attributeNameDiagnostics(p path.Path, names map[string]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	owners := make(map[string]string, len(names))
+	for k, n := range names {
+		owners[n] = k
+	}
+	keys := make([]string, 0, len(names))
+	for k := range names {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		base := strcase.SnakeCase(k)
+		if names[k] == base {
+			continue
+		}
+		diags.AddWarning("Attribute Name Collision", fmt.Sprintf(
+			"Fields %q and %q both map to attribute %s. Field %q is available as %s instead.",
+			owners[base], k, p.AtName(base), k, p.AtName(names[k])))
+	}
+	return diags
+}
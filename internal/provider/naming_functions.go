@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/stoewer/go-strcase"
+)
+
+var _ function.Function = &ToSnakeCaseFunction{}
+var _ function.Function = &ToCamelCaseFunction{}
+
+func NewToSnakeCaseFunction() function.Function {
+	return &ToSnakeCaseFunction{}
+}
+
+// ToSnakeCaseFunction converts field names to attribute names the way the
+// schemas of custom resources are generated.
+type ToSnakeCaseFunction struct{}
+
+func (f *ToSnakeCaseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_snake_case"
+}
+
+func (f *ToSnakeCaseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Convert a field name to an attribute name",
+		MarkdownDescription: "Converts the name of a manifest field, such as `hostIP`, to the name of the attribute generated for it, such as `host_ip`. Fields whose names collide, such as `hostIP` and `hostIp`, are told apart by a numbered suffix which this function can't know about.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "Field name to convert.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ToSnakeCaseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	resp.Error = req.Arguments.Get(ctx, &name)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, strcase.SnakeCase(name))
+}
+
+func NewToCamelCaseFunction() function.Function {
+	return &ToCamelCaseFunction{}
+}
+
+// ToCamelCaseFunction converts attribute names back to field names.
+type ToCamelCaseFunction struct{}
+
+func (f *ToCamelCaseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_camel_case"
+}
+
+func (f *ToCamelCaseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Convert an attribute name to a field name",
+		MarkdownDescription: "Converts a snake_case attribute name, such as `min_ready_seconds`, to the camelCase form used by manifest fields, such as `minReadySeconds`. Initialisms aren't recovered: `host_ip` becomes `hostIp`, not `hostIP`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "Attribute name to convert.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ToCamelCaseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	resp.Error = req.Arguments.Get(ctx, &name)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, strcase.LowerCamelCase(name))
+}
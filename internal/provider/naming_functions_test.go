@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestCaseFunctions(t *testing.T) {
+	cases := []struct {
+		field, attribute, roundTrip string
+	}{
+		{"minReadySeconds", "min_ready_seconds", "minReadySeconds"},
+		{"hostIP", "host_ip", "hostIp"},
+		{"apiVersion", "api_version", "apiVersion"},
+		{"x509Certificate", "x509certificate", "x509certificate"},
+		{"replicas", "replicas", "replicas"},
+	}
+	for _, c := range cases {
+		v, ferr := runFunction(t, NewToSnakeCaseFunction(), types.StringValue(c.field))
+		if ferr != nil {
+			t.Fatal(ferr)
+		}
+		var got string
+		if err := v.As(&got); err != nil || got != c.attribute {
+			t.Errorf("to_snake_case(%q): expected %q, got %q (err: %v)", c.field, c.attribute, got, err)
+		}
+		if names := attributeNames(&spec.Schema{SchemaProps: spec.SchemaProps{Properties: map[string]spec.Schema{c.field: {}}}}); names[c.field] != got {
+			t.Errorf("to_snake_case(%q) = %q doesn't match generated attribute %q", c.field, got, names[c.field])
+		}
+
+		v, ferr = runFunction(t, NewToCamelCaseFunction(), types.StringValue(c.attribute))
+		if ferr != nil {
+			t.Fatal(ferr)
+		}
+		if err := v.As(&got); err != nil || got != c.roundTrip {
+			t.Errorf("to_camel_case(%q): expected %q, got %q (err: %v)", c.attribute, c.roundTrip, got, err)
+		}
+	}
+}
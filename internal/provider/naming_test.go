@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestSchemaOptionsResourceName(t *testing.T) {
+	vs := apiextv1.CustomResourceDefinitionNames{Kind: "VirtualService", Singular: "virtualservice", Plural: "virtualservices", ShortNames: []string{"vs"}}
+	gw := apiextv1.CustomResourceDefinitionNames{Kind: "Gateway", Singular: "gateway", Plural: "gateways"}
+	cases := []struct {
+		naming string
+		names  apiextv1.CustomResourceDefinitionNames
+		want   string
+	}{
+		{namingFull, vs, "networking_istio_io_v1beta1_virtualservice"},
+		{namingNoDomain, vs, "networking_v1beta1_virtualservice"},
+		{namingKind, vs, "virtualservice"},
+		{namingShortName, vs, "vs"},
+		{namingShortName, gw, "gateway"},
+	}
+	for _, c := range cases {
+		t.Setenv("CRD_RESOURCE_NAMING", c.naming)
+		o, err := schemaOptionsFromEnv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := o.resourceName("v1beta1", "networking.istio.io", c.names); got != c.want {
+			t.Errorf("%s: expected %s, got %s", c.naming, c.want, got)
+		}
+	}
+
+	t.Setenv("CRD_RESOURCE_RENAMES", "virtualservices.networking.istio.io=istio_virtual_service, gateways.networking.istio.io/v1beta1=istio_gateway_beta")
+	o, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := o.resourceName("v1", "networking.istio.io", vs); got != "istio_virtual_service" {
+		t.Errorf("expected the definition to be renamed, got %s", got)
+	}
+	if got := o.resourceName("v1beta1", "networking.istio.io", gw); got != "istio_gateway_beta" {
+		t.Errorf("expected the version to be renamed, got %s", got)
+	}
+	if got := o.resourceName("v1", "networking.istio.io", gw); got != "gateway" {
+		t.Errorf("expected other versions to follow the naming strategy, got %s", got)
+	}
+
+	for _, env := range []string{"CRD_RESOURCE_NAMING=short", "CRD_RESOURCE_RENAMES=widgets.example.com", "CRD_RESOURCE_RENAMES=widgets.example.com=Widget"} {
+		k, v, _ := strings.Cut(env, "=")
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(k, v)
+			if _, err := schemaOptionsFromEnv(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestNameKindsCollisions(t *testing.T) {
+	kind := func(group, singular string) customKind {
+		return customKind{version: "v1", group: group, names: apiextv1.CustomResourceDefinitionNames{Kind: singular, Singular: singular, Plural: singular + "s"}}
+	}
+	p := &KubernetesCRD{options: schemaOptions{naming: namingKind}}
+	kinds := p.nameKinds([]customKind{kind("a.example.com", "widget"), kind("b.example.com", "widget"), kind("a.example.com", "gadget")})
+	if len(kinds) != 1 || kinds[0].name != "gadget" {
+		t.Errorf("expected only gadget to be named, got %v", kinds)
+	}
+	if !p.discoveryDiags.HasError() || !containsDetail(p.discoveryDiags, "widget (a.example.com/v1)") {
+		t.Errorf("expected the collision to be reported, got %v", p.discoveryDiags)
+	}
+}
+
+func TestAttributeNames(t *testing.T) {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"hostIP":   *spec.StringProperty(),
+			"hostIp":   *spec.StringProperty(),
+			"TTL":      *spec.Int64Property(),
+			"ttl":      *spec.Int64Property(),
+			"replicas": *spec.Int64Property(),
+		},
+	}}
+	names := attributeNames(s)
+	want := map[string]string{
+		"hostIP":   "host_ip",
+		"hostIp":   "host_ip_2",
+		"TTL":      "ttl_2",
+		"ttl":      "ttl",
+		"replicas": "replicas",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	diags := attributeNameDiagnostics(path.Root("spec"), names)
+	if diags.WarningsCount() != 2 {
+		t.Fatalf("expected 2 warnings, got %v", diags)
+	}
+	if d := diags[0].Detail(); !strings.Contains(d, `"TTL"`) || !strings.Contains(d, "spec.ttl_2") {
+		t.Errorf("unexpected warning: %s", d)
+	}
+
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"host_ip":   tftypes.String,
+		"host_ip_2": tftypes.String,
+		"ttl":       tftypes.Number,
+		"ttl_2":     tftypes.Number,
+		"replicas":  tftypes.Number,
+	}}
+	obj := map[string]interface{}{
+		"hostIP": "10.0.0.1",
+		"hostIp": "10.0.0.2",
+		"TTL":    int64(30),
+		"ttl":    int64(60),
+	}
+	v, err := valueFromObject(s, typ, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := objectFromValue(s, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, obj) {
+		t.Errorf("round trip mismatch:\n got: %#v\nwant: %#v", got, obj)
+	}
+}
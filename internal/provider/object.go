@@ -0,0 +1,521 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// inlineFieldsExtension marks the properties of schemas generated by the
+// provider whose fields are those of the enclosing object, rather than those
+// of a field of the same name.
+const inlineFieldsExtension = "x-terraform-crd-inline-fields"
+
+// notInlined are the fields of objects never held by inline properties:
+// those identifying the kind, which the provider sets, and the status, which
+// controllers write.
+var notInlined = map[string]bool{"apiVersion": true, "kind": true, "status": true}
+
+// isInline reports whether the fields of the property described by s are
+// those of the enclosing object.
+func isInline(s *spec.Schema) bool {
+	v, ok := s.Extensions[inlineFieldsExtension].(bool)
+	return ok && v
+}
+
+// inlineFields returns the fields of mo held by the inline property of s,
+// those not declared by its other properties, or nil if there are none.
+func inlineFields(s *spec.Schema, mo map[string]interface{}) interface{} {
+	fields := make(map[string]interface{}, len(mo))
+	for k, e := range mo {
+		if _, ok := s.Properties[k]; !ok && !notInlined[k] {
+			fields[k] = e
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// objectFromValue converts a Terraform value into its unstructured Kubernetes
+// representation. The OpenAPI schema is used to map attribute names back to the
+// original field names. Null and unknown values convert to nil and are left out
+// of the enclosing object.
+func objectFromValue(s *spec.Schema, v tftypes.Value) (interface{}, error) {
+	if v.IsNull() || !v.IsKnown() {
+		return nil, nil
+	}
+	if s != nil && len(s.AllOf) > 0 {
+		s = flattenAllOf(s)
+	}
+	if isSchemaless(s) {
+		// Dynamic values carry the manifest field names as-is.
+		s = nil
+	}
+	t := v.Type()
+	switch {
+	case t.Is(tftypes.String):
+		var sv string
+		if err := v.As(&sv); err != nil {
+			return nil, err
+		}
+		if isIntOrString(s) {
+			return intOrStringFromValue(sv), nil
+		}
+		return sv, nil
+	case t.Is(tftypes.Bool):
+		var bv bool
+		err := v.As(&bv)
+		return bv, err
+	case t.Is(tftypes.Number):
+		var nv big.Float
+		if err := v.As(&nv); err != nil {
+			return nil, err
+		}
+		if nv.IsInt() {
+			iv, _ := nv.Int64()
+			return iv, nil
+		}
+		fv, _ := nv.Float64()
+		return fv, nil
+	case t.Is(tftypes.List{}), t.Is(tftypes.Set{}), t.Is(tftypes.Tuple{}):
+		var ev []tftypes.Value
+		if err := v.As(&ev); err != nil {
+			return nil, err
+		}
+		var es *spec.Schema
+		if s != nil && s.Items != nil {
+			es = s.Items.Schema
+		}
+		lo := make([]interface{}, 0, len(ev))
+		for _, e := range ev {
+			o, err := objectFromValue(es, e)
+			if err != nil {
+				return nil, err
+			}
+			lo = append(lo, o)
+		}
+		return lo, nil
+	case t.Is(tftypes.Map{}):
+		var ev map[string]tftypes.Value
+		if err := v.As(&ev); err != nil {
+			return nil, err
+		}
+		var es *spec.Schema
+		if s != nil && s.AdditionalProperties != nil {
+			es = s.AdditionalProperties.Schema
+		}
+		mo := make(map[string]interface{}, len(ev))
+		for k, e := range ev {
+			o, err := objectFromValue(es, e)
+			if err != nil {
+				return nil, err
+			}
+			if o != nil {
+				mo[k] = o
+			}
+		}
+		return mo, nil
+	case t.Is(tftypes.Object{}):
+		var av map[string]tftypes.Value
+		if err := v.As(&av); err != nil {
+			return nil, err
+		}
+		mo := make(map[string]interface{}, len(av))
+		if s == nil || len(s.Properties) == 0 {
+			// Dynamic values carry the manifest field names as-is.
+			for k, e := range av {
+				o, err := objectFromValue(nil, e)
+				if err != nil {
+					return nil, err
+				}
+				if o != nil {
+					mo[k] = o
+				}
+			}
+			return mo, nil
+		}
+		names := attributeNames(s)
+		for k, p := range s.Properties {
+			e, ok := av[names[k]]
+			if !ok {
+				continue
+			}
+			o, err := objectFromValue(&p, e)
+			if err != nil {
+				return nil, err
+			}
+			if fields, ok := o.(map[string]interface{}); ok && isInline(&p) {
+				for fk, fe := range fields {
+					mo[fk] = fe
+				}
+				continue
+			}
+			if o != nil {
+				mo[k] = o
+			}
+		}
+		return mo, nil
+	}
+	return nil, fmt.Errorf("unsupported value type: %s", t)
+}
+
+// valueFromObject converts an unstructured Kubernetes value into a Terraform
+// value of type t. Object attributes are looked up by the snake_case form of
+// the field names declared in the OpenAPI schema.
+func valueFromObject(s *spec.Schema, t tftypes.Type, o interface{}) (tftypes.Value, error) {
+	if s != nil && len(s.AllOf) > 0 {
+		s = flattenAllOf(s)
+	}
+	if t.Is(tftypes.DynamicPseudoType) {
+		return dynamicValueFromObject(o)
+	}
+	if o == nil {
+		return tftypes.NewValue(t, nil), nil
+	}
+	switch {
+	case t.Is(tftypes.String):
+		switch sv := o.(type) {
+		case string:
+			return tftypes.NewValue(t, sv), nil
+		default:
+			return tftypes.NewValue(t, fmt.Sprint(sv)), nil
+		}
+	case t.Is(tftypes.Bool):
+		bv, ok := o.(bool)
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected boolean, got %T", o)
+		}
+		return tftypes.NewValue(t, bv), nil
+	case t.Is(tftypes.Number):
+		nv, err := numberFromObject(o)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(t, nv), nil
+	case t.Is(tftypes.List{}), t.Is(tftypes.Set{}):
+		lo, ok := o.([]interface{})
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected array, got %T", o)
+		}
+		var et tftypes.Type
+		if lt, ok := t.(tftypes.List); ok {
+			et = lt.ElementType
+		} else {
+			et = t.(tftypes.Set).ElementType
+		}
+		var es *spec.Schema
+		if s != nil && s.Items != nil {
+			es = s.Items.Schema
+		}
+		ev := make([]tftypes.Value, 0, len(lo))
+		for _, e := range lo {
+			v, err := valueFromObject(es, et, e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			ev = append(ev, v)
+		}
+		return tftypes.NewValue(t, ev), nil
+	case t.Is(tftypes.Map{}):
+		mo, ok := o.(map[string]interface{})
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected object, got %T", o)
+		}
+		var es *spec.Schema
+		if s != nil && s.AdditionalProperties != nil {
+			es = s.AdditionalProperties.Schema
+		}
+		ev := make(map[string]tftypes.Value, len(mo))
+		for k, e := range mo {
+			v, err := valueFromObject(es, t.(tftypes.Map).ElementType, e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			ev[k] = v
+		}
+		return tftypes.NewValue(t, ev), nil
+	case t.Is(tftypes.Object{}):
+		mo, ok := o.(map[string]interface{})
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected object, got %T", o)
+		}
+		at := t.(tftypes.Object).AttributeTypes
+		av := make(map[string]tftypes.Value, len(at))
+		if s != nil {
+			names := attributeNames(s)
+			for k, p := range s.Properties {
+				n := names[k]
+				et, ok := at[n]
+				if !ok {
+					continue
+				}
+				e := mo[k]
+				if isInline(&p) {
+					e = inlineFields(s, mo)
+				}
+				v, err := valueFromObject(&p, et, e)
+				if err != nil {
+					return tftypes.Value{}, err
+				}
+				av[n] = v
+			}
+		}
+		for n, et := range at {
+			if _, ok := av[n]; !ok {
+				av[n] = tftypes.NewValue(et, nil)
+			}
+		}
+		return tftypes.NewValue(t, av), nil
+	}
+	return tftypes.Value{}, fmt.Errorf("unsupported type: %s", t)
+}
+
+// dynamicValueFromObject infers a Terraform type for an unstructured value
+// that has no schema of its own, as is the case for dynamic attributes.
+func dynamicValueFromObject(o interface{}) (tftypes.Value, error) {
+	switch ov := o.(type) {
+	case nil:
+		return tftypes.NewValue(tftypes.DynamicPseudoType, nil), nil
+	case string:
+		return tftypes.NewValue(tftypes.String, ov), nil
+	case bool:
+		return tftypes.NewValue(tftypes.Bool, ov), nil
+	case []interface{}:
+		et := make([]tftypes.Type, 0, len(ov))
+		ev := make([]tftypes.Value, 0, len(ov))
+		for _, e := range ov {
+			v, err := dynamicValueFromObject(e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			et = append(et, v.Type())
+			ev = append(ev, v)
+		}
+		return tftypes.NewValue(tftypes.Tuple{ElementTypes: et}, ev), nil
+	case map[string]interface{}:
+		at := make(map[string]tftypes.Type, len(ov))
+		av := make(map[string]tftypes.Value, len(ov))
+		for k, e := range ov {
+			v, err := dynamicValueFromObject(e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			at[k] = v.Type()
+			av[k] = v
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: at}, av), nil
+	default:
+		nv, err := numberFromObject(o)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(tftypes.Number, nv), nil
+	}
+}
+
+func numberFromObject(o interface{}) (*big.Float, error) {
+	switch nv := o.(type) {
+	case int:
+		return new(big.Float).SetInt64(int64(nv)), nil
+	case int32:
+		return new(big.Float).SetInt64(int64(nv)), nil
+	case int64:
+		return new(big.Float).SetInt64(nv), nil
+	case float32:
+		return big.NewFloat(float64(nv)), nil
+	case float64:
+		return big.NewFloat(nv), nil
+	case json.Number:
+		f, _, err := big.ParseFloat(nv.String(), 10, 512, big.ToNearestEven)
+		return f, err
+	}
+	return nil, fmt.Errorf("expected number, got %T", o)
+}
+
+// serverManagedFields lists metadata fields populated by the API server which
+// never form part of a resource's configuration.
+var serverManagedFields = []string{
+	"managedFields",
+	"resourceVersion",
+	"uid",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"selfLink",
+}
+
+// pruneServerFields removes server-managed metadata from obj.
+func pruneServerFields(obj map[string]interface{}) {
+	md, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, f := range serverManagedFields {
+		delete(md, f)
+	}
+}
+
+// kubectlLastAppliedAnnotation is the annotation in which kubectl apply
+// records the configuration it applied.
+const kubectlLastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// pruneUnconfiguredFields removes from obj, an object the provider has no
+// last applied configuration for, the fields a configuration wouldn't set:
+// the generated name prefix of objects which have a name, as name and
+// generate_name conflict, and the configuration recorded by kubectl apply.
+// What remains describes the object as a configuration would, so that
+// configurations generated on import are valid as they are.
+func pruneUnconfiguredFields(obj map[string]interface{}) {
+	md, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if md["name"] != nil {
+		delete(md, "generateName")
+	}
+	if an, ok := md["annotations"].(map[string]interface{}); ok {
+		delete(an, kubectlLastAppliedAnnotation)
+		if len(an) == 0 {
+			delete(md, "annotations")
+		}
+	}
+}
+
+// pruneObject drops every field of live which is absent from ref, so that
+// values defaulted by the API server or set by other clients don't show up as
+// drift. Array elements are pruned against the element at the same position
+// in ref; elements beyond the end of ref are kept as they are.
+func pruneObject(live, ref interface{}) interface{} {
+	switch rv := ref.(type) {
+	case map[string]interface{}:
+		lv, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		po := make(map[string]interface{}, len(rv))
+		for k, re := range rv {
+			le, ok := lv[k]
+			if !ok {
+				continue
+			}
+			po[k] = pruneObject(le, re)
+		}
+		return po
+	case []interface{}:
+		lv, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+		po := make([]interface{}, 0, len(lv))
+		for i, le := range lv {
+			if i < len(rv) {
+				le = pruneObject(le, rv[i])
+			}
+			po = append(po, le)
+		}
+		return po
+	}
+	return live
+}
+
+// listMapKeys returns the keys identifying the elements of s if it describes
+// an associative list, i.e. one with x-kubernetes-list-type set to map.
+func listMapKeys(s *spec.Schema) []string {
+	if s == nil {
+		return nil
+	}
+	if lt, _ := s.Extensions.GetString("x-kubernetes-list-type"); lt != "map" {
+		return nil
+	}
+	ks, _ := s.Extensions["x-kubernetes-list-map-keys"].([]interface{})
+	keys := make([]string, 0, len(ks))
+	for _, k := range ks {
+		if sk, ok := k.(string); ok {
+			keys = append(keys, sk)
+		}
+	}
+	return keys
+}
+
+// listMapKey identifies an element of an associative list by the values of
+// its keys.
+func listMapKey(e interface{}, keys []string) string {
+	m, _ := e.(map[string]interface{})
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%#v", m[k])
+	}
+	return strings.Join(parts, "/")
+}
+
+// alignListMaps reorders the elements of associative lists in live to follow
+// their order in ref, matching them by their keys rather than their position.
+// The API server is free to reorder such lists, which would otherwise show up
+// as drift. Elements missing from ref are kept after the others, in order.
+func alignListMaps(s *spec.Schema, live, ref interface{}) interface{} {
+	if s == nil {
+		return live
+	}
+	if len(s.AllOf) > 0 {
+		s = flattenAllOf(s)
+	}
+	switch lv := live.(type) {
+	case map[string]interface{}:
+		rv, _ := ref.(map[string]interface{})
+		ao := make(map[string]interface{}, len(lv))
+		for k, le := range lv {
+			var es *spec.Schema
+			if p, ok := s.Properties[k]; ok {
+				es = &p
+			} else if s.AdditionalProperties != nil {
+				es = s.AdditionalProperties.Schema
+			}
+			ao[k] = alignListMaps(es, le, rv[k])
+		}
+		return ao
+	case []interface{}:
+		var es *spec.Schema
+		if s.Items != nil {
+			es = s.Items.Schema
+		}
+		rv, _ := ref.([]interface{})
+		keys := listMapKeys(s)
+		if len(keys) == 0 {
+			ao := make([]interface{}, len(lv))
+			for i, le := range lv {
+				var re interface{}
+				if i < len(rv) {
+					re = rv[i]
+				}
+				ao[i] = alignListMaps(es, le, re)
+			}
+			return ao
+		}
+		byKey := make(map[string]interface{}, len(lv))
+		for _, le := range lv {
+			byKey[listMapKey(le, keys)] = le
+		}
+		ao := make([]interface{}, 0, len(lv))
+		for _, re := range rv {
+			k := listMapKey(re, keys)
+			if le, ok := byKey[k]; ok {
+				ao = append(ao, alignListMaps(es, le, re))
+				delete(byKey, k)
+			}
+		}
+		for _, le := range lv {
+			if _, ok := byKey[listMapKey(le, keys)]; ok {
+				ao = append(ao, alignListMaps(es, le, nil))
+			}
+		}
+		return ao
+	}
+	return live
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestObjectValueRoundTrip(t *testing.T) {
+	s := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"object"},
+			Properties: map[string]spec.Schema{
+				"replicaCount": *spec.Int64Property(),
+				"hostName":     *spec.StringProperty(),
+				"labels":       *spec.MapProperty(spec.StringProperty()),
+				"ports":        *spec.ArrayProperty(spec.Int64Property()),
+			},
+		},
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"replica_count": tftypes.Number,
+		"host_name":     tftypes.String,
+		"labels":        tftypes.Map{ElementType: tftypes.String},
+		"ports":         tftypes.List{ElementType: tftypes.Number},
+	}}
+	obj := map[string]interface{}{
+		"replicaCount": int64(3),
+		"labels":       map[string]interface{}{"app": "test"},
+		"ports":        []interface{}{int64(80), int64(443)},
+	}
+
+	v, err := valueFromObject(s, typ, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var av map[string]tftypes.Value
+	if err := v.As(&av); err != nil {
+		t.Fatal(err)
+	}
+	if !av["host_name"].IsNull() {
+		t.Errorf("expected host_name to be null, got %s", av["host_name"])
+	}
+
+	got, err := objectFromValue(s, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, obj) {
+		t.Errorf("round trip mismatch:\n got: %#v\nwant: %#v", got, obj)
+	}
+}
+
+func TestDynamicValueFromObject(t *testing.T) {
+	obj := map[string]interface{}{
+		"enabled": true,
+		"items":   []interface{}{"a", int64(1)},
+	}
+	v, err := valueFromObject(nil, tftypes.DynamicPseudoType, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"enabled": tftypes.Bool,
+		"items":   tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Number}},
+	}}
+	if !v.Type().Equal(want) {
+		t.Errorf("unexpected type: %s", v.Type())
+	}
+	got, err := objectFromValue(nil, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, obj) {
+		t.Errorf("round trip mismatch:\n got: %#v\nwant: %#v", got, obj)
+	}
+}
+
+func TestPruneObject(t *testing.T) {
+	live := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas":  int64(2),
+			"defaulted": "by-server",
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+				map[string]interface{}{"port": int64(443), "protocol": "TCP"},
+			},
+		},
+	}
+	ref := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80)},
+			},
+		},
+	}
+	want := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80)},
+				map[string]interface{}{"port": int64(443), "protocol": "TCP"},
+			},
+		},
+	}
+	if got := pruneObject(live, ref); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected result:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestPruneUnconfiguredFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":         "widget-x7k2p",
+			"generateName": "widget-",
+			"annotations":  map[string]interface{}{kubectlLastAppliedAnnotation: "{}"},
+			"labels":       map[string]interface{}{"app": "test"},
+		},
+		"spec": map[string]interface{}{"replicas": int64(1)},
+	}
+	pruneUnconfiguredFields(obj)
+	want := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":   "widget-x7k2p",
+			"labels": map[string]interface{}{"app": "test"},
+		},
+		"spec": map[string]interface{}{"replicas": int64(1)},
+	}
+	if !reflect.DeepEqual(obj, want) {
+		t.Errorf("unexpected result:\n got: %#v\nwant: %#v", obj, want)
+	}
+}
+
+func TestAlignListMaps(t *testing.T) {
+	containers := spec.ArrayProperty(&spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"name":  *spec.StringProperty(),
+			"image": *spec.StringProperty(),
+		},
+	}})
+	containers.AddExtension("x-kubernetes-list-type", "map")
+	containers.AddExtension("x-kubernetes-list-map-keys", []interface{}{"name"})
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"containers": *containers},
+	}}
+
+	live := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "sidecar", "image": "envoy"},
+			map[string]interface{}{"name": "injected", "image": "agent"},
+			map[string]interface{}{"name": "app", "image": "nginx", "imagePullPolicy": "Always"},
+		},
+	}
+	ref := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "nginx"},
+			map[string]interface{}{"name": "sidecar", "image": "envoy"},
+		},
+	}
+	want := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "nginx"},
+			map[string]interface{}{"name": "sidecar", "image": "envoy"},
+			map[string]interface{}{"name": "injected", "image": "agent"},
+		},
+	}
+	if got := pruneObject(alignListMaps(s, live, ref), ref); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected result:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestObjectFromValueEmbeddedResource(t *testing.T) {
+	template := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"apiVersion": *spec.StringProperty(),
+			"kind":       *spec.StringProperty(),
+			"metadata":   {SchemaProps: spec.SchemaProps{Type: []string{"object"}}},
+		},
+	}}
+	template.AddExtension("x-kubernetes-embedded-resource", true)
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"template": template},
+	}}
+
+	tt := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"apiVersion": tftypes.String,
+		"kind":       tftypes.String,
+	}}
+	v := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"template": tt}}, map[string]tftypes.Value{
+		"template": tftypes.NewValue(tt, map[string]tftypes.Value{
+			"apiVersion": tftypes.NewValue(tftypes.String, "v1"),
+			"kind":       tftypes.NewValue(tftypes.String, "ConfigMap"),
+		}),
+	})
+
+	got, err := objectFromValue(s, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"template": map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected object:\n got: %#v\nwant: %#v", got, want)
+	}
+}
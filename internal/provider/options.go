@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// schemaOptions shape the schemas of the generated resources. Terraform asks
+// for schemas before the provider is configured, so they are read from the
+// environment rather than from the provider configuration.
+type schemaOptions struct {
+	// source is where schemas are read from, schemaSourceOpenAPI or
+	// schemaSourceCRD.
+	source string
+	// versions selects the versions of each custom resource definition
+	// resources are generated for, versionsStorage, versionsLatest or
+	// versionsAll.
+	versions string
+	// sensitive maps attribute paths, such as spec.auth.api_key, to whether
+	// the attributes are sensitive, overriding the naming heuristics.
+	sensitive map[string]bool
+	// writeOnly holds the paths of attributes which are write-only.
+	writeOnly map[string]bool
+	// computed holds the paths of attributes the API server may set, which
+	// are computed when left out of the configuration.
+	computed map[string]bool
+	// ephemeral holds the names of the custom resource definitions, such as
+	// widgets.example.com, for which ephemeral resources are generated.
+	ephemeral map[string]bool
+	// include and exclude hold glob patterns, such as *.crossplane.io/*,
+	// matched against the group and kind of custom resource definitions
+	// joined with a slash.
+	include []string
+	exclude []string
+	// aggregated enables generating resources for the kinds served by
+	// aggregated API servers.
+	aggregated bool
+	// manifestPaths holds glob patterns of files custom resource
+	// definitions are read from instead of the cluster.
+	manifestPaths []string
+	// lockFile is the path of the schema lock file, or empty if schemas
+	// aren't locked. lockDrift is how drift from it is reported,
+	// lockDriftError or lockDriftWarn.
+	lockFile  string
+	lockDrift string
+	// naming is the strategy resources are named by, namingFull,
+	// namingNoDomain, namingKind or namingShortName. renames maps the names
+	// of custom resource definitions, optionally followed by a slash and a
+	// version, to the names of their resources, overriding it.
+	naming  string
+	renames map[string]string
+	// cacheDir is the directory converted schemas are cached in, or empty
+	// if they aren't cached.
+	cacheDir string
+}
+
+// schemaOptionsFromEnv reads the schema options from the environment.
+func schemaOptionsFromEnv() (schemaOptions, error) {
+	o := schemaOptions{source: schemaSourceOpenAPI, versions: versionsStorage, naming: namingFull}
+	if v, ok := os.LookupEnv("CRD_SCHEMA_SOURCE"); ok {
+		switch v {
+		case schemaSourceOpenAPI, schemaSourceCRD:
+			o.source = v
+		default:
+			return o, fmt.Errorf("CRD_SCHEMA_SOURCE must be %q or %q, got %q", schemaSourceOpenAPI, schemaSourceCRD, v)
+		}
+	}
+	if v, ok := os.LookupEnv("CRD_VERSIONS"); ok {
+		switch v {
+		case versionsStorage, versionsLatest, versionsAll:
+			o.versions = v
+		default:
+			return o, fmt.Errorf("CRD_VERSIONS must be %q, %q or %q, got %q", versionsStorage, versionsLatest, versionsAll, v)
+		}
+	}
+	if v, ok := os.LookupEnv("CRD_RESOURCE_NAMING"); ok {
+		switch v {
+		case namingFull, namingNoDomain, namingKind, namingShortName:
+			o.naming = v
+		default:
+			return o, fmt.Errorf("CRD_RESOURCE_NAMING must be %q, %q, %q or %q, got %q", namingFull, namingNoDomain, namingKind, namingShortName, v)
+		}
+	}
+	if rules := envList("CRD_RESOURCE_RENAMES"); len(rules) > 0 {
+		o.renames = make(map[string]string, len(rules))
+		for _, r := range rules {
+			crd, name, ok := strings.Cut(r, "=")
+			crd, name = strings.TrimSpace(crd), strings.TrimSpace(name)
+			if !ok || crd == "" || !resourceNamePattern.MatchString(name) {
+				return o, fmt.Errorf("CRD_RESOURCE_RENAMES entries must be of the form widgets.example.com=name or widgets.example.com/v1=name, with names of lowercase letters, digits and underscores, got %q", r)
+			}
+			o.renames[crd] = name
+		}
+	}
+	if paths := envList("CRD_SENSITIVE_ATTRIBUTES"); len(paths) > 0 {
+		o.sensitive = make(map[string]bool, len(paths))
+		for _, p := range paths {
+			if strings.HasPrefix(p, "!") {
+				o.sensitive[strings.TrimPrefix(p, "!")] = false
+			} else {
+				o.sensitive[p] = true
+			}
+		}
+	}
+	if paths := envList("CRD_WRITE_ONLY_ATTRIBUTES"); len(paths) > 0 {
+		o.writeOnly = make(map[string]bool, len(paths))
+		for _, p := range paths {
+			o.writeOnly[p] = true
+		}
+	}
+	if paths := envList("CRD_COMPUTED_ATTRIBUTES"); len(paths) > 0 {
+		o.computed = make(map[string]bool, len(paths))
+		for _, p := range paths {
+			o.computed[p] = true
+		}
+	}
+	if names := envList("CRD_EPHEMERAL_RESOURCES"); len(names) > 0 {
+		o.ephemeral = make(map[string]bool, len(names))
+		for _, n := range names {
+			o.ephemeral[n] = true
+		}
+	}
+	// Definitions read from files have no OpenAPI documents published for
+	// them.
+	if o.manifestPaths = envList("CRD_MANIFEST_PATHS"); len(o.manifestPaths) > 0 {
+		o.source = schemaSourceCRD
+	}
+	if v, ok := os.LookupEnv("CRD_AGGREGATED_APIS"); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return o, fmt.Errorf("CRD_AGGREGATED_APIS must be a boolean, got %q", v)
+		}
+		o.aggregated = b
+	}
+	o.lockFile = os.Getenv("CRD_SCHEMA_LOCK_FILE")
+	o.lockDrift = lockDriftError
+	if v, ok := os.LookupEnv("CRD_SCHEMA_LOCK_DRIFT"); ok {
+		switch v {
+		case lockDriftError, lockDriftWarn:
+			o.lockDrift = v
+		default:
+			return o, fmt.Errorf("CRD_SCHEMA_LOCK_DRIFT must be %q or %q, got %q", lockDriftError, lockDriftWarn, v)
+		}
+	}
+	o.cacheDir = defaultSchemaCacheDir()
+	if v, ok := os.LookupEnv("CRD_SCHEMA_CACHE_DIR"); ok {
+		o.cacheDir = v
+	}
+	o.include = envList("CRD_INCLUDE_CRDS")
+	o.exclude = envList("CRD_EXCLUDE_CRDS")
+	for _, p := range append(append([]string{}, o.include...), o.exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return o, fmt.Errorf("invalid pattern %q in CRD_INCLUDE_CRDS or CRD_EXCLUDE_CRDS: %w", p, err)
+		}
+	}
+	return o, nil
+}
+
+// selects reports whether resources are generated for the custom resource
+// definition of group and kind: it must match one of the include patterns,
+// if any, and none of the exclude patterns.
+func (o schemaOptions) selects(group, kind string) bool {
+	gk := group + "/" + kind
+	matches := func(patterns []string) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, gk); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return (len(o.include) == 0 || matches(o.include)) && !matches(o.exclude)
+}
+
+// envList returns the comma-separated entries of the environment variable
+// env, with surrounding spaces and empty entries left out.
+func envList(env string) []string {
+	var l []string
+	for _, e := range strings.Split(os.Getenv(env), ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			l = append(l, e)
+		}
+	}
+	return l
+}
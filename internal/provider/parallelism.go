@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"net/http"
+
+	"k8s.io/client-go/rest"
+)
+
+// minParallelism is the smallest parallelism accepted.
+var minParallelism = float64(1)
+
+// withParallelism makes the clients created from cfg send at most n requests
+// to the API server at once, across all resources and data sources. Watches,
+// which stay open while waiting for objects, don't count towards it.
+func withParallelism(cfg *rest.Config, n int) {
+	if n <= 0 {
+		return
+	}
+	slots := make(chan struct{}, n)
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &parallelismTransport{next: rt, slots: slots}
+	})
+}
+
+// parallelismTransport holds one of slots while next sends a request.
+type parallelismTransport struct {
+	next  http.RoundTripper
+	slots chan struct{}
+}
+
+func (t *parallelismTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("watch") == "true" {
+		return t.next.RoundTrip(req)
+	}
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.slots }()
+	return t.next.RoundTrip(req)
+}
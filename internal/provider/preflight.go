@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// preflight checks that the API server can be reached and accepts the
+// credentials of clients, and, if listDefinitions is set, that they can list
+// custom resource definitions. Problems are reported when the provider is
+// configured, along with how to resolve them, rather than by each resource.
+// An API server which doesn't exist yet is only reported if unreachable is
+// set, as operations are otherwise deferred until it can be reached.
+func preflight(ctx context.Context, clients *KubernetesClients, listDefinitions, unreachable bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	host := clients.Config.Host
+
+	raw, err := clients.Discovery.RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	switch {
+	case apierrors.IsUnauthorized(err):
+		diags.AddError("Kubernetes Credentials Rejected", fmt.Sprintf(
+			"The API server at %s rejected the credentials of the provider: %s\n\n"+
+				"Check that the token, client certificate or exec plugin configured is valid and hasn't expired.", host, err))
+		return diags
+	case clusterUnavailable(err) && !unreachable:
+		tflog.Debug(ctx, "API server unreachable, skipping preflight checks", map[string]interface{}{"host": host, "error": err.Error()})
+		return diags
+	case clusterUnavailable(err) || errors.As(err, new(*url.Error)):
+		// Other transport errors, such as TLS failures, aren't deferred,
+		// as the cluster exists.
+		diags.AddError("Kubernetes Cluster Unreachable", fmt.Sprintf(
+			"Unable to reach the API server at %s: %s\n\n"+
+				"Check the host and TLS settings of the provider configuration, and that the cluster is running.", host, err))
+		return diags
+	case err != nil:
+		// The API server answered, which is all that matters here.
+		tflog.Debug(ctx, "Failed to get the API server version", map[string]interface{}{"host": host, "error": err.Error()})
+	default:
+		var info version.Info
+		if json.Unmarshal(raw, &info) == nil {
+			tflog.Debug(ctx, "Connected to the API server", map[string]interface{}{"host": host, "server_version": info.GitVersion})
+		}
+	}
+
+	if !listDefinitions {
+		return diags
+	}
+	ac, err := authorizationclient.NewForConfig(clients.Config)
+	if err != nil {
+		return diags
+	}
+	review, err := ac.SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "list",
+				Group:    "apiextensions.k8s.io",
+				Resource: "customresourcedefinitions",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		// Clusters may not serve access reviews, in which case listing is
+		// left to fail on its own.
+		tflog.Debug(ctx, "Failed to review access to custom resource definitions", map[string]interface{}{"error": err.Error()})
+		return diags
+	}
+	if !review.Status.Allowed {
+		detail := "The credentials of the provider cannot list customresourcedefinitions: forbidden. " +
+			"Grant them cluster-wide get and list on customresourcedefinitions.apiextensions.k8s.io, " +
+			"or set CRD_MANIFEST_PATHS to read the definitions from files."
+		if review.Status.Reason != "" {
+			detail += "\n\nThe API server reported: " + review.Status.Reason
+		}
+		diags.AddWarning(definitionsNotReadable, detail)
+	}
+	return diags
+}
+
+// definitionsNotReadable is the summary of the warnings reporting that custom
+// resource definitions can't be listed.
+const definitionsNotReadable = "Custom Resource Definitions Not Readable"
+
+// hasDiagnostic reports whether diags holds a diagnostic with summary.
+func hasDiagnostic(diags diag.Diagnostics, summary string) bool {
+	for _, d := range diags {
+		if d.Summary() == summary {
+			return true
+		}
+	}
+	return false
+}
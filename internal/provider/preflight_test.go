@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/rest"
+)
+
+func TestPreflight(t *testing.T) {
+	const reviewPath = "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews"
+	info := version.Info{Major: "1", Minor: "32", GitVersion: "v1.32.3"}
+	review := func(allowed bool) authorizationv1.SelfSubjectAccessReview {
+		return authorizationv1.SelfSubjectAccessReview{Status: authorizationv1.SubjectAccessReviewStatus{Allowed: allowed}}
+	}
+	ctx := context.Background()
+
+	clients := testDiscoveryServer(t, map[string]interface{}{"/version": info, reviewPath: review(true)})
+	if diags := preflight(ctx, clients, true, true); len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diags)
+	}
+
+	clients = testDiscoveryServer(t, map[string]interface{}{"/version": info, reviewPath: review(false)})
+	diags := preflight(ctx, clients, true, true)
+	if diags.HasError() || diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "customresourcedefinitions.apiextensions.k8s.io") {
+		t.Errorf("expected a warning about listing definitions, got %v", diags)
+	}
+	if diags := preflight(ctx, clients, false, true); len(diags) != 0 {
+		t.Errorf("expected permissions to be left unchecked, got %v", diags)
+	}
+
+	// Clusters which don't serve access reviews are given the benefit of
+	// the doubt.
+	clients = testDiscoveryServer(t, map[string]interface{}{"/version": info})
+	if diags := preflight(ctx, clients, true, true); len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diags)
+	}
+
+	clients, err := NewKubernetesClientForConfig(&rest.Config{Host: "http://127.0.0.1:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := preflight(ctx, clients, true, true); !diags.HasError() {
+		t.Errorf("expected an unreachable API server to be reported, got %v", diags)
+	}
+	if diags := preflight(ctx, clients, true, false); len(diags) != 0 {
+		t.Errorf("expected an unreachable API server to be left to deferrals, got %v", diags)
+	}
+}
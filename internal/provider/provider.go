@@ -5,15 +5,25 @@ package provider
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/kube-openapi/pkg/validation/spec"
@@ -22,6 +32,7 @@ import (
 // Ensure KubernetesCRD satisfies various provider interfaces.
 var _ provider.Provider = &KubernetesCRD{}
 var _ provider.ProviderWithFunctions = &KubernetesCRD{}
+var _ provider.ProviderWithEphemeralResources = &KubernetesCRD{}
 
 // KubernetesCRD defines the provider implementation.
 type KubernetesCRD struct {
@@ -29,12 +40,105 @@ type KubernetesCRD struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// clients are used to discover custom resources. They are created on
+	// first use, so that the provider can start without access to a cluster.
 	clients *KubernetesClients
+
+	// discoveryDiags collects problems found while generating resources,
+	// which happens before the provider can report diagnostics. They are
+	// surfaced when the provider is configured.
+	discoveryDiags diag.Diagnostics
+
+	// kinds are the custom resource kinds found in the cluster, once
+	// discovered.
+	kinds      []customKind
+	discovered bool
+	// options shape the schemas generated for kinds.
+	options schemaOptions
 }
 
 // KubernetesCRDModel describes the provider data model.
 type KubernetesCRDModel struct {
-	Kubeconfig types.String `tfsdk:"kubeconfig"`
+	Kubeconfig           types.String       `tfsdk:"kubeconfig"`
+	ConfigPaths          types.List         `tfsdk:"config_paths"`
+	KubeconfigRaw        types.String       `tfsdk:"kubeconfig_raw"`
+	InCluster            types.Bool         `tfsdk:"in_cluster"`
+	ConfigContext        types.String       `tfsdk:"config_context"`
+	ConfigContextCluster types.String       `tfsdk:"config_context_cluster"`
+	ConfigContextUser    types.String       `tfsdk:"config_context_auth_info"`
+	Host                 types.String       `tfsdk:"host"`
+	Token                types.String       `tfsdk:"token"`
+	ClientCertificate    types.String       `tfsdk:"client_certificate"`
+	ClientKey            types.String       `tfsdk:"client_key"`
+	ClusterCACertificate types.String       `tfsdk:"cluster_ca_certificate"`
+	Insecure             types.Bool         `tfsdk:"insecure"`
+	TLSServerName        types.String       `tfsdk:"tls_server_name"`
+	ProxyURL             types.String       `tfsdk:"proxy_url"`
+	Username             types.String       `tfsdk:"username"`
+	Password             types.String       `tfsdk:"password"`
+	Exec                 *ExecModel         `tfsdk:"exec"`
+	Impersonate          *ImpersonateModel  `tfsdk:"impersonate"`
+	QPS                  types.Float64      `tfsdk:"qps"`
+	Burst                types.Int64        `tfsdk:"burst"`
+	RequestTimeout       types.String       `tfsdk:"request_timeout"`
+	MaxRetries           types.Int64        `tfsdk:"max_retries"`
+	OperationTimeout     types.String       `tfsdk:"operation_timeout"`
+	Parallelism          types.Int64        `tfsdk:"parallelism"`
+	ReadCacheTTL         types.String       `tfsdk:"read_cache_ttl"`
+	FieldManager         *FieldManagerModel `tfsdk:"field_manager"`
+	CommonLabels         types.Map          `tfsdk:"common_labels"`
+	CommonAnnotations    types.Map          `tfsdk:"common_annotations"`
+	ServerDryRun         types.Bool         `tfsdk:"server_dry_run"`
+}
+
+// connectionAttributes are the attributes of the provider configuration which
+// determine the cluster it connects to and how.
+var connectionAttributes = []string{
+	"kubeconfig", "config_paths", "kubeconfig_raw", "in_cluster", "config_context", "config_context_cluster", "config_context_auth_info",
+	"host", "token", "client_certificate", "client_key", "cluster_ca_certificate", "insecure", "tls_server_name", "proxy_url",
+	"username", "password", "exec", "impersonate",
+}
+
+// unknownConnectionAttributes returns the connection attributes of config
+// whose values aren't known yet.
+func unknownConnectionAttributes(config tftypes.Value) ([]string, error) {
+	var av map[string]tftypes.Value
+	if err := config.As(&av); err != nil {
+		return nil, err
+	}
+	var unknown []string
+	for _, n := range connectionAttributes {
+		if a, ok := av[n]; ok && !a.IsFullyKnown() {
+			unknown = append(unknown, n)
+		}
+	}
+	return unknown, nil
+}
+
+// ExecModel describes an exec-based credential plugin.
+type ExecModel struct {
+	APIVersion types.String `tfsdk:"api_version"`
+	Command    types.String `tfsdk:"command"`
+	Args       types.List   `tfsdk:"args"`
+	Env        types.Map    `tfsdk:"env"`
+}
+
+// ImpersonateModel describes the identity the provider acts as.
+type ImpersonateModel struct {
+	User   types.String `tfsdk:"user"`
+	Groups types.List   `tfsdk:"groups"`
+	UID    types.String `tfsdk:"uid"`
+}
+
+// ProviderData is handed to resources and data sources when they are configured.
+type ProviderData struct {
+	Clients           *KubernetesClients
+	FieldManager      fieldManager
+	CommonLabels      map[string]string
+	CommonAnnotations map[string]string
+	ServerDryRun      bool
+	OperationTimeout  time.Duration
 }
 
 func (p *KubernetesCRD) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -44,9 +148,203 @@ func (p *KubernetesCRD) Metadata(ctx context.Context, req provider.MetadataReque
 
 func (p *KubernetesCRD) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		MarkdownDescription: "Generates a resource for each custom resource definition installed in the cluster, for its storage version by default.\n\n" +
+			"Attributes are named after the snake_case forms of the fields of objects, such as `host_ip` for `hostIP`. Field names can't be kept as they are, as Terraform only accepts lowercase attribute names; the `provider::crd::to_snake_case` function converts them.\n\n" +
+			"Terraform reads the schemas of these resources before configuring the provider, so the options shaping them are set in the environment:\n\n" +
+			"- `CRD_SCHEMA_SOURCE`: `openapi` to read schemas from the OpenAPI documents published by the API server, the default, or `crd` to read them from the definitions themselves, which is faster on clusters serving many groups.\n" +
+			"- `CRD_VERSIONS`: versions of each custom resource definition resources are generated for. `storage`, the default, selects the version objects are stored as, `latest` the newest served version, and `all` every served version. Versions which aren't served are left out, and resource names carry the version unless `CRD_RESOURCE_NAMING` leaves it out.\n" +
+			"- `CRD_SCHEMA_CACHE_DIR`: directory the converted schemas are cached in, keyed by the resource version of their definitions, so that plans against an unchanged cluster skip downloading them. Defaults to `terraform-provider-crd/schemas` under the user cache directory, e.g. `~/.cache` on Linux; set it to an empty string to disable caching.\n" +
+			"- `CRD_MANIFEST_PATHS`: comma-separated glob patterns of YAML or JSON files, such as `crds/*.yaml`, holding custom resource definitions to generate resources from instead of those installed in the cluster. Other objects in the files are ignored, and schemas are read from the definitions. This lets `terraform validate` and plans of configurations whose cluster doesn't exist yet run without connecting to a cluster.\n" +
+			"- `CRD_SCHEMA_LOCK_FILE`: path of a lock file recording the hashes of the generated schemas, to be committed alongside the configuration. It is written when missing; afterwards, resources whose schemas changed or disappeared since are reported, so that every machine plans against the same schemas. Delete the file to record the current schemas. `CRD_SCHEMA_LOCK_DRIFT` sets how drift is reported, `error`, the default, or `warn`.\n" +
+			"- `CRD_AGGREGATED_APIS`: set to `true` to also generate resources for the kinds served by aggregated API servers, which are registered by `APIService` objects rather than custom resource definitions. Their schemas are read from the OpenAPI documents of their group versions, whatever `CRD_SCHEMA_SOURCE` is set to. Requires the list permission on `apiservices.apiregistration.k8s.io`.\n" +
+			"- `CRD_RESOURCE_NAMING`: how the generated resources and data sources are named. `full`, the default, joins the group, version and singular name of kinds, as in `crd_networking_istio_io_v1beta1_virtualservice`; `no_domain` keeps only the first label of the group, as in `crd_networking_v1beta1_virtualservice`; `kind` uses the singular name alone, as in `crd_virtualservice`; and `short_name` uses the first short name of kinds, as in `crd_vs`, falling back to the singular name. `CRD_RESOURCE_RENAMES` holds comma-separated rename rules taking precedence, such as `virtualservices.networking.istio.io=istio_virtual_service`, with a slash and a version after the definition name to rename a single version. Kinds which would share a name are reported as errors, and none of their resources are generated.\n" +
+			"- `CRD_COMPUTED_ATTRIBUTES`: comma-separated attribute paths, such as `spec.replicas`, of fields the API server or its webhooks may set or change. They are computed when left out of the configuration, taking the values the API server returns instead of failing the apply with inconsistent results. Required and write-only attributes can't be computed.\n" +
+			"- `CRD_SENSITIVE_ATTRIBUTES`: comma-separated attribute paths, such as `spec.auth.api_key`, marked sensitive in every resource. " +
+			"String attributes named like secrets, such as `password` or `api_key`, are sensitive by default; prefix their paths with `!` to show their values.\n" +
+			"- `CRD_WRITE_ONLY_ATTRIBUTES`: comma-separated attribute paths made write-only in every resource. Their values are sent to the API server but never stored in the plan or the state, and require Terraform 1.11 or later.\n" +
+			"- `CRD_EPHEMERAL_RESOURCES`: comma-separated names of custom resource definitions, such as `vaultdynamicsecrets.secrets.hashicorp.com`, for which ephemeral resources are generated as well. Their objects are created when Terraform opens them, annotated with `terraform-provider-crd/renewed-at` every 5 minutes while in use, and deleted when Terraform is done with them. They require Terraform 1.10 or later.\n" +
+			"- `CRD_INCLUDE_CRDS` and `CRD_EXCLUDE_CRDS`: comma-separated glob patterns matched against the group and kind of custom resource definitions joined with a slash, such as `*.crossplane.io/*` or `cert-manager.io/Certificate`. Resources and data sources are only generated for the definitions matching one of the include patterns, when set, and none of the exclude patterns. Leaving out unused definitions speeds up every plan on clusters with hundreds of them.",
 		Attributes: map[string]schema.Attribute{
 			"kubeconfig": schema.StringAttribute{
-				MarkdownDescription: "Example provider attribute",
+				MarkdownDescription: "Path to the kubeconfig file. Can also be set with `KUBE_CONFIG_PATH`. Defaults to the standard loading rules, i.e. `KUBECONFIG` or `~/.kube/config`.",
+				Optional:            true,
+			},
+			"config_paths": schema.ListAttribute{
+				MarkdownDescription: "Paths of kubeconfig files to merge, in order of precedence. Can also be set with `KUBE_CONFIG_PATHS`, using the separator of the `PATH` variable. Conflicts with `kubeconfig`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"kubeconfig_raw": schema.StringAttribute{
+				MarkdownDescription: "Contents of a kubeconfig file, used instead of loading one from disk. Conflicts with `kubeconfig` and `config_paths`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"in_cluster": schema.BoolAttribute{
+				MarkdownDescription: "Use the service account of the pod the provider runs in, ignoring any kubeconfig. Without it, the in-cluster configuration is only used when no kubeconfig can be found.",
+				Optional:            true,
+			},
+			"config_context": schema.StringAttribute{
+				MarkdownDescription: "Kubeconfig context to use instead of the current context. Can also be set with `KUBE_CTX`.",
+				Optional:            true,
+			},
+			"config_context_cluster": schema.StringAttribute{
+				MarkdownDescription: "Kubeconfig cluster to use instead of the one named by the context. Can also be set with `KUBE_CTX_CLUSTER`.",
+				Optional:            true,
+			},
+			"config_context_auth_info": schema.StringAttribute{
+				MarkdownDescription: "Kubeconfig user to use instead of the one named by the context. Can also be set with `KUBE_CTX_AUTH_INFO`.",
+				Optional:            true,
+			},
+			"host": schema.StringAttribute{
+				MarkdownDescription: "Address of the Kubernetes API server. Can also be set with `KUBE_HOST`.",
+				Optional:            true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "Bearer token used to authenticate to the API server. Can also be set with `KUBE_TOKEN`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_certificate": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate for TLS authentication. Can also be set with `KUBE_CLIENT_CERT_DATA`.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key of the client certificate. Can also be set with `KUBE_CLIENT_KEY_DATA`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"cluster_ca_certificate": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded root certificates bundle used to verify the API server certificate. Can also be set with `KUBE_CLUSTER_CA_CERT_DATA`.",
+				Optional:            true,
+			},
+			"insecure": schema.BoolAttribute{
+				MarkdownDescription: "Skip verification of the API server certificate. This makes connections insecure. Can also be set with `KUBE_INSECURE`.",
+				Optional:            true,
+			},
+			"tls_server_name": schema.StringAttribute{
+				MarkdownDescription: "Server name used to verify the API server certificate, for servers reached at an address the certificate isn't issued for. Can also be set with `KUBE_TLS_SERVER_NAME`.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the proxy used to reach the API server. The `http`, `https` and `socks5` schemes are supported. Can also be set with `KUBE_PROXY_URL`.",
+				Optional:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Username for basic authentication to the API server. Can also be set with `KUBE_USER`.",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password for basic authentication to the API server. Can also be set with `KUBE_PASSWORD`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"exec": schema.SingleNestedAttribute{
+				MarkdownDescription: "Credential plugin used to obtain credentials for the API server, such as `aws-iam-authenticator` or `kubelogin`.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"api_version": schema.StringAttribute{
+						MarkdownDescription: "API version of the ExecCredential objects exchanged with the plugin, e.g. `client.authentication.k8s.io/v1beta1`.",
+						Required:            true,
+					},
+					"command": schema.StringAttribute{
+						MarkdownDescription: "Command to execute.",
+						Required:            true,
+					},
+					"args": schema.ListAttribute{
+						MarkdownDescription: "Arguments passed to the command.",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+					"env": schema.MapAttribute{
+						MarkdownDescription: "Environment variables set for the command.",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+				},
+			},
+			"impersonate": schema.SingleNestedAttribute{
+				MarkdownDescription: "Identity to impersonate when making requests to the API server. The authenticated user needs the `impersonate` permission for it.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"user": schema.StringAttribute{
+						MarkdownDescription: "User to impersonate, e.g. `system:serviceaccount:infra:deployer`.",
+						Required:            true,
+					},
+					"groups": schema.ListAttribute{
+						MarkdownDescription: "Groups to impersonate.",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+					"uid": schema.StringAttribute{
+						MarkdownDescription: "UID to impersonate.",
+						Optional:            true,
+					},
+				},
+			},
+			"qps": schema.Float64Attribute{
+				MarkdownDescription: "Maximum sustained rate of requests per second to the API server. Defaults to `5`.",
+				Optional:            true,
+			},
+			"burst": schema.Int64Attribute{
+				MarkdownDescription: "Maximum burst of requests to the API server above `qps`. Defaults to `10`.",
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout of individual requests to the API server, as a duration string such as `30s`. No timeout is set by default.",
+				Optional:            true,
+				Validators:          []validator.String{durationValidator{}},
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Number of times requests to the API server are retried, with exponential backoff, when they fail with throttling, unavailability, conflicting concurrent writes or transient network errors. Retries count towards `request_timeout`. Defaults to `%d`; set it to `0` to disable retries.", defaultMaxRetries),
+				Optional:            true,
+				Validators:          []validator.Int64{numberRangeValidator{min: &minRetries}},
+			},
+			"parallelism": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of requests sent to the API server at once, shared by all resources and data sources, so that large applies don't overwhelm it. Watches used to wait for objects don't count towards it. Requests aren't limited by default, beyond `qps` and `burst`.",
+				Optional:            true,
+				Validators:          []validator.Int64{numberRangeValidator{min: &minParallelism}},
+			},
+			"read_cache_ttl": schema.StringAttribute{
+				MarkdownDescription: "How long the results of reads shared by resources and data sources, such as discovery documents and the list of custom resource definitions, are reused, as a duration string such as `30s`. They are reused for the whole Terraform operation by default; set it to `0s` to read them every time.",
+				Optional:            true,
+				Validators:          []validator.String{durationValidator{}},
+			},
+			"operation_timeout": schema.StringAttribute{
+				MarkdownDescription: "Deadline of the operations performed against the cluster, as a duration string such as `10m`, including waiting for objects and retrying requests. It is the default of the `timeouts` attribute of resources, and bounds the reads of data sources and the operations of ephemeral resources. Resources default to `20m` for creates, updates and deletes and `5m` for reads; data sources and ephemeral resources aren't bounded by default.",
+				Optional:            true,
+				Validators:          []validator.String{durationValidator{}},
+			},
+			"field_manager": schema.SingleNestedAttribute{
+				MarkdownDescription: "Server-side apply settings used for all resources. Resources can override them with their own `field_manager` attribute.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the field manager objects are applied as. Defaults to `" + defaultFieldManagerName + "`.",
+						Optional:            true,
+					},
+					"force_conflicts": schema.BoolAttribute{
+						MarkdownDescription: "Take ownership of fields managed by other field managers, such as controllers or GitOps tools, instead of failing with a conflict.",
+						Optional:            true,
+					},
+					"field_validation": schema.StringAttribute{
+						MarkdownDescription: fieldValidationDescription,
+						Optional:            true,
+						Validators:          []validator.String{stringOneOfValidator{values: fieldValidations}},
+					},
+				},
+			},
+			"common_labels": schema.MapAttribute{
+				MarkdownDescription: "Labels added to every object managed by the provider. Labels set on a resource take precedence. They are not tracked in the state of resources.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"common_annotations": schema.MapAttribute{
+				MarkdownDescription: "Annotations added to every object managed by the provider. Annotations set on a resource take precedence. They are not tracked in the state of resources.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"server_dry_run": schema.BoolAttribute{
+				MarkdownDescription: "Submit planned objects to the API server as a dry run, so that admission webhooks and validation rules are checked at plan time. Defaults to `true`; disable it to plan without reaching the cluster.",
 				Optional:            true,
 			},
 		},
@@ -57,65 +355,352 @@ func (p *KubernetesCRD) Configure(ctx context.Context, req provider.ConfigureReq
 	var data KubernetesCRDModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	if !req.Config.Raw.IsFullyKnown() && req.ClientCapabilities.DeferralAllowed {
+		// The cluster is created in the same run, so resources are deferred
+		// until its connection details are known. Discovery problems are
+		// expected until then.
+		resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+		return
+	}
+	unknown, err := unknownConnectionAttributes(req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to inspect provider configuration", err.Error())
+		return
+	}
+	if len(unknown) > 0 {
+		// Clients built without the unknown values would connect to
+		// another cluster, or none.
+		resp.Diagnostics.AddError("Unknown Provider Configuration", fmt.Sprintf(
+			"The %s attributes depend on values known only after apply, such as those of a cluster created in the same run, "+
+				"and this version of Terraform can't defer the resources of the provider until then. "+
+				"Apply the resources they depend on first, for instance with -target, or use a version of Terraform supporting deferred actions.",
+			strings.Join(unknown, ", ")))
+		return
+	}
+
+	resp.Diagnostics.Append(p.discoveryDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Configuration values are now available.
-	// if data.Kubeconfig.IsNull() { /* ... */ }
+	cfg, err := clientConfig(ctx, data)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Kubernetes Configuration", err.Error())
+		return
+	}
+	clients, err := NewKubernetesClientForConfig(cfg)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create Kubernetes clients", err.Error())
+		return
+	}
+	// Definitions read from files need no permissions, and those discovery
+	// found unreadable were already reported.
+	listDefinitions := len(p.options.manifestPaths) == 0 && !hasDiagnostic(p.discoveryDiags, definitionsNotReadable)
+	resp.Diagnostics.Append(preflight(ctx, clients, listDefinitions, !req.ClientCapabilities.DeferralAllowed)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Example client configuration for data sources and resources
-	resp.DataSourceData = p.clients
-	resp.ResourceData = p.clients
+	pd := &ProviderData{
+		Clients:      clients,
+		FieldManager: fieldManager{name: defaultFieldManagerName}.merge(data.FieldManager),
+		ServerDryRun: data.ServerDryRun.IsNull() || data.ServerDryRun.ValueBool(),
+	}
+	if !data.OperationTimeout.IsNull() {
+		pd.OperationTimeout, err = time.ParseDuration(data.OperationTimeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("operation_timeout"), "Invalid Duration", err.Error())
+			return
+		}
+	}
+	if !data.ReadCacheTTL.IsNull() {
+		clients.reads.ttl, err = time.ParseDuration(data.ReadCacheTTL.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("read_cache_ttl"), "Invalid Duration", err.Error())
+			return
+		}
+	}
+	resp.Diagnostics.Append(data.CommonLabels.ElementsAs(ctx, &pd.CommonLabels, false)...)
+	resp.Diagnostics.Append(data.CommonAnnotations.ElementsAs(ctx, &pd.CommonAnnotations, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.DataSourceData = pd
+	resp.ResourceData = pd
+	resp.EphemeralResourceData = pd
 }
 
 func (p *KubernetesCRD) Resources(ctx context.Context) []func() resource.Resource {
 	var resources []func() resource.Resource
+	for _, k := range p.discover(ctx) {
+		resources = append(resources, func() resource.Resource {
+			return NewCustomResource(k.version, k.group, k.names, k.scope, k.schema, p.options)
+		})
+	}
+	return resources
+}
+
+func (p *KubernetesCRD) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	var ephemeralResources []func() ephemeral.EphemeralResource
+	for _, k := range p.discover(ctx) {
+		if !p.options.ephemeral[k.names.Plural+"."+k.group] {
+			continue
+		}
+		ephemeralResources = append(ephemeralResources, func() ephemeral.EphemeralResource {
+			return NewCustomEphemeralResource(k.version, k.group, k.names, k.scope, k.schema, p.options)
+		})
+	}
+	return ephemeralResources
+}
+
+// discover returns the custom resource kinds served by the cluster. They are
+// looked up once, and shared by resources and data sources.
+func (p *KubernetesCRD) discover(ctx context.Context) []customKind {
+	if p.discovered {
+		return p.kinds
+	}
+	p.discovered = true
 
-	crds, err := p.clients.APIextensions.ApiextensionsV1().CustomResourceDefinitions().List(ctx, v1.ListOptions{})
+	ctx = withSubsystem(ctx, discoverySubsystem)
+	ctx = withSubsystem(ctx, schemaSubsystem)
+	start := time.Now()
+	var err error
+	p.options, err = schemaOptionsFromEnv()
 	if err != nil {
-		log.Fatalf("failed to list Custom Resources: %s", err)
+		p.discoveryDiags.AddError("Invalid Schema Options", err.Error())
+		return nil
 	}
 
-	for _, crd := range crds.Items {
-		for _, ver := range crd.Spec.Versions {
-			gv := rtschema.GroupVersion{Version: ver.Name, Group: crd.Spec.Group}
-			gvspec, err := p.clients.Openapi.GVSpec(gv)
-			if err != nil {
-				log.Fatal(err)
-			}
-			var s *spec.Schema
-			for k := range gvspec.Components.Schemas {
-				if !strings.HasSuffix(k, crd.Spec.Names.Kind) {
+	var clients *KubernetesClients
+	var crds []apiextv1.CustomResourceDefinition
+	if len(p.options.manifestPaths) > 0 {
+		crds, err = crdsFromManifests(p.options.manifestPaths)
+		if err != nil {
+			p.discoveryDiags.AddError("Failed to read Custom Resource Definitions", err.Error())
+			return nil
+		}
+		tflog.SubsystemDebug(ctx, discoverySubsystem, "Read custom resource definitions from files", map[string]interface{}{
+			"definitions": len(crds),
+			"paths":       p.options.manifestPaths,
+		})
+	} else {
+		clients, err = p.discoveryClients()
+		if err != nil {
+			p.discoveryDiags.AddError("Invalid Kubernetes Configuration", fmt.Sprintf("Unable to create clients for resource discovery: %s", err))
+			return nil
+		}
+		listed := time.Now()
+		list, err := clients.APIextensions.ApiextensionsV1().CustomResourceDefinitions().List(ctx, v1.ListOptions{})
+		if apierrors.IsForbidden(err) {
+			// Data sources and functions which don't depend on
+			// custom resources remain usable.
+			p.discoveryDiags.AddWarning(definitionsNotReadable, fmt.Sprintf(
+				"No resources were generated, as the credentials can't list custom resource definitions: %s\n\n"+
+					"Grant them the list permission on customresourcedefinitions.apiextensions.k8s.io, or set CRD_MANIFEST_PATHS to read the definitions from files.", err))
+			return nil
+		}
+		if err != nil {
+			p.discoveryDiags.AddError("Failed to list Custom Resource Definitions", err.Error())
+			return nil
+		}
+		crds = list.Items
+		tflog.SubsystemDebug(ctx, discoverySubsystem, "Listed custom resource definitions", map[string]interface{}{
+			"definitions": len(crds),
+			"latency_ms":  time.Since(listed).Milliseconds(),
+		})
+	}
+
+	type crdVersion struct {
+		crd apiextv1.CustomResourceDefinition
+		ver apiextv1.CustomResourceDefinitionVersion
+	}
+	var selected []crdVersion
+	var gvs []rtschema.GroupVersion
+	for _, crd := range crds {
+		if !p.options.selects(crd.Spec.Group, crd.Spec.Names.Kind) {
+			continue
+		}
+		for _, ver := range selectVersions(crd, p.options.versions) {
+			selected = append(selected, crdVersion{crd, ver})
+			gvs = append(gvs, rtschema.GroupVersion{Group: crd.Spec.Group, Version: ver.Name})
+		}
+	}
+	cache := schemaCache{dir: p.options.cacheDir}
+	schemas := make([]*spec.Schema, len(selected))
+	var missed []rtschema.GroupVersion
+	for i, cv := range selected {
+		switch {
+		case cv.crd.Spec.PreserveUnknownFields:
+			// Legacy definitions have no structural schema to convert,
+			// and none is published for them.
+			schemas[i] = legacyObjectSchema()
+		case cv.crd.ResourceVersion != "":
+			schemas[i] = cache.load(schemaCacheKey(p.options.source, cv.crd, cv.ver))
+		}
+		if schemas[i] == nil {
+			missed = append(missed, gvs[i])
+		}
+	}
+	var docs map[rtschema.GroupVersion]openAPIDocument
+	if p.options.source != schemaSourceCRD {
+		fetched := time.Now()
+		docs = fetchOpenAPIDocuments(clients.Openapi, missed)
+		tflog.SubsystemDebug(ctx, discoverySubsystem, "Fetched OpenAPI documents", map[string]interface{}{
+			"group_versions": len(docs),
+			"cached":         len(selected) - len(missed),
+			"latency_ms":     time.Since(fetched).Milliseconds(),
+		})
+	}
+	var skipped []string
+	for i, cv := range selected {
+		crd, ver := cv.crd, cv.ver
+		gvk := gvs[i].WithKind(crd.Spec.Names.Kind)
+		s := schemas[i]
+		if s == nil {
+			// Schemas are only cached when they convert cleanly, so
+			// that their warnings are reported on every run.
+			warnings := len(p.discoveryDiags)
+			switch p.options.source {
+			case schemaSourceCRD:
+				s = p.crdSchema(crd, ver)
+			default:
+				doc := docs[gvs[i]]
+				if doc.err != nil {
+					tflog.SubsystemWarn(ctx, discoverySubsystem, "Failed to fetch OpenAPI schema", map[string]interface{}{"gvk": gvk.String(), "error": doc.err.Error()})
+					skipped = append(skipped, fmt.Sprintf("%s (%s): %s", crd.Spec.Names.Kind, gvs[i], doc.err))
 					continue
 				}
-				s = gvspec.Components.Schemas[k]
-				break
+				s = p.openAPISchema(doc.spec, gvk)
+			}
+			if s == nil {
+				tflog.SubsystemWarn(ctx, schemaSubsystem, "Skipped kind without a usable schema", map[string]interface{}{"gvk": gvk.String()})
+				continue
+			}
+			// Definitions read from files have no resource version to
+			// key their schemas by, and are cheap to convert anyway.
+			if len(p.discoveryDiags) == warnings && crd.ResourceVersion != "" {
+				if err := cache.store(schemaCacheKey(p.options.source, crd, ver), s); err != nil {
+					tflog.SubsystemDebug(ctx, schemaSubsystem, "Failed to cache schema", map[string]interface{}{"gvk": gvk.String(), "error": err.Error()})
+				}
 			}
-			resources = append(resources, func() resource.Resource {
-				r := NewCustomResource(ver.Name, crd.Spec.Group, crd.Spec.Names, s)
-				return r
-			})
 		}
+		tflog.SubsystemTrace(ctx, schemaSubsystem, "Loaded schema", map[string]interface{}{"gvk": gvk.String(), "cached": schemas[i] != nil})
+		p.kinds = append(p.kinds, customKind{
+			version: ver.Name,
+			group:   crd.Spec.Group,
+			names:   crd.Spec.Names,
+			scope:   crd.Spec.Scope,
+			schema:  s,
+		})
 	}
+	if p.options.aggregated && clients != nil {
+		kinds, sk := p.aggregatedKinds(ctx, clients)
+		p.kinds = append(p.kinds, kinds...)
+		skipped = append(skipped, sk...)
+	}
+	p.kinds = p.nameKinds(p.kinds)
+	if len(skipped) > 0 {
+		p.discoveryDiags.AddWarning("Failed to fetch OpenAPI schemas", fmt.Sprintf(
+			"No resources were generated for these kinds, as their OpenAPI schemas couldn't be fetched:\n\n- %s\n\n"+
+				"If the credentials lack the get permission on the /openapi/v3 URLs, grant it or set CRD_SCHEMA_SOURCE=crd to read schemas from the definitions.",
+			strings.Join(skipped, "\n- ")))
+	}
+	if p.options.lockFile != "" {
+		p.discoveryDiags.Append(checkSchemaLock(p.options.lockFile, p.options.lockDrift, p.kinds)...)
+	}
+	tflog.SubsystemDebug(ctx, discoverySubsystem, "Discovered custom resource kinds", map[string]interface{}{
+		"kinds":      len(p.kinds),
+		"skipped":    len(skipped),
+		"latency_ms": time.Since(start).Milliseconds(),
+	})
+	return p.kinds
+}
 
-	return resources
+// nameKinds names kinds following the naming options. Kinds which would share
+// a name are left out and reported, as the framework rejects duplicate
+// resource types.
+func (p *KubernetesCRD) nameKinds(kinds []customKind) []customKind {
+	for i, k := range kinds {
+		kinds[i].name = p.options.resourceName(k.version, k.group, k.names)
+	}
+	collisions := resourceNameCollisions(kinds)
+	if len(collisions) == 0 {
+		return kinds
+	}
+	names := make([]string, 0, len(collisions))
+	for n := range collisions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		p.discoveryDiags.AddError("Resource Name Collision", fmt.Sprintf(
+			"These kinds would all generate resources named %s, so none were generated:\n\n- %s\n\n"+
+				"Rename them with CRD_RESOURCE_RENAMES, or choose another CRD_RESOURCE_NAMING strategy.",
+			n, strings.Join(collisions[n], "\n- ")))
+	}
+	named := kinds[:0]
+	for _, k := range kinds {
+		if _, ok := collisions[k.name]; !ok {
+			named = append(named, k)
+		}
+	}
+	return named
+}
+
+// discoveryClients returns the clients used for resource discovery, creating
+// them from the default kubeconfig if needed.
+func (p *KubernetesCRD) discoveryClients() (*KubernetesClients, error) {
+	if p.clients != nil {
+		return p.clients, nil
+	}
+	clients, err := NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	p.clients = clients
+	return clients, nil
 }
 
 func (p *KubernetesCRD) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	dataSources := []func() datasource.DataSource{NewDefinitionsDataSource, NewEventsDataSource, NewClusterInfoDataSource}
+	for _, k := range p.discover(ctx) {
+		dataSources = append(dataSources, func() datasource.DataSource {
+			return NewCustomDataSource(k.version, k.group, k.names, k.scope, k.schema, p.options)
+		}, func() datasource.DataSource {
+			return NewCustomListDataSource(k.version, k.group, k.names, k.scope, k.schema, p.options)
+		}, func() datasource.DataSource {
+			return NewCustomManifestDataSource(k.version, k.group, k.names, k.scope, k.schema, p.options)
+		}, func() datasource.DataSource {
+			return NewCustomStatusDataSource(k.version, k.group, k.names, k.scope, k.schema, p.options)
+		})
+	}
+	return dataSources
 }
 
 func (p *KubernetesCRD) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewManifestDecodeFunction,
+		NewManifestDecodeMultiFunction,
+		NewManifestEncodeFunction,
+		NewMergeManifestsFunction,
+		NewParseQuantityFunction,
+		NewAddQuantitiesFunction,
+		NewCompareQuantitiesFunction,
+		NewToSnakeCaseFunction,
+		NewToCamelCaseFunction,
+		NewExtractConditionFunction,
+		NewSelectorMatchesFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &KubernetesCRD{
 			version: version,
-			clients: NewKubernetesClient(),
 		}
 	}
 }
@@ -4,10 +4,21 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -23,3 +34,117 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+func TestResourcesWithoutCluster(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/nonexistent/kubeconfig")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	p := New("test")().(*KubernetesCRD)
+	if rs := p.Resources(context.Background()); len(rs) != 0 {
+		t.Errorf("expected no resources, got %d", len(rs))
+	}
+	if !p.discoveryDiags.HasError() {
+		t.Error("expected a discovery error diagnostic")
+	}
+}
+
+// crdListPath is the path custom resource definitions are listed at.
+const crdListPath = "/apis/apiextensions.k8s.io/v1/customresourcedefinitions"
+
+// testDiscoveryServer serves the JSON form of routes, keyed by path, and
+// forbids any other request.
+func testDiscoveryServer(t *testing.T, routes map[string]interface{}) *KubernetesClients {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if body, ok := routes[r.URL.Path]; ok {
+			_ = json.NewEncoder(w).Encode(body)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonForbidden,
+			Code:     http.StatusForbidden,
+			Message:  "forbidden",
+		})
+	}))
+	t.Cleanup(srv.Close)
+	clients, err := NewKubernetesClientForConfig(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clients
+}
+
+func TestResourcesWithForbiddenDefinitions(t *testing.T) {
+	p := New("test")().(*KubernetesCRD)
+	p.clients = testDiscoveryServer(t, nil)
+	if rs := p.Resources(context.Background()); len(rs) != 0 {
+		t.Errorf("expected no resources, got %d", len(rs))
+	}
+	if p.discoveryDiags.HasError() || p.discoveryDiags.WarningsCount() != 1 {
+		t.Errorf("expected a single warning, got %v", p.discoveryDiags)
+	}
+}
+
+func TestResourcesWithForbiddenOpenAPI(t *testing.T) {
+	t.Setenv("CRD_SCHEMA_CACHE_DIR", "")
+	crd := func(group string) apiextv1.CustomResourceDefinition {
+		return apiextv1.CustomResourceDefinition{Spec: apiextv1.CustomResourceDefinitionSpec{
+			Group:    group,
+			Names:    apiextv1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"},
+			Scope:    apiextv1.NamespaceScoped,
+			Versions: []apiextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+		}}
+	}
+	p := New("test")().(*KubernetesCRD)
+	p.clients = testDiscoveryServer(t, map[string]interface{}{
+		crdListPath: apiextv1.CustomResourceDefinitionList{Items: []apiextv1.CustomResourceDefinition{crd("a.example.com"), crd("b.example.com")}},
+	})
+	if rs := p.Resources(context.Background()); len(rs) != 0 {
+		t.Errorf("expected no resources, got %d", len(rs))
+	}
+	if p.discoveryDiags.HasError() || p.discoveryDiags.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning, got %v", p.discoveryDiags)
+	}
+	detail := p.discoveryDiags[0].Detail()
+	for _, gv := range []string{"a.example.com/v1", "b.example.com/v1"} {
+		if !strings.Contains(detail, gv) {
+			t.Errorf("expected the warning to list %s, got %q", gv, detail)
+		}
+	}
+}
+
+func TestConfigureUnknownConnection(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")().(*KubernetesCRD)
+	sresp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, sresp)
+	typ := sresp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	vals := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for n, at := range typ.AttributeTypes {
+		vals[n] = tftypes.NewValue(at, nil)
+	}
+	vals["host"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	config := tfsdk.Config{Schema: sresp.Schema, Raw: tftypes.NewValue(typ, vals)}
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config:             config,
+		ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: true},
+	}, resp)
+	if resp.Deferred == nil || resp.Diagnostics.HasError() {
+		t.Errorf("expected the provider to be deferred, got %v", resp.Diagnostics)
+	}
+
+	resp = &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: config}, resp)
+	if !hasDiagnostic(resp.Diagnostics, "Unknown Provider Configuration") || resp.ResourceData != nil {
+		t.Errorf("expected the unknown host to be reported, got %v", resp.Diagnostics)
+	}
+	if !strings.Contains(resp.Diagnostics[0].Detail(), "host") {
+		t.Errorf("expected the host to be named, got %q", resp.Diagnostics[0].Detail())
+	}
+}
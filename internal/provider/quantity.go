@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ basetypes.StringTypable = QuantityType{}
+var _ basetypes.StringValuableWithSemanticEquals = QuantityValue{}
+
+// quantityPattern is the pattern controller-gen emits for resource.Quantity
+// fields, which is how quantities are recognised in CRD schemas.
+const quantityPattern = `^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+
+// isQuantity reports whether s describes a resource quantity such as "500m"
+// or "1Gi".
+func isQuantity(s *spec.Schema) bool {
+	if s == nil {
+		return false
+	}
+	return s.Format == "quantity" || (isIntOrString(s) && s.Pattern == quantityPattern)
+}
+
+// QuantityType is the type of attributes generated for resource quantities.
+// Values which denote the same amount, such as "1024Mi" and "1Gi", are
+// semantically equal, so normalization by the API server causes no diffs.
+type QuantityType struct {
+	basetypes.StringType
+}
+
+func (t QuantityType) Equal(o attr.Type) bool {
+	other, ok := o.(QuantityType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t QuantityType) String() string {
+	return "QuantityType"
+}
+
+func (t QuantityType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return QuantityValue{StringValue: in}, nil
+}
+
+func (t QuantityType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	av, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	sv, ok := av.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", av)
+	}
+	v, diags := t.ValueFromString(ctx, sv)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to QuantityValue: %v", diags)
+	}
+	return v, nil
+}
+
+func (t QuantityType) ValueType(ctx context.Context) attr.Value {
+	return QuantityValue{}
+}
+
+// QuantityValue is a value of QuantityType.
+type QuantityValue struct {
+	basetypes.StringValue
+}
+
+func (v QuantityValue) Equal(o attr.Value) bool {
+	other, ok := o.(QuantityValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v QuantityValue) Type(ctx context.Context) attr.Type {
+	return QuantityType{}
+}
+
+// StringSemanticEquals compares quantities by the amount they denote. Values
+// which don't parse as quantities are compared as strings.
+func (v QuantityValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	nv, d := newValuable.ToStringValue(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return false, diags
+	}
+	if v.ValueString() == nv.ValueString() {
+		return true, diags
+	}
+	a, err := resource.ParseQuantity(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	b, err := resource.ParseQuantity(nv.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	return a.Cmp(b) == 0, diags
+}
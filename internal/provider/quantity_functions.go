@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ function.Function = &ParseQuantityFunction{}
+var _ function.Function = &AddQuantitiesFunction{}
+var _ function.Function = &CompareQuantitiesFunction{}
+
+// parsedQuantityAttrTypes are the attributes of the objects returned by
+// parse_quantity.
+var parsedQuantityAttrTypes = map[string]attr.Type{
+	"value":     types.NumberType,
+	"canonical": types.StringType,
+}
+
+func NewParseQuantityFunction() function.Function {
+	return &ParseQuantityFunction{}
+}
+
+// ParseQuantityFunction parses a resource quantity, such as "500m" or "1Gi".
+type ParseQuantityFunction struct{}
+
+func (f *ParseQuantityFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_quantity"
+}
+
+func (f *ParseQuantityFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Parse a resource quantity",
+		MarkdownDescription: "Parses a resource quantity, such as `500m` or `1Gi`, into an object holding the amount it denotes as `value`, e.g. `0.5` or `1073741824`, and its canonical form as `canonical`. Since numbers are quantities too, `value` can be computed with and passed back to the API server.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "quantity",
+				MarkdownDescription: "Quantity to parse.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: parsedQuantityAttrTypes,
+		},
+	}
+}
+
+func (f *ParseQuantityFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var s string
+	resp.Error = req.Arguments.Get(ctx, &s)
+	if resp.Error != nil {
+		return
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid quantity %q: %s", s, err))
+		return
+	}
+	v, _, err := big.ParseFloat(q.AsDec().String(), 10, 512, big.ToNearestEven)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, types.ObjectValueMust(parsedQuantityAttrTypes, map[string]attr.Value{
+		"value":     types.NumberValue(v),
+		"canonical": types.StringValue(q.String()),
+	}))
+}
+
+func NewAddQuantitiesFunction() function.Function {
+	return &AddQuantitiesFunction{}
+}
+
+// AddQuantitiesFunction sums resource quantities.
+type AddQuantitiesFunction struct{}
+
+func (f *AddQuantitiesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "add_quantities"
+}
+
+func (f *AddQuantitiesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Add resource quantities",
+		MarkdownDescription: "Sums resource quantities, such as `512Mi` and `1Gi`, returning the canonical form of the total, e.g. `1536Mi`. The total takes the suffix of the first quantity. Negative quantities subtract.",
+		VariadicParameter: function.StringParameter{
+			Name:                "quantities",
+			MarkdownDescription: "Quantities to add.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *AddQuantitiesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var qs []string
+	resp.Error = req.Arguments.Get(ctx, &qs)
+	if resp.Error != nil {
+		return
+	}
+	var sum resource.Quantity
+	for i, s := range qs {
+		q, err := resource.ParseQuantity(s)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(int64(i), fmt.Sprintf("invalid quantity %q: %s", s, err))
+			return
+		}
+		if i == 0 {
+			sum = q
+			continue
+		}
+		sum.Add(q)
+	}
+	resp.Error = resp.Result.Set(ctx, sum.String())
+}
+
+func NewCompareQuantitiesFunction() function.Function {
+	return &CompareQuantitiesFunction{}
+}
+
+// CompareQuantitiesFunction compares resource quantities by the amount they
+// denote.
+type CompareQuantitiesFunction struct{}
+
+func (f *CompareQuantitiesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "compare_quantities"
+}
+
+func (f *CompareQuantitiesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Compare resource quantities",
+		MarkdownDescription: "Compares resource quantities by the amount they denote, returning `-1` if `a` is less than `b`, `0` if they are equal, as `1Gi` and `1024Mi` are, and `1` if `a` is greater than `b`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "a",
+				MarkdownDescription: "First quantity.",
+			},
+			function.StringParameter{
+				Name:                "b",
+				MarkdownDescription: "Second quantity.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *CompareQuantitiesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b string
+	resp.Error = req.Arguments.Get(ctx, &a, &b)
+	if resp.Error != nil {
+		return
+	}
+	qa, err := resource.ParseQuantity(a)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid quantity %q: %s", a, err))
+		return
+	}
+	qb, err := resource.ParseQuantity(b)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid quantity %q: %s", b, err))
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, int64(qa.Cmp(qb)))
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestParseQuantityFunction(t *testing.T) {
+	cases := map[string]struct {
+		value     *big.Float
+		canonical string
+	}{
+		"500m":   {big.NewFloat(0.5), "500m"},
+		"1Gi":    {big.NewFloat(1 << 30), "1Gi"},
+		"1024Mi": {big.NewFloat(1 << 30), "1Gi"},
+		"2":      {big.NewFloat(2), "2"},
+	}
+	for in, c := range cases {
+		v, ferr := runFunction(t, NewParseQuantityFunction(), types.StringValue(in))
+		if ferr != nil {
+			t.Fatalf("%s: %s", in, ferr)
+		}
+		var obj map[string]tftypes.Value
+		if err := v.As(&obj); err != nil {
+			t.Fatal(err)
+		}
+		var value big.Float
+		var canonical string
+		if err := obj["value"].As(&value); err != nil || value.Cmp(c.value) != 0 {
+			t.Errorf("%s: expected value %s, got %s (err: %v)", in, c.value, value.String(), err)
+		}
+		if err := obj["canonical"].As(&canonical); err != nil || canonical != c.canonical {
+			t.Errorf("%s: expected canonical %q, got %q (err: %v)", in, c.canonical, canonical, err)
+		}
+	}
+
+	if _, ferr := runFunction(t, NewParseQuantityFunction(), types.StringValue("1 GB")); ferr == nil {
+		t.Error("expected an error parsing an invalid quantity")
+	}
+}
+
+func TestAddQuantitiesFunction(t *testing.T) {
+	cases := []struct {
+		in   []string
+		want string
+	}{
+		{[]string{"512Mi", "512Mi"}, "1Gi"},
+		{[]string{"512Mi", "1Gi"}, "1536Mi"},
+		{[]string{"250m", "1"}, "1250m"},
+		{[]string{"1Gi", "-256Mi"}, "768Mi"},
+		{[]string{}, "0"},
+	}
+	for _, c := range cases {
+		// Variadic arguments are passed as a tuple.
+		ets := make([]attr.Type, 0, len(c.in))
+		evs := make([]attr.Value, 0, len(c.in))
+		for _, q := range c.in {
+			ets = append(ets, types.StringType)
+			evs = append(evs, types.StringValue(q))
+		}
+		v, ferr := runFunction(t, NewAddQuantitiesFunction(), types.TupleValueMust(ets, evs))
+		if ferr != nil {
+			t.Fatalf("%v: %s", c.in, ferr)
+		}
+		var got string
+		if err := v.As(&got); err != nil || got != c.want {
+			t.Errorf("%v: expected %q, got %q (err: %v)", c.in, c.want, got, err)
+		}
+	}
+}
+
+func TestCompareQuantitiesFunction(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int64
+	}{
+		{"1Gi", "1024Mi", 0},
+		{"500m", "1", -1},
+		{"2", "1500m", 1},
+	}
+	for _, c := range cases {
+		v, ferr := runFunction(t, NewCompareQuantitiesFunction(), types.StringValue(c.a), types.StringValue(c.b))
+		if ferr != nil {
+			t.Fatal(ferr)
+		}
+		var got big.Float
+		if err := v.As(&got); err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := got.Int64(); n != c.want {
+			t.Errorf("%s vs %s: expected %d, got %d", c.a, c.b, c.want, n)
+		}
+	}
+
+	if _, ferr := runFunction(t, NewCompareQuantitiesFunction(), types.StringValue("1Gi"), types.StringValue("lots")); ferr == nil || *ferr.FunctionArgument != 1 {
+		t.Errorf("expected an error about the second argument, got %v", ferr)
+	}
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestQuantitySemanticEquals(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"1Gi", "1024Mi", true},
+		{"500m", "0.5", true},
+		{"1", "1000m", true},
+		{"1Gi", "1G", false},
+		{"large", "large", true},
+		{"large", "1", false},
+	}
+	for _, c := range cases {
+		v := QuantityValue{StringValue: types.StringValue(c.a)}
+		eq, diags := v.StringSemanticEquals(context.Background(), QuantityValue{StringValue: types.StringValue(c.b)})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if eq != c.equal {
+			t.Errorf("%q == %q: got %t, want %t", c.a, c.b, eq, c.equal)
+		}
+	}
+}
+
+func TestQuantityAttributeFromOAPI(t *testing.T) {
+	q := intOrStringProperty()
+	q.Pattern = quantityPattern
+	limits := spec.MapProperty(q)
+
+	a, diags := attributeFromOAPI(limits, path.Root("spec").AtName("limits"), optionalAttribute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	ma, ok := a.(schema.MapAttribute)
+	if !ok {
+		t.Fatalf("expected a map attribute, got %T", a)
+	}
+	if _, ok := ma.ElementType.(QuantityType); !ok {
+		t.Errorf("expected elements of QuantityType, got %T", ma.ElementType)
+	}
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// cacheForever keeps the results of reads for as long as the provider runs,
+// that is for a single Terraform operation such as a plan or an apply.
+const cacheForever = time.Duration(math.MaxInt64)
+
+// readCache shares the results of reads which don't depend on the object
+// being operated on, such as discovery documents and the list of custom
+// resource definitions, between the resources and data sources of an
+// operation. Reads of the same key made at the same time wait for the first
+// of them, and failed reads aren't kept. A nil readCache reads every time.
+type readCache struct {
+	// ttl is how long results are kept. Zero disables the cache.
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry is the result of a read, which is pending until done is
+// closed. Results kept for as long as the provider runs don't expire.
+type cacheEntry struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	expires time.Time
+}
+
+func newReadCache(ttl time.Duration) *readCache {
+	return &readCache{ttl: ttl, entries: make(map[string]*cacheEntry)}
+}
+
+// get returns the result of read, made at most once per key until it
+// expires.
+func (c *readCache) get(key string, read func() (interface{}, error)) (interface{}, error) {
+	if c == nil || c.ttl <= 0 {
+		return read()
+	}
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		select {
+		case <-e.done:
+			if e.err != nil || (!e.expires.IsZero() && time.Now().After(e.expires)) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if ok {
+		c.mu.Unlock()
+		<-e.done
+		return e.value, e.err
+	}
+	e = &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.value, e.err = read()
+	if c.ttl != cacheForever {
+		e.expires = time.Now().Add(c.ttl)
+	}
+	close(e.done)
+	return e.value, e.err
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadCache(t *testing.T) {
+	var reads int32
+	read := func() (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return atomic.AddInt32(&reads, 1), nil
+	}
+
+	c := newReadCache(cacheForever)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.get("version", read); err != nil || v.(int32) != 1 {
+				t.Errorf("expected the first read to be shared, got %v (err: %v)", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, _ = c.get("groups", read); reads != 2 {
+		t.Errorf("expected keys to be read separately, got %d reads", reads)
+	}
+
+	failed := 0
+	fail := func() (interface{}, error) {
+		failed++
+		return nil, errors.New("unavailable")
+	}
+	_, _ = c.get("definitions", fail)
+	if _, err := c.get("definitions", fail); err == nil || failed != 2 {
+		t.Errorf("expected failed reads not to be kept, got %d reads", failed)
+	}
+
+	c = newReadCache(time.Millisecond)
+	reads = 0
+	_, _ = c.get("version", read)
+	time.Sleep(5 * time.Millisecond)
+	if _, _ = c.get("version", read); reads != 2 {
+		t.Errorf("expected expired results to be read again, got %d reads", reads)
+	}
+
+	for _, c := range []*readCache{nil, newReadCache(0)} {
+		reads = 0
+		_, _ = c.get("version", read)
+		_, _ = c.get("version", read)
+		if reads != 2 {
+			t.Errorf("expected every read to be made, got %d reads", reads)
+		}
+	}
+}
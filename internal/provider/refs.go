@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const componentsPrefix = "#/components/schemas/"
+
+// resolveRefs returns a copy of s with every $ref replaced by the component
+// schema it points to. References which can't be resolved, or which refer back
+// to a schema being resolved, are replaced by schemas preserving unknown
+// fields, so that they convert to dynamic attributes.
+func resolveRefs(s *spec.Schema, components map[string]*spec.Schema) (*spec.Schema, diag.Diagnostics) {
+	r := &refResolver{
+		components: components,
+		resolving:  make(map[string]bool),
+	}
+	rs := r.resolve(s)
+	return rs, r.diags
+}
+
+type refResolver struct {
+	components map[string]*spec.Schema
+	resolving  map[string]bool
+	diags      diag.Diagnostics
+}
+
+func (r *refResolver) resolve(s *spec.Schema) *spec.Schema {
+	if s == nil {
+		return nil
+	}
+	if ref := s.Ref.String(); ref != "" {
+		return r.resolveRef(s, ref)
+	}
+
+	rs := *s
+	if s.Properties != nil {
+		rs.Properties = make(map[string]spec.Schema, len(s.Properties))
+		for k, p := range s.Properties {
+			rs.Properties[k] = *r.resolve(&p)
+		}
+	}
+	if s.Items != nil {
+		items := *s.Items
+		items.Schema = r.resolve(s.Items.Schema)
+		items.Schemas = r.resolveAll(s.Items.Schemas)
+		rs.Items = &items
+	}
+	if s.AdditionalProperties != nil {
+		ap := *s.AdditionalProperties
+		ap.Schema = r.resolve(s.AdditionalProperties.Schema)
+		rs.AdditionalProperties = &ap
+	}
+	rs.AllOf = r.resolveAll(s.AllOf)
+	rs.OneOf = r.resolveAll(s.OneOf)
+	rs.AnyOf = r.resolveAll(s.AnyOf)
+	rs.Not = r.resolve(s.Not)
+	return &rs
+}
+
+func (r *refResolver) resolveAll(ss []spec.Schema) []spec.Schema {
+	if ss == nil {
+		return nil
+	}
+	rs := make([]spec.Schema, len(ss))
+	for i := range ss {
+		rs[i] = *r.resolve(&ss[i])
+	}
+	return rs
+}
+
+func (r *refResolver) resolveRef(s *spec.Schema, ref string) *spec.Schema {
+	name := strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(ref, componentsPrefix))
+	target, ok := r.components[name]
+	switch {
+	case !strings.HasPrefix(ref, componentsPrefix) || !ok:
+		r.diags.AddWarning("Unresolved Schema Reference",
+			fmt.Sprintf("Reference %q doesn't point to a schema of the OpenAPI document. The field it describes is treated as dynamic.", ref))
+		return unknownFieldsSchema(s.Description)
+	case r.resolving[name]:
+		r.diags.AddWarning("Recursive Schema Reference",
+			fmt.Sprintf("Schema %q refers to itself. The recursive field is treated as dynamic.", name))
+		return unknownFieldsSchema(s.Description)
+	}
+
+	r.resolving[name] = true
+	rs := r.resolve(target)
+	delete(r.resolving, name)
+
+	// Keywords next to the reference describe the referring field.
+	if s.Description != "" {
+		rs.Description = s.Description
+	}
+	if s.Default != nil {
+		rs.Default = s.Default
+	}
+	return rs
+}
+
+// unknownFieldsSchema returns a schema accepting any value.
+func unknownFieldsSchema(description string) *spec.Schema {
+	s := &spec.Schema{}
+	s.Description = description
+	s.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+	return s
+}
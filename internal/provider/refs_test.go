@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"testing"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestResolveRefs(t *testing.T) {
+	node := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"value":    *spec.StringProperty(),
+			"children": *spec.ArrayProperty(spec.RefSchema(componentsPrefix + "com.example.Node")),
+		},
+	}}
+	requirements := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"cpu": *spec.StringProperty()},
+	}}
+	components := map[string]*spec.Schema{
+		"com.example.Node":         node,
+		"com.example.Requirements": requirements,
+	}
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"resources": *spec.RefSchema(componentsPrefix + "com.example.Requirements").WithDescription("Compute resources."),
+			"tree":      *spec.RefSchema(componentsPrefix + "com.example.Node"),
+			"missing":   *spec.RefSchema(componentsPrefix + "com.example.Missing"),
+		},
+	}}
+
+	rs, diags := resolveRefs(s, components)
+	if diags.ErrorsCount() != 0 || diags.WarningsCount() != 2 {
+		t.Errorf("expected two warnings, got %v", diags)
+	}
+
+	res := rs.Properties["resources"]
+	if _, ok := res.Properties["cpu"]; !ok || res.Description != "Compute resources." {
+		t.Errorf("unexpected resolved schema: %#v", res)
+	}
+	children := rs.Properties["tree"].Properties["children"]
+	if !isPreservingUnknownFields(children.Items.Schema) {
+		t.Errorf("expected the recursive reference to preserve unknown fields, got %#v", children.Items.Schema)
+	}
+	missing := rs.Properties["missing"]
+	if !isPreservingUnknownFields(&missing) {
+		t.Errorf("expected the missing reference to preserve unknown fields, got %#v", missing)
+	}
+	if orig := s.Properties["resources"]; orig.Ref.String() == "" {
+		t.Error("expected the original schema to be left as is")
+	}
+}
+
+func isPreservingUnknownFields(s *spec.Schema) bool {
+	v, ok := s.Extensions["x-kubernetes-preserve-unknown-fields"].(bool)
+	return ok && v
+}
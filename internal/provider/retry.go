@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+)
+
+// defaultMaxRetries is the number of times failed requests are retried unless
+// the provider configuration sets max_retries.
+const defaultMaxRetries = 5
+
+// minRetries is the smallest max_retries accepted, which disables retries.
+var minRetries = float64(0)
+
+// retryBackoff spaces out the retries of a request. Retry-After headers sent
+// by the API server take precedence when they ask for longer.
+var retryBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Cap:      30 * time.Second,
+}
+
+// optimisticLockMessage is part of the message of the conflicts the API server
+// reports when an object changed while it was writing it, which it gives up on
+// after retrying a few times itself.
+const optimisticLockMessage = "the object has been modified"
+
+// withRetries makes the clients created from cfg retry requests which fail
+// with throttling, unavailability, optimistic concurrency conflicts or
+// transient network errors, up to maxRetries times. Conflicts of requests
+// which set the resourceVersion of the object they write are left to their
+// callers, which have to read the object again before retrying.
+func withRetries(cfg *rest.Config, maxRetries int) {
+	if maxRetries <= 0 {
+		return
+	}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &retryTransport{next: rt, maxRetries: maxRetries, backoff: retryBackoff}
+	})
+}
+
+// retryTransport retries the requests sent by next, backing off
+// exponentially between attempts.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	backoff    wait.Backoff
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	b := t.backoff
+	b.Steps = t.maxRetries
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		reason, after := retryReason(req, resp, err)
+		// Requests whose body can't be read again can't be sent again.
+		if reason == "" || attempt > t.maxRetries || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		delay := b.Step()
+		if after > delay {
+			delay = after
+		}
+		tflog.Debug(ctx, "Retrying request to the API server", map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"attempt": attempt,
+			"reason":  reason,
+			"delay":   delay.String(),
+		})
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (after %d attempts, last failed with %s)", ctx.Err(), attempt, reason)
+		case <-timer.C:
+		}
+
+		req = req.Clone(ctx)
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryReason returns why the request req, which got resp or failed with err,
+// is worth sending again, or an empty string if it isn't, along with how long
+// the API server asked to wait before doing so. Requests creating objects are
+// only sent again when the API server can't have processed them.
+func retryReason(req *http.Request, resp *http.Response, err error) (string, time.Duration) {
+	idempotent := req.Method != http.MethodPost
+	if err != nil {
+		switch {
+		case utilnet.IsConnectionRefused(err):
+			return "connection refused", 0
+		case !idempotent:
+			return "", 0
+		case utilnet.IsConnectionReset(err), utilnet.IsHTTP2ConnectionLost(err), utilnet.IsProbableEOF(err):
+			return err.Error(), 0
+		}
+		return "", 0
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return resp.Status, retryAfter(resp)
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		if idempotent {
+			return resp.Status, retryAfter(resp)
+		}
+	case http.StatusConflict:
+		if optimisticLockConflict(resp) && !hasResourceVersion(req) {
+			return resp.Status, 0
+		}
+	}
+	return "", 0
+}
+
+// retryAfter returns the delay asked for by the Retry-After header of resp, if
+// any, in seconds as the API server sends it.
+func retryAfter(resp *http.Response) time.Duration {
+	s, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || s < 0 {
+		return 0
+	}
+	return time.Duration(s) * time.Second
+}
+
+// optimisticLockConflict reports whether resp reports an optimistic
+// concurrency conflict, rather than conflicting field managers or failed
+// preconditions, which sending the request again doesn't resolve. The body of
+// resp is left readable.
+func optimisticLockConflict(resp *http.Response) bool {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var status metav1.Status
+	if json.Unmarshal(body, &status) != nil || status.Reason != metav1.StatusReasonConflict {
+		return false
+	}
+	if status.Details != nil {
+		for _, c := range status.Details.Causes {
+			if c.Type == metav1.CauseTypeFieldManagerConflict {
+				return false
+			}
+		}
+	}
+	return strings.Contains(status.Message, optimisticLockMessage)
+}
+
+// hasResourceVersion reports whether the body of req sets the resourceVersion
+// of the object it writes. The API server rejects such requests with a
+// conflict for as long as the object doesn't have that version, so sending
+// them again as they are can't succeed.
+func hasResourceVersion(req *http.Request) bool {
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+	var obj struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	if json.NewDecoder(body).Decode(&obj) != nil {
+		return false
+	}
+	return obj.Metadata.ResourceVersion != ""
+}
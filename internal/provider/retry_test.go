@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestRetryTransport(t *testing.T) {
+	conflict := `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Conflict","code":409,` +
+		`"message":"Operation cannot be fulfilled on widgets.example.com \"test\": the object has been modified; please apply your changes to the latest version and try again"}`
+	fieldConflict := `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Conflict","code":409,` +
+		`"message":"Apply failed with 1 conflict","details":{"causes":[{"reason":"FieldManagerConflict","message":"conflict with \"kubectl\"","field":".spec.replicas"}]}}`
+
+	tests := []struct {
+		name     string
+		method   string
+		failures int
+		status   int
+		body     string
+		request  string
+		attempts int
+		want     int
+	}{
+		{name: "throttled", method: http.MethodGet, failures: 2, status: http.StatusTooManyRequests, attempts: 3, want: http.StatusOK},
+		{name: "unavailable", method: http.MethodPatch, failures: 1, status: http.StatusServiceUnavailable, attempts: 2, want: http.StatusOK},
+		{name: "exhausted", method: http.MethodGet, failures: 10, status: http.StatusServiceUnavailable, attempts: 4, want: http.StatusServiceUnavailable},
+		{name: "optimistic lock conflict", method: http.MethodPatch, failures: 1, status: http.StatusConflict, body: conflict, attempts: 2, want: http.StatusOK},
+		{name: "preconditioned conflict", method: http.MethodPatch, failures: 1, status: http.StatusConflict, body: conflict, request: `{"metadata":{"resourceVersion":"42"}}`, attempts: 1, want: http.StatusConflict},
+		{name: "field manager conflict", method: http.MethodPatch, failures: 1, status: http.StatusConflict, body: fieldConflict, attempts: 1, want: http.StatusConflict},
+		{name: "create timed out", method: http.MethodPost, failures: 1, status: http.StatusGatewayTimeout, attempts: 1, want: http.StatusGatewayTimeout},
+		{name: "not found", method: http.MethodGet, failures: 1, status: http.StatusNotFound, attempts: 1, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := tt.request
+			if request == "" {
+				request = "{}"
+			}
+			attempts := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if b, _ := io.ReadAll(r.Body); r.Method != http.MethodGet && string(b) != request {
+					t.Errorf("expected the request body to be sent on every attempt, got %q", b)
+				}
+				if attempts <= tt.failures {
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, tt.body)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			rt := &retryTransport{next: http.DefaultTransport, maxRetries: 3, backoff: wait.Backoff{Duration: time.Millisecond, Factor: 2}}
+			var body io.Reader
+			if tt.method != http.MethodGet {
+				body = strings.NewReader(request)
+			}
+			req, err := http.NewRequest(tt.method, srv.URL, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, resp.StatusCode)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("expected %d attempts, got %d", tt.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryTransportCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := &retryTransport{next: http.DefaultTransport, maxRetries: 3, backoff: retryBackoff}
+	start := time.Now()
+	_, err = rt.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be reported, got %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Error("expected retries to stop when the context is done")
+	}
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// validationRule is a CEL rule from the x-kubernetes-validations of a schema.
+type validationRule struct {
+	Rule    string
+	Message string
+}
+
+// validationRulesFromOAPI returns the CEL rules declared on s. Rules are
+// enforced by the API server; those written in the subset of CEL parsed by
+// parseCELRule are evaluated when planning as well.
+func validationRulesFromOAPI(s *spec.Schema) []validationRule {
+	if s == nil {
+		return nil
+	}
+	rs, ok := s.Extensions["x-kubernetes-validations"].([]interface{})
+	if !ok {
+		return nil
+	}
+	var rules []validationRule
+	for _, r := range rs {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rule, _ := rm["rule"].(string)
+		if rule == "" {
+			continue
+		}
+		msg, _ := rm["message"].(string)
+		rules = append(rules, validationRule{Rule: rule, Message: msg})
+	}
+	return rules
+}
+
+// withRulesDescription returns s with its CEL rules appended to its
+// description, so that they show up in the documentation of the attribute.
+// s itself is left as is.
+func withRulesDescription(s *spec.Schema) *spec.Schema {
+	rules := validationRulesFromOAPI(s)
+	if len(rules) == 0 {
+		return s
+	}
+	var b strings.Builder
+	b.WriteString(s.Description)
+	if b.Len() > 0 {
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Validation rules:")
+	for _, r := range rules {
+		if r.Message != "" {
+			fmt.Fprintf(&b, "\n- `%s`: %s", r.Rule, r.Message)
+		} else {
+			fmt.Fprintf(&b, "\n- `%s`", r.Rule)
+		}
+	}
+	rs := *s
+	rs.Description = b.String()
+	return &rs
+}
+
+// validationRuleDiagnostics evaluates the CEL rules declared in s against v,
+// the part of a manifest s describes, and reports the rules v breaks. p is the
+// path of the attribute holding v. Rules which can't be evaluated locally are
+// left to the API server.
+func validationRuleDiagnostics(s *spec.Schema, v interface{}, p path.Path) diag.Diagnostics {
+	if s == nil || v == nil {
+		return nil
+	}
+	if len(s.AllOf) > 0 {
+		s = flattenAllOf(s)
+	}
+	var diags diag.Diagnostics
+	for _, r := range validationRulesFromOAPI(s) {
+		e, ok := parseCELRule(r.Rule)
+		if !ok {
+			continue
+		}
+		if res, ok := e.eval(v); ok && res == false {
+			msg := r.Message
+			if msg == "" {
+				msg = fmt.Sprintf("failed rule: %s", r.Rule)
+			}
+			diags.AddAttributeError(p, "Validation Rule Failed", msg)
+		}
+	}
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		if len(s.Properties) > 0 {
+			names := attributeNames(s)
+			for k, ps := range s.Properties {
+				// The fields of inline properties belong to the enclosing
+				// object, which their rules don't apply to.
+				if isInline(&ps) {
+					continue
+				}
+				diags.Append(validationRuleDiagnostics(&ps, tv[k], p.AtName(names[k]))...)
+			}
+		} else if s.AdditionalProperties != nil {
+			for k, e := range tv {
+				diags.Append(validationRuleDiagnostics(s.AdditionalProperties.Schema, e, p.AtMapKey(k))...)
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			break
+		}
+		for i, e := range tv {
+			// Elements of sets are reported on the set, as they have no
+			// index.
+			ep := p
+			if !isOAPISet(s) {
+				ep = p.AtListIndex(i)
+			}
+			diags.Append(validationRuleDiagnostics(s.Items.Schema, e, ep)...)
+		}
+	}
+	return diags
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestValidationRulesFromOAPI(t *testing.T) {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:        []string{"object"},
+		Description: "Scaling settings.",
+		Properties: map[string]spec.Schema{
+			"replicas":    *spec.Int64Property(),
+			"maxReplicas": *spec.Int64Property(),
+		},
+	}}
+	s.AddExtension("x-kubernetes-validations", []interface{}{
+		map[string]interface{}{"rule": "self.replicas <= self.maxReplicas", "message": "replicas must not exceed maxReplicas"},
+		map[string]interface{}{"rule": "has(self.replicas)"},
+		map[string]interface{}{"message": "no rule"},
+	})
+
+	rules := validationRulesFromOAPI(s)
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %v", rules)
+	}
+
+	a, diags := attributeFromOAPI(s, path.Root("spec"), optionalAttribute)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	desc := a.GetDescription()
+	for _, want := range []string{"Scaling settings.", "`self.replicas <= self.maxReplicas`: replicas must not exceed maxReplicas", "`has(self.replicas)`"} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("expected description to contain %q, got %q", want, desc)
+		}
+	}
+	if s.Description != "Scaling settings." {
+		t.Errorf("expected the schema to be left as is, got description %q", s.Description)
+	}
+}
+
+func TestCELRuleEval(t *testing.T) {
+	self := map[string]interface{}{
+		"replicas":    int64(3),
+		"maxReplicas": int64(5),
+		"ratio":       0.5,
+		"name":        "web",
+		"ports":       []interface{}{int64(80), int64(443)},
+		"labels":      map[string]interface{}{"app": "web"},
+	}
+	for _, tc := range []struct {
+		rule string
+		want interface{}
+	}{
+		{"self.replicas <= self.maxReplicas", true},
+		{"self.replicas > self.maxReplicas", false},
+		{"self.ratio < 1", true},
+		{"self.replicas == 3.0", true},
+		{"self.name == 'web' && self.name != \"api\"", true},
+		{"!(self.replicas >= 5)", true},
+		{"self.replicas >= -1", true},
+		{"has(self.replicas) && size(self.name) == 3", true},
+		{"self.ports.size() == 2 && self.labels.size() == 1", true},
+		{"self.ports == [80, 443]", nil},
+		{"self.minReplicas <= self.maxReplicas", nil},
+		{"!has(self.minReplicas)", nil},
+		// Either operand decides logical operators.
+		{"self.minReplicas > 0 || self.replicas > 0", true},
+		{"self.minReplicas > 0 && self.replicas > 5", false},
+		{"self.replicas == oldSelf.replicas", nil},
+		{"self.replicas + 1 <= self.maxReplicas", nil},
+		{"self.name.startsWith('w')", nil},
+		{"self.replicas < self.maxReplicas < 10", nil},
+	} {
+		t.Run(tc.rule, func(t *testing.T) {
+			e, ok := parseCELRule(tc.rule)
+			var got interface{}
+			if ok {
+				if v, ok := e.eval(self); ok {
+					got = v
+				}
+			}
+			if got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestValidationRuleDiagnostics(t *testing.T) {
+	ss := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"minReplicas": *spec.Int64Property(),
+			"maxReplicas": *spec.Int64Property(),
+		},
+	}}
+	ss.AddExtension("x-kubernetes-validations", []interface{}{
+		map[string]interface{}{"rule": "self.minReplicas <= self.maxReplicas", "message": "minReplicas must not exceed maxReplicas"},
+		map[string]interface{}{"rule": "self.maxReplicas < 100"},
+	})
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Properties: map[string]spec.Schema{"spec": ss},
+	}}
+
+	obj := map[string]interface{}{"spec": map[string]interface{}{"minReplicas": int64(300), "maxReplicas": int64(200)}}
+	diags := validationRuleDiagnostics(s, obj, path.Empty())
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %v", diags)
+	}
+	for _, d := range diags {
+		if d, ok := d.(diag.DiagnosticWithPath); !ok || !d.Path().Equal(path.Root("spec")) {
+			t.Errorf("expected the diagnostic on spec, got %v", d)
+		}
+	}
+	if diags[1].Detail() != "failed rule: self.maxReplicas < 100" {
+		t.Errorf("unexpected detail: %s", diags[1].Detail())
+	}
+
+	obj = map[string]interface{}{"spec": map[string]interface{}{"maxReplicas": int64(10)}}
+	if diags := validationRuleDiagnostics(s, obj, path.Empty()); diags.HasError() {
+		t.Errorf("expected rules reading unset fields to be left to the API server, got %v", diags)
+	}
+}
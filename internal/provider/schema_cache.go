@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// schemaCache stores the schemas of custom resource definition versions on
+// disk, so that plans against an unchanged cluster don't download and convert
+// them again. It is disabled when dir is empty.
+type schemaCache struct {
+	dir string
+}
+
+// defaultSchemaCacheDir returns the directory schemas are cached in when
+// CRD_SCHEMA_CACHE_DIR isn't set, or an empty string if the user has no cache
+// directory.
+func defaultSchemaCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "terraform-provider-crd", "schemas")
+}
+
+// schemaCacheKey identifies the schema of ver of crd read from source. The
+// resource version of a definition changes whenever it is updated, which
+// invalidates the schemas cached for it.
+func schemaCacheKey(source string, crd apiextv1.CustomResourceDefinition, ver apiextv1.CustomResourceDefinitionVersion) string {
+	h := sha256.New()
+	for _, s := range []string{source, string(crd.UID), crd.ResourceVersion, ver.Name} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// load returns the schema cached under key, or nil if there is none.
+func (c schemaCache) load(key string) *spec.Schema {
+	if c.dir == "" {
+		return nil
+	}
+	b, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+	s := &spec.Schema{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil
+	}
+	return s
+}
+
+// store caches s under key. The file is written next to its final location
+// and renamed into place, so that concurrent runs never read partial files.
+func (c schemaCache) store(key string, s *spec.Schema) error {
+	if c.dir == "" {
+		return nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(c.dir, key+".json"))
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"os"
+	"testing"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSchemaCache(t *testing.T) {
+	cache := schemaCache{dir: t.TempDir()}
+	crd := apiextv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{UID: "1234", ResourceVersion: "1"}}
+	ver := apiextv1.CustomResourceDefinitionVersion{Name: "v1"}
+	key := schemaCacheKey(schemaSourceOpenAPI, crd, ver)
+
+	if s := cache.load(key); s != nil {
+		t.Fatal("expected a miss on an empty cache")
+	}
+	if err := cache.store(key, testCRDSchema()); err != nil {
+		t.Fatal(err)
+	}
+	s := cache.load(key)
+	if s == nil {
+		t.Fatal("expected a hit after storing the schema")
+	}
+	if _, ok := s.Properties["spec"]; !ok {
+		t.Error("expected the cached schema to keep its properties")
+	}
+	entries, err := os.ReadDir(cache.dir)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected a single cache file, got %d (err: %v)", len(entries), err)
+	}
+
+	crd.ResourceVersion = "2"
+	if schemaCacheKey(schemaSourceOpenAPI, crd, ver) == key {
+		t.Error("expected updating the definition to change the key")
+	}
+	if schemaCacheKey(schemaSourceCRD, crd, ver) == schemaCacheKey(schemaSourceOpenAPI, crd, ver) {
+		t.Error("expected the schema source to be part of the key")
+	}
+
+	disabled := schemaCache{}
+	if err := disabled.store(key, testCRDSchema()); err != nil || disabled.load(key) != nil {
+		t.Errorf("expected a disabled cache to store nothing (err: %v)", err)
+	}
+}
+
+func TestSchemaOptionsFromEnvCacheDir(t *testing.T) {
+	t.Setenv("CRD_SCHEMA_CACHE_DIR", "")
+	o, err := schemaOptionsFromEnv()
+	if err != nil || o.cacheDir != "" {
+		t.Errorf("expected an empty value to disable the cache, got %q (%v)", o.cacheDir, err)
+	}
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Ways of reporting schemas which drifted from the lock file.
+const (
+	lockDriftError = "error"
+	lockDriftWarn  = "warn"
+)
+
+// schemaLock is the content of a schema lock file. It maps the names of
+// the generated resources, without the provider prefix, to the hashes of
+// their schemas.
+type schemaLock struct {
+	Schemas map[string]string `json:"schemas"`
+}
+
+// schemaHash returns the hash of the schema of k.
+func schemaHash(k customKind) (string, error) {
+	b, err := json.Marshal(k.schema)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// checkSchemaLock compares the schemas of kinds with those recorded in the
+// lock file at path. The file is written when it doesn't exist, so that
+// deleting it records the current schemas. Kinds missing from the file are
+// left out of the comparison, as they can't change the plan of existing
+// configurations. Drift is reported as errors, or as warnings if drift is
+// lockDriftWarn.
+func checkSchemaLock(path string, drift string, kinds []customKind) diag.Diagnostics {
+	var diags diag.Diagnostics
+	current := schemaLock{Schemas: make(map[string]string, len(kinds))}
+	for _, k := range kinds {
+		h, err := schemaHash(k)
+		if err != nil {
+			diags.AddError("Failed to hash schema", fmt.Sprintf("%s: %s", k.names.Kind, err))
+			return diags
+		}
+		current.Schemas[k.name] = h
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		b, err := json.MarshalIndent(current, "", "  ")
+		if err == nil {
+			err = os.WriteFile(path, append(b, '\n'), 0o644)
+		}
+		if err != nil {
+			diags.AddWarning("Failed to write schema lock file", fmt.Sprintf("%s: %s", path, err))
+		}
+		return diags
+	}
+	if err != nil {
+		diags.AddError("Failed to read schema lock file", fmt.Sprintf("%s: %s", path, err))
+		return diags
+	}
+	var locked schemaLock
+	if err := json.Unmarshal(b, &locked); err != nil {
+		diags.AddError("Invalid schema lock file", fmt.Sprintf("%s: %s", path, err))
+		return diags
+	}
+
+	var drifted []string
+	for name, h := range locked.Schemas {
+		switch ch, ok := current.Schemas[name]; {
+		case !ok:
+			drifted = append(drifted, name+" (removed)")
+		case ch != h:
+			drifted = append(drifted, name+" (changed)")
+		}
+	}
+	if len(drifted) == 0 {
+		return diags
+	}
+	sort.Strings(drifted)
+	summary := "Schemas Drifted From Lock File"
+	detail := fmt.Sprintf("The schemas of these resources differ from those recorded in %s:\n\n- %s\n\n"+
+		"Plans may differ from those of other machines. Delete the lock file to record the current schemas.",
+		path, strings.Join(drifted, "\n- "))
+	if drift == lockDriftWarn {
+		diags.AddWarning(summary, detail)
+	} else {
+		diags.AddError(summary, detail)
+	}
+	return diags
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestCheckSchemaLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crd.lock.json")
+	kind := func(singular string, s *spec.Schema) customKind {
+		return customKind{name: "example_com_v1_" + singular, version: "v1", group: "example.com", names: v1.CustomResourceDefinitionNames{Kind: singular, Singular: singular}, schema: s}
+	}
+	widget, gadget := kind("widget", testCRDSchema()), kind("gadget", spec.StringProperty())
+
+	if diags := checkSchemaLock(path, lockDriftError, []customKind{widget, gadget}); diags.HasError() {
+		t.Fatalf("unexpected errors writing the lock file: %v", diags)
+	}
+	if diags := checkSchemaLock(path, lockDriftError, []customKind{widget, gadget, kind("gizmo", spec.BoolProperty())}); len(diags) != 0 {
+		t.Errorf("expected unchanged and added schemas to match the lock file, got %v", diags)
+	}
+
+	changed := kind("widget", spec.StringProperty())
+	diags := checkSchemaLock(path, lockDriftError, []customKind{changed})
+	if !diags.HasError() {
+		t.Fatal("expected an error for drifted schemas")
+	}
+	for _, want := range []string{"example_com_v1_widget (changed)", "example_com_v1_gadget (removed)"} {
+		if !containsDetail(diags, want) {
+			t.Errorf("expected the drift to mention %q, got %v", want, diags)
+		}
+	}
+
+	if diags := checkSchemaLock(path, lockDriftWarn, []customKind{changed}); diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("expected a single warning, got %v", diags)
+	}
+}
+
+func containsDetail(diags diag.Diagnostics, s string) bool {
+	for _, d := range diags {
+		if strings.Contains(d.Detail(), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/openapi3"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Sources of the schemas resources are generated from.
+const (
+	// schemaSourceOpenAPI reads schemas from the OpenAPI v3 documents
+	// published by the API server.
+	schemaSourceOpenAPI = "openapi"
+	// schemaSourceCRD reads schemas from the CRD objects themselves, which
+	// saves fetching an OpenAPI document per group version.
+	schemaSourceCRD = "crd"
+)
+
+// Selections of the versions of custom resource definitions resources are
+// generated for.
+const (
+	// versionsStorage selects the storage version, the one objects are
+	// persisted as.
+	versionsStorage = "storage"
+	// versionsLatest selects the newest served version, as ordered by
+	// Kubernetes, with v1 above v1beta2 above v1alpha1.
+	versionsLatest = "latest"
+	// versionsAll selects every served version.
+	versionsAll = "all"
+)
+
+// selectVersions returns the versions of crd resources are generated for.
+// Versions which aren't served are never selected. When the storage version
+// isn't served, the newest served version is selected instead.
+func selectVersions(crd apiextv1.CustomResourceDefinition, selection string) []apiextv1.CustomResourceDefinitionVersion {
+	var served []apiextv1.CustomResourceDefinitionVersion
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			served = append(served, v)
+		}
+	}
+	if selection == versionsAll || len(served) == 0 {
+		return served
+	}
+	if selection == versionsStorage {
+		for _, v := range served {
+			if v.Storage {
+				return []apiextv1.CustomResourceDefinitionVersion{v}
+			}
+		}
+	}
+	latest := served[0]
+	for _, v := range served[1:] {
+		if version.CompareKubeAwareVersionStrings(v.Name, latest.Name) > 0 {
+			latest = v
+		}
+	}
+	return []apiextv1.CustomResourceDefinitionVersion{latest}
+}
+
+// customKind is a version of a custom resource kind served by the cluster.
+type customKind struct {
+	// name is the name of the generated resources, without the provider
+	// prefix.
+	name    string
+	version string
+	group   string
+	names   apiextv1.CustomResourceDefinitionNames
+	scope   apiextv1.ResourceScope
+	schema  *spec.Schema
+}
+
+// openAPIFetchWorkers bounds the number of OpenAPI documents fetched at once.
+const openAPIFetchWorkers = 8
+
+// openAPIDocument is the OpenAPI document of a group version, or the error
+// fetching it.
+type openAPIDocument struct {
+	spec *spec3.OpenAPI
+	err  error
+}
+
+// fetchOpenAPIDocuments fetches the OpenAPI documents of gvs concurrently.
+// Each group version is fetched once, however many kinds it serves.
+func fetchOpenAPIDocuments(root openapi3.Root, gvs []rtschema.GroupVersion) map[rtschema.GroupVersion]openAPIDocument {
+	docs := make(map[rtschema.GroupVersion]openAPIDocument, len(gvs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, openAPIFetchWorkers)
+	seen := make(map[rtschema.GroupVersion]bool, len(gvs))
+	for _, gv := range gvs {
+		if seen[gv] {
+			continue
+		}
+		seen[gv] = true
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			s, err := root.GVSpec(gv)
+			mu.Lock()
+			docs[gv] = openAPIDocument{spec: s, err: err}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return docs
+}
+
+// openAPISchema returns the schema of gvk from gvspec, the OpenAPI document
+// of its group version, or nil if it can't be found.
+func (p *KubernetesCRD) openAPISchema(gvspec *spec3.OpenAPI, gvk rtschema.GroupVersionKind) *spec.Schema {
+	gv := gvk.GroupVersion()
+	s := componentForGVK(gvspec.Components.Schemas, gvk)
+	if s == nil {
+		p.discoveryDiags.AddWarning(
+			"Missing OpenAPI schema",
+			fmt.Sprintf("No resource was generated for %s (%s): the OpenAPI document has no schema for it.", gvk.Kind, gv),
+		)
+		return nil
+	}
+	s, diags := resolveRefs(s, gvspec.Components.Schemas)
+	for _, d := range diags {
+		p.discoveryDiags.AddWarning(d.Summary(), fmt.Sprintf("%s (%s): %s", gvk.Kind, gv, d.Detail()))
+	}
+	return s
+}
+
+// componentForGVK returns the schema of components describing gvk. Schemas
+// are matched by their x-kubernetes-group-version-kind extension, or else by
+// the kind ending their name when a single one does.
+func componentForGVK(components map[string]*spec.Schema, gvk rtschema.GroupVersionKind) *spec.Schema {
+	var named []*spec.Schema
+	for k, s := range components {
+		if s == nil {
+			continue
+		}
+		gvks, _ := s.Extensions["x-kubernetes-group-version-kind"].([]interface{})
+		for _, e := range gvks {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+				return s
+			}
+		}
+		if len(gvks) == 0 && (k == gvk.Kind || strings.HasSuffix(k, "."+gvk.Kind)) {
+			named = append(named, s)
+		}
+	}
+	if len(named) == 1 {
+		return named[0]
+	}
+	return nil
+}
+
+// crdSchema returns the structural schema declared by ver of crd, or nil if it
+// declares none.
+func (p *KubernetesCRD) crdSchema(crd apiextv1.CustomResourceDefinition, ver apiextv1.CustomResourceDefinitionVersion) *spec.Schema {
+	gv := rtschema.GroupVersion{Version: ver.Name, Group: crd.Spec.Group}
+	if ver.Schema == nil || ver.Schema.OpenAPIV3Schema == nil {
+		p.discoveryDiags.AddWarning(
+			"Missing CRD schema",
+			fmt.Sprintf("No resource was generated for %s (%s): the CRD declares no schema for it.", crd.Spec.Names.Kind, gv),
+		)
+		return nil
+	}
+	s, err := schemaFromJSONSchemaProps(ver.Schema.OpenAPIV3Schema)
+	if err != nil {
+		p.discoveryDiags.AddWarning(
+			"Invalid CRD schema",
+			fmt.Sprintf("No resource was generated for %s (%s): %s", crd.Spec.Names.Kind, gv, err),
+		)
+		return nil
+	}
+	return s
+}
+
+// legacyObjectSchema returns the schema of kinds whose definitions set
+// preserveUnknownFields, which predate structural schemas. Whatever schema
+// they declare may not describe their objects, so all their fields but the
+// metadata are held by a single dynamic object attribute instead.
+func legacyObjectSchema() *spec.Schema {
+	obj := unknownFieldsSchema("Fields of the object other than `metadata`, such as `spec`, as they are sent to the API server. " +
+		"The definition of this kind preserves unknown fields, so it has no schema to generate attributes from.")
+	obj.AddExtension(inlineFieldsExtension, true)
+	s := &spec.Schema{}
+	s.Type = spec.StringOrArray{"object"}
+	s.Properties = map[string]spec.Schema{"object": *obj}
+	return s
+}
+
+// schemaFromJSONSchemaProps converts the schema of a CRD version into an
+// OpenAPI schema. Both share the same JSON form, extensions included.
+func schemaFromJSONSchemaProps(props *apiextv1.JSONSchemaProps) (*spec.Schema, error) {
+	data, err := json.Marshal(props)
+	if err != nil {
+		return nil, err
+	}
+	s := &spec.Schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
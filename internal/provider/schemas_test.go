@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	rtschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/openapi3"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestSchemaFromJSONSchemaProps(t *testing.T) {
+	preserve := true
+	props := &apiextv1.JSONSchemaProps{
+		Type:        "object",
+		Description: "Widget is a test kind.",
+		Properties: map[string]apiextv1.JSONSchemaProps{
+			"spec": {
+				Type:     "object",
+				Required: []string{"replicas"},
+				Properties: map[string]apiextv1.JSONSchemaProps{
+					"replicas": {Type: "integer", Format: "int64", Default: &apiextv1.JSON{Raw: []byte("1")}},
+					"port":     {XIntOrString: true, AnyOf: []apiextv1.JSONSchemaProps{{Type: "integer"}, {Type: "string"}}},
+					"extra":    {Type: "object", XPreserveUnknownFields: &preserve},
+				},
+			},
+		},
+	}
+	s, err := schemaFromJSONSchemaProps(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Description != props.Description || !s.Type.Contains("object") {
+		t.Errorf("unexpected root schema: %+v", s.SchemaProps)
+	}
+	sp := s.Properties["spec"]
+	if r := sp.Properties["replicas"]; r.Format != "int64" || r.Default == nil {
+		t.Errorf("unexpected replicas schema: %+v", r.SchemaProps)
+	}
+	port := sp.Properties["port"]
+	if !isIntOrString(&port) {
+		t.Error("expected port to be an int-or-string")
+	}
+	extra := sp.Properties["extra"]
+	if !isSchemaless(&extra) {
+		t.Error("expected extra to preserve unknown fields")
+	}
+}
+
+func TestSchemaOptionsFromEnvSource(t *testing.T) {
+	o, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.source != schemaSourceOpenAPI {
+		t.Errorf("expected the OpenAPI source by default, got %q", o.source)
+	}
+
+	t.Setenv("CRD_SCHEMA_SOURCE", "crd")
+	if o, err = schemaOptionsFromEnv(); err != nil || o.source != schemaSourceCRD {
+		t.Errorf("expected the CRD source, got %q (%v)", o.source, err)
+	}
+
+	t.Setenv("CRD_SCHEMA_SOURCE", "cluster")
+	if _, err = schemaOptionsFromEnv(); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+}
+
+func TestSchemaOptionsFromEnvVersions(t *testing.T) {
+	o, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.versions != versionsStorage {
+		t.Errorf("expected the storage version by default, got %q", o.versions)
+	}
+
+	t.Setenv("CRD_VERSIONS", "all")
+	if o, err = schemaOptionsFromEnv(); err != nil || o.versions != versionsAll {
+		t.Errorf("expected all versions, got %q (%v)", o.versions, err)
+	}
+
+	t.Setenv("CRD_VERSIONS", "newest")
+	if _, err = schemaOptionsFromEnv(); err == nil {
+		t.Error("expected an error for an unknown selection")
+	}
+}
+
+func TestSchemaOptionsSelects(t *testing.T) {
+	t.Setenv("CRD_INCLUDE_CRDS", "*.crossplane.io/*, cert-manager.io/Certificate")
+	t.Setenv("CRD_EXCLUDE_CRDS", "pkg.crossplane.io/*")
+	o, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		group, kind string
+		want        bool
+	}{
+		{"apiextensions.crossplane.io", "Composition", true},
+		{"pkg.crossplane.io", "Provider", false},
+		{"cert-manager.io", "Certificate", true},
+		{"cert-manager.io", "Issuer", false},
+		{"example.com", "Widget", false},
+	}
+	for _, c := range cases {
+		if got := o.selects(c.group, c.kind); got != c.want {
+			t.Errorf("%s/%s: expected %t, got %t", c.group, c.kind, c.want, got)
+		}
+	}
+
+	if !(schemaOptions{}).selects("example.com", "Widget") {
+		t.Error("expected all definitions to be selected without patterns")
+	}
+
+	t.Setenv("CRD_EXCLUDE_CRDS", "[")
+	if _, err := schemaOptionsFromEnv(); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestComponentForGVK(t *testing.T) {
+	component := func(group, kind string) *spec.Schema {
+		s := spec.StringProperty()
+		s.AddExtension("x-kubernetes-group-version-kind", []interface{}{
+			map[string]interface{}{"group": group, "version": "v1", "kind": kind},
+		})
+		return s
+	}
+	cm := component("cert-manager.io", "Certificate")
+	acm := component("acm.services.k8s.aws", "Certificate")
+	components := map[string]*spec.Schema{
+		"io.cert-manager.v1.Certificate":      cm,
+		"aws.k8s.services.acm.v1.Certificate": acm,
+	}
+	for i := 0; i < 10; i++ {
+		if got := componentForGVK(components, rtschema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}); got != cm {
+			t.Fatalf("expected the cert-manager schema, got %v", got)
+		}
+	}
+	if got := componentForGVK(components, rtschema.GroupVersionKind{Group: "cert-manager.io", Version: "v2", Kind: "Certificate"}); got != nil {
+		t.Errorf("expected no schema for another version, got %v", got)
+	}
+
+	legacy := map[string]*spec.Schema{"com.example.v1.Widget": spec.StringProperty(), "com.example.v1.BigWidget": spec.StringProperty()}
+	if got := componentForGVK(legacy, rtschema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}); got != legacy["com.example.v1.Widget"] {
+		t.Errorf("expected the schema named after the kind, got %v", got)
+	}
+}
+
+func TestSelectVersions(t *testing.T) {
+	crd := apiextv1.CustomResourceDefinition{Spec: apiextv1.CustomResourceDefinitionSpec{
+		Versions: []apiextv1.CustomResourceDefinitionVersion{
+			{Name: "v1alpha1", Served: false},
+			{Name: "v1beta1", Served: true, Storage: true},
+			{Name: "v1", Served: true},
+			{Name: "v1beta2", Served: true},
+		},
+	}}
+	names := func(vs []apiextv1.CustomResourceDefinitionVersion) []string {
+		n := make([]string, 0, len(vs))
+		for _, v := range vs {
+			n = append(n, v.Name)
+		}
+		return n
+	}
+	cases := map[string][]string{
+		versionsStorage: {"v1beta1"},
+		versionsLatest:  {"v1"},
+		versionsAll:     {"v1beta1", "v1", "v1beta2"},
+	}
+	for selection, want := range cases {
+		if got := names(selectVersions(crd, selection)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", selection, want, got)
+		}
+	}
+
+	crd.Spec.Versions[1].Storage = false
+	crd.Spec.Versions[0].Storage = true
+	if got := names(selectVersions(crd, versionsStorage)); !reflect.DeepEqual(got, []string{"v1"}) {
+		t.Errorf("expected the latest version when the storage one isn't served, got %v", got)
+	}
+}
+
+// countingRoot serves empty OpenAPI documents, counting the requests made for
+// each group version.
+type countingRoot struct {
+	openapi3.Root
+	mu       sync.Mutex
+	requests map[rtschema.GroupVersion]int
+}
+
+func (r *countingRoot) GVSpec(gv rtschema.GroupVersion) (*spec3.OpenAPI, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[gv]++
+	if gv.Group == "broken.example.com" {
+		return nil, errors.New("not found")
+	}
+	return &spec3.OpenAPI{}, nil
+}
+
+func TestFetchOpenAPIDocuments(t *testing.T) {
+	root := &countingRoot{requests: map[rtschema.GroupVersion]int{}}
+	var gvs []rtschema.GroupVersion
+	for i := 0; i < 3*openAPIFetchWorkers; i++ {
+		gvs = append(gvs, rtschema.GroupVersion{Group: fmt.Sprintf("g%d.example.com", i%openAPIFetchWorkers), Version: "v1"})
+	}
+	gvs = append(gvs, rtschema.GroupVersion{Group: "broken.example.com", Version: "v1"})
+
+	docs := fetchOpenAPIDocuments(root, gvs)
+	if len(docs) != openAPIFetchWorkers+1 {
+		t.Errorf("expected %d documents, got %d", openAPIFetchWorkers+1, len(docs))
+	}
+	for gv, n := range root.requests {
+		if n != 1 {
+			t.Errorf("expected %s to be fetched once, got %d", gv, n)
+		}
+	}
+	if docs[rtschema.GroupVersion{Group: "broken.example.com", Version: "v1"}].err == nil {
+		t.Error("expected the error fetching a document to be kept")
+	}
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ function.Function = &SelectorMatchesFunction{}
+
+func NewSelectorMatchesFunction() function.Function {
+	return &SelectorMatchesFunction{}
+}
+
+// SelectorMatchesFunction matches labels against a label selector, as the API
+// server does when listing objects.
+type SelectorMatchesFunction struct{}
+
+func (f *SelectorMatchesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "selector_matches"
+}
+
+func (f *SelectorMatchesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Match labels against a label selector",
+		MarkdownDescription: "Reports whether a set of labels matches a label selector written the way `kubectl --selector` takes it, e.g. `app=web,tier in (frontend,edge),!canary`. An empty selector matches all labels.",
+		Parameters: []function.Parameter{
+			function.MapParameter{
+				Name:                "labels",
+				MarkdownDescription: "Labels to match, such as the `metadata.labels` of an object.",
+				ElementType:         types.StringType,
+				AllowNullValue:      true,
+			},
+			function.StringParameter{
+				Name:                "selector",
+				MarkdownDescription: "Label selector.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *SelectorMatchesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var ls map[string]string
+	var s string
+	resp.Error = req.Arguments.Get(ctx, &ls, &s)
+	if resp.Error != nil {
+		return
+	}
+	sel, err := labels.Parse(s)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid label selector %q: %s", s, err))
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, sel.Matches(labels.Set(ls)))
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSelectorMatchesFunction(t *testing.T) {
+	ls := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"app":  types.StringValue("web"),
+		"tier": types.StringValue("frontend"),
+	})
+	cases := []struct {
+		labels   types.Map
+		selector string
+		want     bool
+	}{
+		{ls, "app=web", true},
+		{ls, "app=web,tier in (frontend,edge)", true},
+		{ls, "app=web,!canary", true},
+		{ls, "app!=web", false},
+		{ls, "tier notin (frontend)", false},
+		{ls, "", true},
+		{types.MapNull(types.StringType), "app", false},
+		{types.MapNull(types.StringType), "!app", true},
+	}
+	for _, c := range cases {
+		v, ferr := runFunction(t, NewSelectorMatchesFunction(), c.labels, types.StringValue(c.selector))
+		if ferr != nil {
+			t.Fatalf("%q: %s", c.selector, ferr)
+		}
+		var got bool
+		if err := v.As(&got); err != nil || got != c.want {
+			t.Errorf("%q: expected %t, got %t (err: %v)", c.selector, c.want, got, err)
+		}
+	}
+
+	if _, ferr := runFunction(t, NewSelectorMatchesFunction(), ls, types.StringValue("app in web")); ferr == nil {
+		t.Error("expected an error parsing an invalid selector")
+	}
+}
@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// sensitiveWords mark attribute names of secret values.
+var sensitiveWords = map[string]bool{
+	"password":   true,
+	"passwd":     true,
+	"passphrase": true,
+	"secret":     true,
+	"token":      true,
+}
+
+// keyQualifiers mark attribute names ending in key as names of secret values,
+// as in private_key or api_key, unlike selector keys.
+var keyQualifiers = map[string]bool{
+	"access":     true,
+	"api":        true,
+	"client":     true,
+	"encryption": true,
+	"private":    true,
+	"secret":     true,
+	"signing":    true,
+}
+
+// referenceWords end attribute names which refer to secrets rather than hold
+// them, such as secret_name or token_secret_ref.
+var referenceWords = map[string]bool{
+	"file":      true,
+	"name":      true,
+	"namespace": true,
+	"path":      true,
+	"ref":       true,
+	"selector":  true,
+}
+
+// isSensitive reports whether the string field described by s at path p
+// holds a secret, judging by its format or its name.
+func isSensitive(s *spec.Schema, p path.Path) bool {
+	if s == nil || !s.Type.Contains("string") {
+		return false
+	}
+	if s.Format == "password" || s.Format == "byte" {
+		return true
+	}
+	step, _ := p.Steps().LastStep()
+	n, ok := step.(path.PathStepAttributeName)
+	if !ok {
+		return false
+	}
+	words := strings.Split(string(n), "_")
+	last := words[len(words)-1]
+	if referenceWords[last] {
+		return false
+	}
+	if last == "key" && len(words) > 1 && keyQualifiers[words[len(words)-2]] {
+		return true
+	}
+	for _, w := range words {
+		if sensitiveWords[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// withSensitivePaths marks the attributes of attrs, held by the attribute at
+// p, as sensitive or not as listed in paths. Nested attributes are updated in
+// place.
+func withSensitivePaths(attrs map[string]schema.Attribute, p path.Path, paths map[string]bool) {
+	if len(paths) == 0 {
+		return
+	}
+	for n, a := range attrs {
+		ap := p.AtName(n)
+		if v, ok := paths[ap.String()]; ok {
+			a = withSensitive(a, v)
+			attrs[n] = a
+		}
+		switch a := a.(type) {
+		case schema.SingleNestedAttribute:
+			withSensitivePaths(a.Attributes, ap, paths)
+		case schema.ListNestedAttribute:
+			withSensitivePaths(a.NestedObject.Attributes, ap, paths)
+		case schema.SetNestedAttribute:
+			withSensitivePaths(a.NestedObject.Attributes, ap, paths)
+		case schema.MapNestedAttribute:
+			withSensitivePaths(a.NestedObject.Attributes, ap, paths)
+		}
+	}
+}
+
+// withSensitive returns a with its sensitivity set to v.
+func withSensitive(a schema.Attribute, v bool) schema.Attribute {
+	switch a := a.(type) {
+	case schema.StringAttribute:
+		a.Sensitive = v
+		return a
+	case schema.BoolAttribute:
+		a.Sensitive = v
+		return a
+	case schema.Int32Attribute:
+		a.Sensitive = v
+		return a
+	case schema.Int64Attribute:
+		a.Sensitive = v
+		return a
+	case schema.Float32Attribute:
+		a.Sensitive = v
+		return a
+	case schema.Float64Attribute:
+		a.Sensitive = v
+		return a
+	case schema.DynamicAttribute:
+		a.Sensitive = v
+		return a
+	case schema.ListAttribute:
+		a.Sensitive = v
+		return a
+	case schema.ListNestedAttribute:
+		a.Sensitive = v
+		return a
+	case schema.SetAttribute:
+		a.Sensitive = v
+		return a
+	case schema.SetNestedAttribute:
+		a.Sensitive = v
+		return a
+	case schema.MapAttribute:
+		a.Sensitive = v
+		return a
+	case schema.MapNestedAttribute:
+		a.Sensitive = v
+		return a
+	case schema.SingleNestedAttribute:
+		a.Sensitive = v
+		return a
+	}
+	return a
+}
+
+// hasSensitive reports whether any of attrs, or of the attributes nested in
+// them, is sensitive.
+func hasSensitive(attrs map[string]schema.Attribute) bool {
+	for _, a := range attrs {
+		if a.IsSensitive() {
+			return true
+		}
+		switch a := a.(type) {
+		case schema.SingleNestedAttribute:
+			if hasSensitive(a.Attributes) {
+				return true
+			}
+		case schema.ListNestedAttribute:
+			if hasSensitive(a.NestedObject.Attributes) {
+				return true
+			}
+		case schema.SetNestedAttribute:
+			if hasSensitive(a.NestedObject.Attributes) {
+				return true
+			}
+		case schema.MapNestedAttribute:
+			if hasSensitive(a.NestedObject.Attributes) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestIsSensitive(t *testing.T) {
+	cases := map[string]bool{
+		"password":         true,
+		"admin_password":   true,
+		"token":            true,
+		"client_secret":    true,
+		"api_key":          true,
+		"private_key":      true,
+		"secret_name":      false,
+		"token_secret_ref": false,
+		"password_file":    false,
+		"key":              false,
+		"host_name":        false,
+	}
+	for n, want := range cases {
+		if got := isSensitive(spec.StringProperty(), path.Root("spec").AtName(n)); got != want {
+			t.Errorf("%s: got %t, want %t", n, got, want)
+		}
+	}
+	if isSensitive(spec.Int64Property(), path.Root("spec").AtName("token")) {
+		t.Error("expected non-string fields not to be sensitive")
+	}
+	bs := spec.StringProperty()
+	bs.Format = "byte"
+	if !isSensitive(bs, path.Root("spec").AtName("data")) {
+		t.Error("expected byte fields to be sensitive")
+	}
+}
+
+func TestCustomResourceSensitivePaths(t *testing.T) {
+	t.Setenv("CRD_SENSITIVE_ATTRIBUTES", "spec.image, !spec.token")
+	s := testCRDSchema()
+	sp := s.Properties["spec"]
+	sp.Properties["token"] = *spec.StringProperty()
+	s.Properties["spec"] = sp
+
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	opts, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := testCustomResourceSchema(t, NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, s, opts))
+	attrs := rs.Attributes["spec"].(schema.SingleNestedAttribute).Attributes
+	if !attrs["image"].IsSensitive() {
+		t.Error("expected image to be sensitive")
+	}
+	if attrs["token"].IsSensitive() {
+		t.Error("expected token not to be sensitive")
+	}
+	if attrs["replicas"].IsSensitive() {
+		t.Error("expected replicas not to be sensitive")
+	}
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// Default operation timeouts, used when the timeouts attribute leaves them unset.
+var defaultTimeouts = map[string]time.Duration{
+	"create": 20 * time.Minute,
+	"read":   5 * time.Minute,
+	"update": 20 * time.Minute,
+	"delete": 20 * time.Minute,
+}
+
+// TimeoutsModel describes the timeouts attribute of generated resources.
+type TimeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Read   types.String `tfsdk:"read"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
+}
+
+func timeoutsAttribute() schema.Attribute {
+	attrs := make(map[string]schema.Attribute, len(defaultTimeouts))
+	for op, d := range defaultTimeouts {
+		attrs[op] = schema.StringAttribute{
+			MarkdownDescription: "Timeout for " + op + " operations, as a duration string such as `5m`. Defaults to `" + durationString(d) + "`.",
+			Optional:            true,
+			Validators:          []validator.String{durationValidator{}},
+		}
+	}
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Deadlines for the operations performed against the cluster on behalf of this resource.",
+		Optional:            true,
+		Attributes:          attrs,
+	}
+}
+
+// durationString formats d the way durations are written in configurations,
+// as in 20m rather than 20m0s.
+func durationString(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// attributeGetter is implemented by plans and states.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, p path.Path, target interface{}) diag.Diagnostics
+}
+
+// operationTimeout returns the timeout configured for op in the timeouts
+// attribute, falling back to fallback, the operation timeout of the provider
+// configuration, if set, and to the default of op otherwise.
+func operationTimeout(ctx context.Context, g attributeGetter, op string, fallback time.Duration) (time.Duration, diag.Diagnostics) {
+	var s types.String
+	diags := g.GetAttribute(ctx, path.Root("timeouts").AtName(op), &s)
+	if diags.HasError() || s.IsNull() || s.IsUnknown() {
+		if fallback > 0 {
+			return fallback, diags
+		}
+		return defaultTimeouts[op], diags
+	}
+	d, err := time.ParseDuration(s.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("timeouts").AtName(op), "Invalid Duration", err.Error())
+	}
+	return d, diags
+}
+
+// withOperationTimeout returns ctx bounded by the operation timeout of the
+// provider configuration, if it sets one, for operations which have no
+// timeouts attribute.
+func (r *CustomResource) withOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.operationTimeout > 0 {
+		return context.WithTimeout(ctx, r.operationTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// contextError describes why ctx is done: either Terraform interrupted the
+// operation, or it ran out of time.
+func contextError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("the operation timed out: %w", ctx.Err())
+	}
+	return fmt.Errorf("the operation was interrupted: %w", ctx.Err())
+}
+
+// interruptedLookupTimeout bounds the requests made once an operation was
+// interrupted, to find out what it did or to undo it.
+const interruptedLookupTimeout = 10 * time.Second
+
+// createdObject returns the object named name if it exists, looking it up
+// after a create was interrupted, when ctx is done already. The object may
+// have been created before the interruption.
+func createdObject(ctx context.Context, rc dynamic.ResourceInterface, name string) *unstructured.Unstructured {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interruptedLookupTimeout)
+	defer cancel()
+	live, err := rc.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return live
+}
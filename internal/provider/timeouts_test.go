@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestOperationTimeout(t *testing.T) {
+	ctx := context.Background()
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{})
+	s := testCustomResourceSchema(t, r)
+	typ := s.Type().TerraformType(ctx).(tftypes.Object)
+	tt := typ.AttributeTypes["timeouts"].(tftypes.Object)
+	state := func(create interface{}) tfsdk.State {
+		av := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+		for n, at := range typ.AttributeTypes {
+			av[n] = tftypes.NewValue(at, nil)
+		}
+		tv := make(map[string]tftypes.Value, len(tt.AttributeTypes))
+		for n := range tt.AttributeTypes {
+			tv[n] = tftypes.NewValue(tftypes.String, nil)
+		}
+		tv["create"] = tftypes.NewValue(tftypes.String, create)
+		av["timeouts"] = tftypes.NewValue(tt, tv)
+		return tfsdk.State{Schema: s, Raw: tftypes.NewValue(typ, av)}
+	}
+
+	tests := []struct {
+		name     string
+		create   interface{}
+		fallback time.Duration
+		want     time.Duration
+	}{
+		{name: "default", want: defaultTimeouts["create"]},
+		{name: "provider", fallback: 3 * time.Minute, want: 3 * time.Minute},
+		{name: "resource", create: "90s", fallback: 3 * time.Minute, want: 90 * time.Second},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, diags := operationTimeout(ctx, state(tc.create), "create", tc.fallback)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if d != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, d)
+			}
+		})
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	cases := map[time.Duration]string{
+		20 * time.Minute:               "20m",
+		2 * time.Hour:                  "2h",
+		90 * time.Minute:               "1h30m",
+		30 * time.Second:               "30s",
+		5*time.Minute + 30*time.Second: "5m30s",
+		500 * time.Millisecond:         "500ms",
+	}
+	for d, want := range cases {
+		if got := durationString(d); got != want {
+			t.Errorf("%s: expected %q, got %q", d, want, got)
+		}
+	}
+}
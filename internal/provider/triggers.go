@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ planmodifier.Map = triggersModifier{}
+
+// triggersAttribute returns the triggers attribute, arbitrary values whose
+// changes replace the object, for kinds whose controllers only act when
+// objects are created, such as one-shot migrations or backups.
+func triggersAttribute() schema.Attribute {
+	return schema.MapAttribute{
+		MarkdownDescription: "Arbitrary values which force the replacement of the object when they change, " +
+			"for kinds whose controllers only act when objects are created. " +
+			"Setting them on an object which had none, or removing them, doesn't replace it.",
+		Optional:      true,
+		ElementType:   types.StringType,
+		PlanModifiers: []planmodifier.Map{triggersModifier{}},
+	}
+}
+
+// triggersModifier plans the replacement of the resource when its triggers
+// change. Objects without triggers, such as imported ones, aren't replaced
+// when they are given some.
+type triggersModifier struct{}
+
+func (m triggersModifier) Description(ctx context.Context) string {
+	return "Changing the value of this attribute forces the replacement of the resource."
+}
+
+func (m triggersModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m triggersModifier) PlanModifyMap(ctx context.Context, req planmodifier.MapRequest, resp *planmodifier.MapResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() {
+		return
+	}
+	resp.RequiresReplace = !req.StateValue.Equal(req.PlanValue)
+}
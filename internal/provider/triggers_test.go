@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTriggersModifier(t *testing.T) {
+	triggers := func(v string) types.Map {
+		return types.MapValueMust(types.StringType, map[string]attr.Value{"revision": types.StringValue(v)})
+	}
+	cases := []struct {
+		state, plan types.Map
+		replace     bool
+	}{
+		{triggers("1"), triggers("2"), true},
+		{triggers("1"), triggers("1"), false},
+		{triggers("1"), types.MapUnknown(types.StringType), true},
+		{types.MapNull(types.StringType), triggers("1"), false},
+		{triggers("1"), types.MapNull(types.StringType), false},
+	}
+	for _, c := range cases {
+		req := planmodifier.MapRequest{StateValue: c.state, PlanValue: c.plan}
+		resp := &planmodifier.MapResponse{PlanValue: c.plan}
+		triggersModifier{}.PlanModifyMap(context.Background(), req, resp)
+		if resp.RequiresReplace != c.replace {
+			t.Errorf("%s -> %s: got replace %t, want %t", c.state, c.plan, resp.RequiresReplace, c.replace)
+		}
+	}
+}
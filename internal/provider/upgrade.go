@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ resource.ResourceWithUpgradeState = &CustomResource{}
+
+// schemaVersion is the version of the schemas generated for custom resources.
+// It is bumped whenever the conversion of OpenAPI schemas into Terraform ones
+// changes the types of existing attributes, as version 2 did for format-less
+// numbers and collections holding dynamic values. Attributes which CRDs add
+// or remove don't need a new version, as states of the same version are
+// decoded without their removed attributes, and with added ones unset.
+const schemaVersion int64 = 2
+
+// UpgradeState upgrades states of any prior version of the schema. The prior
+// schemas are no longer known, so states are mapped onto the current one by
+// attribute names, leaving out attributes which were removed.
+func (r *CustomResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	upgraders := make(map[int64]resource.StateUpgrader, schemaVersion)
+	for v := int64(0); v < schemaVersion; v++ {
+		upgraders[v] = resource.StateUpgrader{StateUpgrader: r.upgradeState}
+	}
+	return upgraders
+}
+
+func (r *CustomResource) upgradeState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	if req.RawState == nil || req.RawState.JSON == nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", "The prior state has no JSON representation.")
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(req.RawState.JSON))
+	dec.UseNumber()
+	var prior map[string]interface{}
+	if err := dec.Decode(&prior); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to decode the prior state: %s", err))
+		return
+	}
+	v, err := r.stateFromPrior(prior, resp.State.Raw.Type())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", err.Error())
+		return
+	}
+	resp.State.Raw = v
+}
+
+// stateFromPrior converts prior, a state of an earlier schema decoded from
+// JSON, into a value of type t.
+func (r *CustomResource) stateFromPrior(prior map[string]interface{}, t tftypes.Type) (tftypes.Value, error) {
+	obj := manifestFromState(r.schema, prior)
+	v, err := valueFromObject(r.schema, t, obj)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	var av map[string]tftypes.Value
+	if err := v.As(&av); err != nil {
+		return tftypes.Value{}, err
+	}
+	// Provider-defined attributes keep their values, unless their type
+	// changed along with the provider.
+	at := t.(tftypes.Object).AttributeTypes
+	for _, n := range resourceAttributes {
+		if prior[n] == nil {
+			continue
+		}
+		raw, err := json.Marshal(prior[n])
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		if pv, err := tftypes.ValueFromJSON(raw, at[n]); err == nil {
+			av[n] = pv
+		}
+	}
+	return tftypes.NewValue(t, av), nil
+}
+
+// manifestFromState converts v, part of a state decoded from JSON, into its
+// manifest form. Attribute names are mapped back to the field names of s, and
+// attributes without a matching field are left out.
+func manifestFromState(s *spec.Schema, v interface{}) interface{} {
+	if s != nil && len(s.AllOf) > 0 {
+		s = flattenAllOf(s)
+	}
+	if isSchemaless(s) {
+		s = nil
+	}
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		if dv, ok := dynamicStateValue(s, tv); ok {
+			// Dynamic values carry the manifest field names as-is.
+			return dv
+		}
+		mo := make(map[string]interface{}, len(tv))
+		if s != nil && len(s.Properties) > 0 {
+			fields := make(map[string]string, len(s.Properties))
+			for k, n := range attributeNames(s) {
+				fields[n] = k
+			}
+			for n, e := range tv {
+				k, ok := fields[n]
+				if !ok {
+					continue
+				}
+				p := s.Properties[k]
+				if fields, ok := manifestFromState(&p, e).(map[string]interface{}); ok && isInline(&p) {
+					for fk, fe := range fields {
+						mo[fk] = fe
+					}
+					continue
+				}
+				mo[k] = manifestFromState(&p, e)
+			}
+			return mo
+		}
+		var es *spec.Schema
+		if s != nil && s.AdditionalProperties != nil {
+			es = s.AdditionalProperties.Schema
+		}
+		for k, e := range tv {
+			mo[k] = manifestFromState(es, e)
+		}
+		return mo
+	case []interface{}:
+		var es *spec.Schema
+		if s != nil && s.Items != nil {
+			es = s.Items.Schema
+		}
+		lo := make([]interface{}, 0, len(tv))
+		for _, e := range tv {
+			lo = append(lo, manifestFromState(es, e))
+		}
+		return lo
+	}
+	return v
+}
+
+// dynamicStateValue returns the value wrapped by m if m is the JSON form of a
+// dynamic attribute value, which Terraform stores along with its type. s
+// describes the field holding m.
+func dynamicStateValue(s *spec.Schema, m map[string]interface{}) (interface{}, bool) {
+	if len(m) != 2 || m["type"] == nil {
+		return nil, false
+	}
+	dv, ok := m["value"]
+	if !ok {
+		return nil, false
+	}
+	if s != nil {
+		if _, ok := s.Properties["type"]; ok {
+			return nil, false
+		}
+	}
+	return dv, true
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestUpgradeState(t *testing.T) {
+	ctx := context.Background()
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, testCRDSchema(), schemaOptions{}).(*CustomResource)
+	s := testCustomResourceSchema(t, r)
+	if s.Version != schemaVersion {
+		t.Fatalf("expected schema version %d, got %d", schemaVersion, s.Version)
+	}
+	upgraders := r.UpgradeState(ctx)
+	if len(upgraders) != int(schemaVersion) {
+		t.Fatalf("expected upgraders for versions 0 to %d, got %d", schemaVersion-1, len(upgraders))
+	}
+
+	prior := `{
+		"metadata": {"name": "test", "namespace": "default", "generate_name": null},
+		"spec": {"replicas": 3, "image": "nginx", "removed": "gone"},
+		"legacy": {"value": "x", "type": "string"},
+		"wait": null,
+		"timeouts": {"create": "5m", "update": null, "delete": null}
+	}`
+	typ := s.Type().TerraformType(ctx)
+	req := resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(prior)}}
+	resp := &resource.UpgradeStateResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(typ, nil)}}
+	upgraders[1].StateUpgrader(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var av map[string]tftypes.Value
+	if err := resp.State.Raw.As(&av); err != nil {
+		t.Fatal(err)
+	}
+	var spec map[string]tftypes.Value
+	if err := av["spec"].As(&spec); err != nil {
+		t.Fatal(err)
+	}
+	if !spec["image"].Equal(tftypes.NewValue(tftypes.String, "nginx")) {
+		t.Errorf("unexpected image: %s", spec["image"])
+	}
+	if _, ok := spec["removed"]; ok {
+		t.Error("expected removed attributes to be left out")
+	}
+	var timeouts map[string]tftypes.Value
+	if err := av["timeouts"].As(&timeouts); err != nil {
+		t.Fatal(err)
+	}
+	if !timeouts["create"].Equal(tftypes.NewValue(tftypes.String, "5m")) {
+		t.Errorf("expected timeouts to be kept, got %s", av["timeouts"])
+	}
+}
+
+func TestManifestFromStateDynamic(t *testing.T) {
+	v := manifestFromState(nil, map[string]interface{}{
+		"type":  []interface{}{"object", map[string]interface{}{"replicaCount": "number"}},
+		"value": map[string]interface{}{"replicaCount": 3},
+	})
+	mo, ok := v.(map[string]interface{})
+	if !ok || mo["replicaCount"] != 3 {
+		t.Errorf("expected the wrapped value, got %#v", v)
+	}
+}
+
+func TestManifestFromStateInline(t *testing.T) {
+	v := manifestFromState(legacyObjectSchema(), map[string]interface{}{
+		"object": map[string]interface{}{
+			"type":  []interface{}{"object", map[string]interface{}{"spec": []interface{}{"object", map[string]interface{}{"size": "string"}}}},
+			"value": map[string]interface{}{"spec": map[string]interface{}{"size": "large"}},
+		},
+	})
+	want := map[string]interface{}{"spec": map[string]interface{}{"size": "large"}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("expected the fields of the object attribute to be inlined, got %#v", v)
+	}
+}
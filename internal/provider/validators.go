@@ -0,0 +1,477 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var _ validator.String = durationValidator{}
+var _ validator.String = stringOneOfValidator{}
+var _ validator.String = stringPatternValidator{}
+var _ validator.String = stringLengthValidator{}
+var _ validator.Int32 = int32OneOfValidator{}
+var _ validator.Int64 = int64OneOfValidator{}
+var _ validator.Int32 = numberRangeValidator{}
+var _ validator.Int64 = numberRangeValidator{}
+var _ validator.Float32 = numberRangeValidator{}
+var _ validator.Float64 = numberRangeValidator{}
+var _ validator.List = sizeValidator{}
+var _ validator.Set = sizeValidator{}
+
+// durationValidator checks that a string parses as a Go duration.
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a duration string such as \"30s\" or \"5m\""
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a duration string such as `30s` or `5m`"
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := time.ParseDuration(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Duration", err.Error())
+	}
+}
+
+// stringOneOfValidator checks that a string is one of the values of an OpenAPI
+// enum.
+type stringOneOfValidator struct {
+	values []string
+}
+
+func (v stringOneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", strings.Join(v.quoted(), ", "))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) quoted() []string {
+	q := make([]string, len(v.values))
+	for i, e := range v.values {
+		q[i] = fmt.Sprintf("%q", e)
+	}
+	return q
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	for _, e := range v.values {
+		if req.ConfigValue.ValueString() == e {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()))
+}
+
+// stringPatternValidator checks that a string matches the pattern declared by
+// an OpenAPI schema.
+type stringPatternValidator struct {
+	pattern *regexp.Regexp
+}
+
+func (v stringPatternValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must match the regular expression %q", v.pattern)
+}
+
+func (v stringPatternValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("value must match the regular expression `%s`", v.pattern)
+}
+
+func (v stringPatternValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if !v.pattern.MatchString(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()))
+	}
+}
+
+// stringLengthValidator checks that the length of a string, in characters,
+// is within the bounds declared by an OpenAPI schema. Nil bounds are open.
+type stringLengthValidator struct {
+	min, max *int64
+}
+
+func (v stringLengthValidator) Description(ctx context.Context) string {
+	switch {
+	case v.min != nil && v.max != nil:
+		return fmt.Sprintf("string length must be between %d and %d", *v.min, *v.max)
+	case v.min != nil:
+		return fmt.Sprintf("string length must be at least %d", *v.min)
+	default:
+		return fmt.Sprintf("string length must be at most %d", *v.max)
+	}
+}
+
+func (v stringLengthValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringLengthValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	l := int64(utf8.RuneCountInString(req.ConfigValue.ValueString()))
+	if (v.min != nil && l < *v.min) || (v.max != nil && l > *v.max) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value Length",
+			fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), l))
+	}
+}
+
+// int32OneOfValidator checks that an integer is one of the values of an
+// OpenAPI enum.
+type int32OneOfValidator struct {
+	values []int64
+}
+
+func (v int32OneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", joinInts(v.values))
+}
+
+func (v int32OneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int32OneOfValidator) ValidateInt32(ctx context.Context, req validator.Int32Request, resp *validator.Int32Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	for _, e := range v.values {
+		if int64(req.ConfigValue.ValueInt32()) == e {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), req.ConfigValue.ValueInt32()))
+}
+
+// int64OneOfValidator checks that an integer is one of the values of an
+// OpenAPI enum.
+type int64OneOfValidator struct {
+	values []int64
+}
+
+func (v int64OneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", joinInts(v.values))
+}
+
+func (v int64OneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64OneOfValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	for _, e := range v.values {
+		if req.ConfigValue.ValueInt64() == e {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), req.ConfigValue.ValueInt64()))
+}
+
+// numberRangeValidator checks numbers against the minimum, maximum and
+// multipleOf keywords of an OpenAPI schema. It serves all numeric attribute
+// types.
+type numberRangeValidator struct {
+	min, max                   *float64
+	exclusiveMin, exclusiveMax bool
+	multipleOf                 *float64
+}
+
+// newNumberRangeValidator returns a validator for the numeric constraints of
+// s, or nil if it declares none.
+func newNumberRangeValidator(s *spec.Schema) *numberRangeValidator {
+	if s.Minimum == nil && s.Maximum == nil && s.MultipleOf == nil {
+		return nil
+	}
+	return &numberRangeValidator{
+		min:          s.Minimum,
+		max:          s.Maximum,
+		exclusiveMin: s.ExclusiveMinimum,
+		exclusiveMax: s.ExclusiveMaximum,
+		multipleOf:   s.MultipleOf,
+	}
+}
+
+func (v numberRangeValidator) Description(ctx context.Context) string {
+	var c []string
+	if v.min != nil {
+		op := ">="
+		if v.exclusiveMin {
+			op = ">"
+		}
+		c = append(c, fmt.Sprintf("%s %v", op, *v.min))
+	}
+	if v.max != nil {
+		op := "<="
+		if v.exclusiveMax {
+			op = "<"
+		}
+		c = append(c, fmt.Sprintf("%s %v", op, *v.max))
+	}
+	if v.multipleOf != nil {
+		c = append(c, fmt.Sprintf("a multiple of %v", *v.multipleOf))
+	}
+	return "value must be " + strings.Join(c, " and ")
+}
+
+func (v numberRangeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v numberRangeValidator) valid(n float64) bool {
+	switch {
+	case v.min != nil && (n < *v.min || v.exclusiveMin && n == *v.min):
+		return false
+	case v.max != nil && (n > *v.max || v.exclusiveMax && n == *v.max):
+		return false
+	case v.multipleOf != nil && *v.multipleOf != 0:
+		return isMultipleOf(n, *v.multipleOf)
+	}
+	return true
+}
+
+// isMultipleOf reports whether n is a multiple of m. They are divided as the
+// decimals they are written as, so that 0.3 is a multiple of 0.1 even though
+// their binary forms aren't exact.
+func isMultipleOf(n, m float64) bool {
+	rn, ok := new(big.Rat).SetString(strconv.FormatFloat(n, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+	rm, ok := new(big.Rat).SetString(strconv.FormatFloat(m, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+	return new(big.Rat).Quo(rn, rm).IsInt()
+}
+
+func (v numberRangeValidator) validate(ctx context.Context, p path.Path, n float64, diags *diag.Diagnostics) {
+	if !v.valid(n) {
+		diags.AddAttributeError(p, "Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %v", p, v.Description(ctx), n))
+	}
+}
+
+func (v numberRangeValidator) ValidateInt32(ctx context.Context, req validator.Int32Request, resp *validator.Int32Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	v.validate(ctx, req.Path, float64(req.ConfigValue.ValueInt32()), &resp.Diagnostics)
+}
+
+func (v numberRangeValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	v.validate(ctx, req.Path, float64(req.ConfigValue.ValueInt64()), &resp.Diagnostics)
+}
+
+func (v numberRangeValidator) ValidateFloat32(ctx context.Context, req validator.Float32Request, resp *validator.Float32Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	// The shortest decimal form of the value, such as 0.3, is kept rather
+	// than that of its widened binary form.
+	n, _ := strconv.ParseFloat(strconv.FormatFloat(float64(req.ConfigValue.ValueFloat32()), 'g', -1, 32), 64)
+	v.validate(ctx, req.Path, n, &resp.Diagnostics)
+}
+
+func (v numberRangeValidator) ValidateFloat64(ctx context.Context, req validator.Float64Request, resp *validator.Float64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	v.validate(ctx, req.Path, req.ConfigValue.ValueFloat64(), &resp.Diagnostics)
+}
+
+// sizeValidator checks that the number of elements of a list or set is within
+// the minItems and maxItems bounds of an OpenAPI schema. Nil bounds are open.
+type sizeValidator struct {
+	min, max *int64
+}
+
+func (v sizeValidator) Description(ctx context.Context) string {
+	switch {
+	case v.min != nil && v.max != nil:
+		return fmt.Sprintf("must contain between %d and %d elements", *v.min, *v.max)
+	case v.min != nil:
+		return fmt.Sprintf("must contain at least %d elements", *v.min)
+	default:
+		return fmt.Sprintf("must contain at most %d elements", *v.max)
+	}
+}
+
+func (v sizeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sizeValidator) validate(ctx context.Context, p path.Path, n int, diags *diag.Diagnostics) {
+	l := int64(n)
+	if (v.min != nil && l < *v.min) || (v.max != nil && l > *v.max) {
+		diags.AddAttributeError(p, "Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %d", p, v.Description(ctx), l))
+	}
+}
+
+func (v sizeValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	v.validate(ctx, req.Path, len(req.ConfigValue.Elements()), &resp.Diagnostics)
+}
+
+func (v sizeValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	v.validate(ctx, req.Path, len(req.ConfigValue.Elements()), &resp.Diagnostics)
+}
+
+func joinInts(values []int64) string {
+	s := make([]string, len(values))
+	for i, e := range values {
+		s[i] = fmt.Sprint(e)
+	}
+	return strings.Join(s, ", ")
+}
+
+// stringEnum returns the string values of the enum declared by s.
+func stringEnum(s *spec.Schema) []string {
+	var values []string
+	for _, e := range s.Enum {
+		if sv, ok := e.(string); ok {
+			values = append(values, sv)
+		}
+	}
+	return values
+}
+
+// integerEnum returns the integer values of the enum declared by s.
+func integerEnum(s *spec.Schema) []int64 {
+	var values []int64
+	for _, e := range s.Enum {
+		nv, err := numberFromObject(e)
+		if err != nil || !nv.IsInt() {
+			continue
+		}
+		iv, _ := nv.Int64()
+		values = append(values, iv)
+	}
+	return values
+}
+
+// stringValidatorsFromOAPI returns validators enforcing the constraints s
+// places on string values. Computed attributes aren't configured and get none.
+func stringValidatorsFromOAPI(s *spec.Schema, m attributeMode) []validator.String {
+	if m == computedAttribute {
+		return nil
+	}
+	var vs []validator.String
+	if values := stringEnum(s); len(values) > 0 {
+		vs = append(vs, stringOneOfValidator{values: values})
+	}
+	if s.Pattern != "" {
+		// OpenAPI patterns follow ECMA-262, which accepts some expressions
+		// RE2 doesn't. Those are left to the API server to enforce.
+		if re, err := regexp.Compile(s.Pattern); err == nil {
+			vs = append(vs, stringPatternValidator{pattern: re})
+		}
+	}
+	if s.MinLength != nil || s.MaxLength != nil {
+		vs = append(vs, stringLengthValidator{min: s.MinLength, max: s.MaxLength})
+	}
+	return vs
+}
+
+// int32ValidatorsFromOAPI returns validators enforcing the constraints s
+// places on int32 values.
+func int32ValidatorsFromOAPI(s *spec.Schema, m attributeMode) []validator.Int32 {
+	if m == computedAttribute {
+		return nil
+	}
+	var vs []validator.Int32
+	if values := integerEnum(s); len(values) > 0 {
+		vs = append(vs, int32OneOfValidator{values: values})
+	}
+	if rv := newNumberRangeValidator(s); rv != nil {
+		vs = append(vs, *rv)
+	}
+	return vs
+}
+
+// int64ValidatorsFromOAPI returns validators enforcing the constraints s
+// places on int64 values.
+func int64ValidatorsFromOAPI(s *spec.Schema, m attributeMode) []validator.Int64 {
+	if m == computedAttribute {
+		return nil
+	}
+	var vs []validator.Int64
+	if values := integerEnum(s); len(values) > 0 {
+		vs = append(vs, int64OneOfValidator{values: values})
+	}
+	if rv := newNumberRangeValidator(s); rv != nil {
+		vs = append(vs, *rv)
+	}
+	return vs
+}
+
+// float32ValidatorsFromOAPI returns validators enforcing the constraints s
+// places on float32 values.
+func float32ValidatorsFromOAPI(s *spec.Schema, m attributeMode) []validator.Float32 {
+	if rv := newNumberRangeValidator(s); rv != nil && m != computedAttribute {
+		return []validator.Float32{*rv}
+	}
+	return nil
+}
+
+// float64ValidatorsFromOAPI returns validators enforcing the constraints s
+// places on float64 values.
+func float64ValidatorsFromOAPI(s *spec.Schema, m attributeMode) []validator.Float64 {
+	if rv := newNumberRangeValidator(s); rv != nil && m != computedAttribute {
+		return []validator.Float64{*rv}
+	}
+	return nil
+}
+
+// listValidatorsFromOAPI returns validators enforcing the constraints s places
+// on arrays converted to lists.
+func listValidatorsFromOAPI(s *spec.Schema, m attributeMode) []validator.List {
+	if (s.MinItems == nil && s.MaxItems == nil) || m == computedAttribute {
+		return nil
+	}
+	return []validator.List{sizeValidator{min: s.MinItems, max: s.MaxItems}}
+}
+
+// setValidatorsFromOAPI returns validators enforcing the constraints s places
+// on arrays converted to sets.
+func setValidatorsFromOAPI(s *spec.Schema, m attributeMode) []validator.Set {
+	if (s.MinItems == nil && s.MaxItems == nil) || m == computedAttribute {
+		return nil
+	}
+	return []validator.Set{sizeValidator{min: s.MinItems, max: s.MaxItems}}
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestStringValidatorsFromOAPIEnum(t *testing.T) {
+	s := spec.StringProperty().WithEnum("Always", "IfNotPresent", "Never")
+	vs := stringValidatorsFromOAPI(s, optionalAttribute)
+	if len(vs) != 1 {
+		t.Fatalf("expected a single validator, got %d", len(vs))
+	}
+	for value, valid := range map[string]bool{"Always": true, "Sometimes": false} {
+		resp := &validator.StringResponse{}
+		vs[0].ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("spec").AtName("pull_policy"),
+			ConfigValue: types.StringValue(value),
+		}, resp)
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("%q: unexpected diagnostics %v", value, resp.Diagnostics)
+		}
+	}
+	if vs := stringValidatorsFromOAPI(s, computedAttribute); len(vs) != 0 {
+		t.Errorf("expected no validators for a computed attribute, got %d", len(vs))
+	}
+}
+
+func TestInt64ValidatorsFromOAPIEnum(t *testing.T) {
+	s := spec.Int64Property().WithEnum(float64(80), int64(443))
+	vs := int64ValidatorsFromOAPI(s, requiredAttribute)
+	if len(vs) != 1 {
+		t.Fatalf("expected a single validator, got %d", len(vs))
+	}
+	for value, valid := range map[int64]bool{443: true, 8080: false} {
+		resp := &validator.Int64Response{}
+		vs[0].ValidateInt64(context.Background(), validator.Int64Request{
+			Path:        path.Root("spec").AtName("port"),
+			ConfigValue: types.Int64Value(value),
+		}, resp)
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("%d: unexpected diagnostics %v", value, resp.Diagnostics)
+		}
+	}
+}
+
+func TestStringValidatorsFromOAPIPatternAndLength(t *testing.T) {
+	s := spec.StringProperty()
+	s.Pattern = `^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	s.MaxLength = ptr(int64(10))
+	vs := stringValidatorsFromOAPI(s, optionalAttribute)
+	if len(vs) != 2 {
+		t.Fatalf("expected two validators, got %d", len(vs))
+	}
+	cases := map[string]bool{
+		"web":              true,
+		"Web":              false,
+		"web-frontend-api": false,
+	}
+	for value, valid := range cases {
+		var diags int
+		for _, v := range vs {
+			resp := &validator.StringResponse{}
+			v.ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("spec").AtName("host"),
+				ConfigValue: types.StringValue(value),
+			}, resp)
+			diags += resp.Diagnostics.ErrorsCount()
+		}
+		if (diags == 0) != valid {
+			t.Errorf("%q: expected valid=%t, got %d errors", value, valid, diags)
+		}
+	}
+
+	s.Pattern = `^(?!kube-).*$`
+	if vs := stringValidatorsFromOAPI(s, optionalAttribute); len(vs) != 1 {
+		t.Errorf("expected an RE2-incompatible pattern to be skipped, got %d validators", len(vs))
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNumberRangeValidator(t *testing.T) {
+	s := spec.Int64Property()
+	s.Minimum = ptr(float64(0))
+	s.Maximum = ptr(float64(100))
+	s.ExclusiveMaximum = true
+	s.MultipleOf = ptr(float64(5))
+	v := newNumberRangeValidator(s)
+	if v == nil {
+		t.Fatal("expected a validator")
+	}
+	cases := map[float64]bool{0: true, 95: true, 100: false, -5: false, 42: false}
+	for n, valid := range cases {
+		if v.valid(n) != valid {
+			t.Errorf("%v: expected valid=%t", n, valid)
+		}
+	}
+	if newNumberRangeValidator(spec.Int64Property()) != nil {
+		t.Error("expected no validator without numeric constraints")
+	}
+}
+
+func TestNumberRangeValidatorDecimalMultipleOf(t *testing.T) {
+	s := spec.Float64Property()
+	s.MultipleOf = ptr(0.1)
+	v := newNumberRangeValidator(s)
+	cases := map[float64]bool{0.3: true, 0.7: true, 1.2: true, 0.25: false}
+	for n, valid := range cases {
+		if v.valid(n) != valid {
+			t.Errorf("%v: expected valid=%t", n, valid)
+		}
+	}
+
+	resp := &validator.Float32Response{}
+	v.ValidateFloat32(context.Background(), validator.Float32Request{Path: path.Root("ratio"), ConfigValue: types.Float32Value(0.3)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("expected 0.3 to be a multiple of 0.1, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,328 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	defaultWaitTimeout  = 10 * time.Minute
+	defaultPollInterval = 2 * time.Second
+)
+
+// errWaitTimedOut is returned when objects don't satisfy wait criteria in
+// time.
+var errWaitTimedOut = errors.New("timed out")
+
+// WaitModel describes the wait attribute of generated resources.
+type WaitModel struct {
+	Conditions types.Map    `tfsdk:"conditions"`
+	Fields     types.Map    `tfsdk:"fields"`
+	Timeout    types.String `tfsdk:"timeout"`
+	Interval   types.String `tfsdk:"interval"`
+}
+
+func waitAttribute() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Block create and update operations until the object satisfies all of the given criteria.",
+		Optional:            true,
+		Attributes: map[string]schema.Attribute{
+			"conditions": schema.MapAttribute{
+				MarkdownDescription: "Status conditions to wait for, mapping the condition type to its expected status, e.g. `{ Ready = \"True\" }`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"fields": schema.MapAttribute{
+				MarkdownDescription: "JSONPath expressions mapped to their expected values, e.g. `{ \"{.status.phase}\" = \"Running\" }`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait, as a duration string such as `5m`. Defaults to `10m`.",
+				Optional:            true,
+				Validators:          []validator.String{durationValidator{}},
+			},
+			"interval": schema.StringAttribute{
+				MarkdownDescription: "How often to check objects which can't be watched, as a duration string such as `10s`. Defaults to `2s`.",
+				Optional:            true,
+				Validators:          []validator.String{durationValidator{}},
+			},
+		},
+	}
+}
+
+// waitForAttribute returns the wait_for attribute of data sources, which takes
+// the same criteria as the wait attribute of resources.
+func waitForAttribute(description string) dschema.SingleNestedAttribute {
+	a, _ := configAttribute(waitAttribute())
+	wa, ok := a.(dschema.SingleNestedAttribute)
+	if !ok {
+		// configAttribute keeps nested attributes nested, so this only
+		// happens if the wait attribute stops being one.
+		wa = dschema.SingleNestedAttribute{Optional: true}
+	}
+	wa.MarkdownDescription = description
+	return wa
+}
+
+// waitCriteriaFrom returns the wait criteria of the attribute at p of g, or
+// nil if it isn't set.
+func waitCriteriaFrom(ctx context.Context, g attributeGetter, p path.Path) (*waitCriteria, diag.Diagnostics) {
+	var wm *WaitModel
+	diags := g.GetAttribute(ctx, p, &wm)
+	if diags.HasError() || wm == nil {
+		return nil, diags
+	}
+	wc, err := newWaitCriteria(ctx, wm)
+	if err != nil {
+		diags.AddAttributeError(p, "Invalid Wait Configuration", err.Error())
+		return nil, diags
+	}
+	return wc, diags
+}
+
+// waitCriteria is the parsed form of a WaitModel.
+type waitCriteria struct {
+	conditions map[string]string
+	fields     map[string]string
+	paths      map[string]*jsonpath.JSONPath
+	timeout    time.Duration
+	interval   time.Duration
+}
+
+func newWaitCriteria(ctx context.Context, m *WaitModel) (*waitCriteria, error) {
+	wc := &waitCriteria{
+		conditions: make(map[string]string),
+		fields:     make(map[string]string),
+		paths:      make(map[string]*jsonpath.JSONPath),
+		timeout:    defaultWaitTimeout,
+		interval:   defaultPollInterval,
+	}
+	if d := m.Conditions.ElementsAs(ctx, &wc.conditions, false); d.HasError() {
+		return nil, fmt.Errorf("invalid conditions: %s", d.Errors()[0].Detail())
+	}
+	if d := m.Fields.ElementsAs(ctx, &wc.fields, false); d.HasError() {
+		return nil, fmt.Errorf("invalid fields: %s", d.Errors()[0].Detail())
+	}
+	for expr := range wc.fields {
+		jp, err := parseJSONPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		wc.paths[expr] = jp
+	}
+	if !m.Timeout.IsNull() {
+		d, err := time.ParseDuration(m.Timeout.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %w", err)
+		}
+		wc.timeout = d
+	}
+	if !m.Interval.IsNull() {
+		d, err := time.ParseDuration(m.Interval.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		wc.interval = d
+	}
+	return wc, nil
+}
+
+// parseJSONPath parses a kubectl-style JSONPath expression. The braces around
+// it may be left out, and missing fields evaluate to empty strings.
+func parseJSONPath(expr string) (*jsonpath.JSONPath, error) {
+	jp := jsonpath.New("jsonpath").AllowMissingKeys(true)
+	tmpl := expr
+	if !strings.HasPrefix(tmpl, "{") {
+		tmpl = "{" + tmpl + "}"
+	}
+	if err := jp.Parse(tmpl); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath expression %q: %w", expr, err)
+	}
+	return jp, nil
+}
+
+// satisfiedBy reports whether obj meets all of the criteria.
+func (wc *waitCriteria) satisfiedBy(obj *unstructured.Unstructured) (bool, error) {
+	if len(wc.conditions) > 0 {
+		conds, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		if err != nil {
+			return false, err
+		}
+		status := make(map[string]string, len(conds))
+		for _, c := range conds {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ct, _ := cm["type"].(string)
+			cs, _ := cm["status"].(string)
+			status[ct] = cs
+		}
+		for ct, cs := range wc.conditions {
+			if status[ct] != cs {
+				return false, nil
+			}
+		}
+	}
+	for expr, want := range wc.fields {
+		var buf bytes.Buffer
+		if err := wc.paths[expr].Execute(&buf, obj.Object); err != nil {
+			return false, err
+		}
+		if buf.String() != want {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// waitForObject watches the named object until it satisfies the criteria or
+// the timeout expires, and returns the last version of the object it saw.
+// Like informers, it lists the object again to resume watches which expire or
+// are closed by the API server. Objects which can't be watched are polled.
+func waitForObject(ctx context.Context, rc dynamic.ResourceInterface, name string, wc *waitCriteria) (*unstructured.Unstructured, error) {
+	wctx, cancel := context.WithTimeout(ctx, wc.timeout)
+	defer cancel()
+	// unwatchable is the error of the first request the credentials aren't
+	// allowed to make, or the API server doesn't serve.
+	var unwatchable error
+	denied := func(err error) {
+		if unwatchable == nil && (apierrors.IsForbidden(err) || apierrors.IsMethodNotSupported(err)) {
+			unwatchable = err
+			cancel()
+		}
+	}
+	selector := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = selector
+			l, err := rc.List(wctx, opts)
+			denied(err)
+			return l, err
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = selector
+			opts.AllowWatchBookmarks = true
+			w, err := rc.Watch(wctx, opts)
+			denied(err)
+			return w, err
+		},
+	}
+
+	var obj *unstructured.Unstructured
+	_, err := watchtools.UntilWithSync(wctx, lw, &unstructured.Unstructured{}, nil, func(e watch.Event) (bool, error) {
+		o, ok := e.Object.(*unstructured.Unstructured)
+		if !ok || o.GetName() != name || e.Type == watch.Deleted {
+			return false, nil
+		}
+		obj = o
+		return wc.satisfiedBy(obj)
+	})
+	switch {
+	case unwatchable != nil:
+		tflog.Debug(ctx, "Polling object which can't be watched", map[string]interface{}{"name": name, "error": unwatchable.Error()})
+		return pollForObject(ctx, rc, name, wc)
+	case err != nil && ctx.Err() != nil:
+		// Interruptions and operation timeouts aren't timeouts of the wait
+		// criteria, which the status data source reports as unhealthy.
+		return obj, contextError(ctx)
+	case wait.Interrupted(err):
+		return obj, fmt.Errorf("%w after %s", errWaitTimedOut, wc.timeout)
+	}
+	return obj, err
+}
+
+// pollForObject gets the named object every interval until it satisfies the
+// criteria or the timeout expires, and returns the last version of the object
+// it saw.
+func pollForObject(ctx context.Context, rc dynamic.ResourceInterface, name string, wc *waitCriteria) (*unstructured.Unstructured, error) {
+	var obj *unstructured.Unstructured
+	err := wait.PollUntilContextTimeout(ctx, wc.interval, wc.timeout, true, func(ctx context.Context) (bool, error) {
+		o, err := rc.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		obj = o
+		return wc.satisfiedBy(obj)
+	})
+	if err != nil && ctx.Err() != nil {
+		return obj, contextError(ctx)
+	}
+	if wait.Interrupted(err) {
+		return obj, fmt.Errorf("%w after %s", errWaitTimedOut, wc.timeout)
+	}
+	return obj, err
+}
+
+// waitForObjects polls the objects returned by list until at least one of
+// them satisfies the criteria or the timeout expires, and returns those which
+// do.
+func waitForObjects(ctx context.Context, list func(context.Context) ([]unstructured.Unstructured, error), wc *waitCriteria) ([]unstructured.Unstructured, error) {
+	var matched []unstructured.Unstructured
+	err := wait.PollUntilContextTimeout(ctx, wc.interval, wc.timeout, true, func(ctx context.Context) (bool, error) {
+		objs, err := list(ctx)
+		if err != nil {
+			return false, err
+		}
+		matched = matched[:0]
+		for _, obj := range objs {
+			ok, err := wc.satisfiedBy(&obj)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched = append(matched, obj)
+			}
+		}
+		return len(matched) > 0, nil
+	})
+	if err != nil && ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+	if wait.Interrupted(err) {
+		return nil, fmt.Errorf("no object satisfied the criteria within %s", wc.timeout)
+	}
+	return matched, err
+}
+
+// waitFor blocks until the named object satisfies the wait criteria held by
+// g, if the configuration sets any, and returns the last version of the
+// object it saw.
+func (r *CustomResource) waitFor(ctx context.Context, g attributeGetter, rc dynamic.ResourceInterface, name string) (*unstructured.Unstructured, diag.Diagnostics) {
+	wc, diags := waitCriteriaFrom(ctx, g, path.Root("wait"))
+	if wc == nil {
+		return nil, diags
+	}
+	obj, err := waitForObject(ctx, rc, name, wc)
+	if err != nil {
+		diags.AddError("Wait Failed", fmt.Sprintf("%s %q did not reach the expected state: %s", r.gvk.Kind, name, err))
+	}
+	return obj, diags
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestWaitCriteriaSatisfiedBy(t *testing.T) {
+	ctx := context.Background()
+	wc, err := newWaitCriteria(ctx, &WaitModel{
+		Conditions: types.MapValueMust(types.StringType, map[string]attr.Value{"Ready": types.StringValue("True")}),
+		Fields:     types.MapValueMust(types.StringType, map[string]attr.Value{".status.phase": types.StringValue("Running")}),
+		Timeout:    types.StringNull(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase": "Pending",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+	if ok, err := wc.satisfiedBy(obj); err != nil || ok {
+		t.Errorf("expected pending object not to satisfy criteria (err: %v)", err)
+	}
+
+	obj.Object["status"].(map[string]interface{})["phase"] = "Running"
+	if ok, err := wc.satisfiedBy(obj); err != nil || !ok {
+		t.Errorf("expected running object to satisfy criteria (err: %v)", err)
+	}
+}
+
+func TestWaitForObjects(t *testing.T) {
+	ctx := context.Background()
+	wc, err := newWaitCriteria(ctx, &WaitModel{
+		Conditions: types.MapNull(types.StringType),
+		Fields:     types.MapValueMust(types.StringType, map[string]attr.Value{".status.phase": types.StringValue("Ready")}),
+		Timeout:    types.StringValue("10s"),
+		Interval:   types.StringValue("10ms"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := func(name, phase string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name},
+			"status":   map[string]interface{}{"phase": phase},
+		}}
+	}
+	calls := 0
+	list := func(ctx context.Context) ([]unstructured.Unstructured, error) {
+		calls++
+		if calls == 1 {
+			return nil, nil
+		}
+		return []unstructured.Unstructured{obj("a", "Pending"), obj("b", "Ready")}, nil
+	}
+	objs, err := waitForObjects(ctx, list, wc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 || objs[0].GetName() != "b" {
+		t.Errorf("expected only the ready object, got %v", objs)
+	}
+}
+
+func TestWaitForObjectsInterrupted(t *testing.T) {
+	wc, err := newWaitCriteria(context.Background(), &WaitModel{
+		Conditions: types.MapNull(types.StringType),
+		Fields:     types.MapValueMust(types.StringType, map[string]attr.Value{".status.phase": types.StringValue("Ready")}),
+		Timeout:    types.StringValue("10s"),
+		Interval:   types.StringValue("10ms"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	list := func(ctx context.Context) ([]unstructured.Unstructured, error) {
+		cancel()
+		return nil, nil
+	}
+	_, err = waitForObjects(ctx, list, wc)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, errWaitTimedOut) {
+		t.Errorf("expected the interruption to be reported, got %v", err)
+	}
+}
+
+func TestWaitForObject(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	widget := func(name, phase string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"status":     map[string]interface{}{"phase": phase},
+		}}
+	}
+	wc, err := newWaitCriteria(context.Background(), &WaitModel{
+		Conditions: types.MapNull(types.StringType),
+		Fields:     types.MapValueMust(types.StringType, map[string]attr.Value{".status.phase": types.StringValue("Ready")}),
+		Timeout:    types.StringValue("10s"),
+		Interval:   types.StringValue("10ms"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, watchable := range []bool{true, false} {
+		client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "WidgetList"},
+			widget("test", "Pending"), widget("other", "Ready"))
+		watches := 0
+		client.PrependWatchReactor("widgets", func(action k8stesting.Action) (bool, watch.Interface, error) {
+			watches++
+			if !watchable {
+				return true, nil, apierrors.NewForbidden(gvr.GroupResource(), "", errors.New("watch denied"))
+			}
+			return false, nil, nil
+		})
+		rc := client.Resource(gvr).Namespace("default")
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_, _ = rc.Update(context.Background(), widget("test", "Ready"), metav1.UpdateOptions{})
+		}()
+		obj, err := waitForObject(context.Background(), rc, "test", wc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if obj.GetName() != "test" || obj.Object["status"].(map[string]interface{})["phase"] != "Ready" {
+			t.Errorf("expected the ready object, got %v", obj)
+		}
+		if watches == 0 {
+			t.Error("expected the object to be watched")
+		}
+	}
+
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "WidgetList"}, widget("test", "Pending"))
+	wc.timeout = 50 * time.Millisecond
+	obj, err := waitForObject(context.Background(), client.Resource(gvr).Namespace("default"), "test", wc)
+	if !errors.Is(err, errWaitTimedOut) || obj == nil {
+		t.Errorf("expected the wait to time out with the last object seen, got %v (err: %v)", obj, err)
+	}
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+)
+
+// warningsKey is the context key of the warnings collected for an operation.
+type warningsKey struct{}
+
+// apiWarnings collects the warnings sent by the API server in the Warning
+// headers of responses. They report problems which don't fail requests, such
+// as unknown fields when field validation is set to Warn. client-go only logs
+// them, so they are collected per operation to be reported as diagnostics.
+type apiWarnings struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// withWarnings returns ctx collecting the warnings sent in responses to the
+// requests made with it, and the warnings collected.
+func withWarnings(ctx context.Context) (context.Context, *apiWarnings) {
+	w := &apiWarnings{}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// withWarningCollection makes the clients created from cfg collect the
+// warnings of requests made with a context set up by withWarnings.
+func withWarningCollection(cfg *rest.Config) {
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &warningTransport{next: rt}
+	})
+}
+
+// warningTransport collects the warnings of the responses to next.
+type warningTransport struct {
+	next http.RoundTripper
+}
+
+func (t *warningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	w, ok := req.Context().Value(warningsKey{}).(*apiWarnings)
+	if !ok || resp == nil {
+		return resp, err
+	}
+	headers, _ := utilnet.ParseWarningHeaders(resp.Header.Values("Warning"))
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, h := range headers {
+		if !containsString(w.messages, h.Text) {
+			w.messages = append(w.messages, h.Text)
+		}
+	}
+	return resp, err
+}
+
+// diagnostics returns the warnings collected while writing obj.
+func (w *apiWarnings) diagnostics(obj *unstructured.Unstructured) diag.Diagnostics {
+	var diags diag.Diagnostics
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, m := range w.messages {
+		diags.AddWarning("API Server Warning", fmt.Sprintf("%s %q: %s", obj.GetKind(), obj.GetName(), m))
+	}
+	return diags
+}
@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// withWriteOnlyPaths makes the attributes of attrs, held by the attribute at
+// p, write-only if their paths are listed in paths. attrs is modified, along
+// with the attribute maps nested in it. Write-only attributes are read from
+// the configuration when applying and never stored in the plan or the state.
+// Their values are left out of the last applied configuration as well, which
+// is kept in the private state.
+func withWriteOnlyPaths(attrs map[string]schema.Attribute, p path.Path, paths map[string]bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(paths) == 0 {
+		return diags
+	}
+	for n, a := range attrs {
+		ap := p.AtName(n)
+		if paths[ap.String()] {
+			if a.IsComputed() && !a.IsOptional() {
+				diags.AddWarning("Invalid Write-Only Attribute",
+					fmt.Sprintf("Attribute %s is set by the API server and can't be write-only.", ap))
+				continue
+			}
+			attrs[n] = withWriteOnly(a)
+			continue
+		}
+		switch a := a.(type) {
+		case schema.SingleNestedAttribute:
+			diags.Append(withWriteOnlyPaths(a.Attributes, ap, paths)...)
+		case schema.ListNestedAttribute:
+			diags.Append(withWriteOnlyPaths(a.NestedObject.Attributes, ap, paths)...)
+		case schema.MapNestedAttribute:
+			diags.Append(withWriteOnlyPaths(a.NestedObject.Attributes, ap, paths)...)
+		case schema.SetNestedAttribute:
+			// Terraform doesn't support write-only attributes within sets.
+			for wp := range paths {
+				if strings.HasPrefix(wp, ap.String()+".") {
+					diags.AddWarning("Invalid Write-Only Attribute",
+						fmt.Sprintf("Attribute %s is part of the set %s and can't be write-only.", wp, ap))
+				}
+			}
+		}
+	}
+	return diags
+}
+
+// withWriteOnly returns a made write-only, along with its nested attributes.
+// Write-only attributes have no value to compute, so defaults are dropped.
+func withWriteOnly(a schema.Attribute) schema.Attribute {
+	switch a := a.(type) {
+	case schema.StringAttribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.BoolAttribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.Int32Attribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.Int64Attribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.Float32Attribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.Float64Attribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.DynamicAttribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.ListAttribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.MapAttribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		return a
+	case schema.SingleNestedAttribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		for n, na := range a.Attributes {
+			a.Attributes[n] = withWriteOnly(na)
+		}
+		return a
+	case schema.ListNestedAttribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		for n, na := range a.NestedObject.Attributes {
+			a.NestedObject.Attributes[n] = withWriteOnly(na)
+		}
+		return a
+	case schema.MapNestedAttribute:
+		a.WriteOnly, a.Computed, a.Default = true, false, nil
+		for n, na := range a.NestedObject.Attributes {
+			a.NestedObject.Attributes[n] = withWriteOnly(na)
+		}
+		return a
+	}
+	// Sets can't hold write-only values.
+	return a
+}
+
+// withWriteOnlyValues returns plan with the values of its write-only
+// attributes taken from config.
+func (r *CustomResource) withWriteOnlyValues(plan, config tftypes.Value) (tftypes.Value, error) {
+	if len(r.options.writeOnly) == 0 || config.IsNull() {
+		return plan, nil
+	}
+	return tftypes.Transform(plan, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !r.options.writeOnly[attributePathString(p)] {
+			return v, nil
+		}
+		cv, _, err := tftypes.WalkAttributePath(config, p)
+		if err != nil {
+			return v, nil
+		}
+		if cv, ok := cv.(tftypes.Value); ok {
+			return cv, nil
+		}
+		return v, nil
+	})
+}
+
+// appliedObject returns the object sent to the API server for obj, which was
+// built from plan: obj with the common metadata and the write-only values of
+// config.
+func (r *CustomResource) appliedObject(obj *unstructured.Unstructured, plan, config tftypes.Value) (*unstructured.Unstructured, error) {
+	if len(r.options.writeOnly) == 0 {
+		return r.withCommonMetadata(obj), nil
+	}
+	v, err := r.withWriteOnlyValues(plan, config)
+	if err != nil {
+		return nil, err
+	}
+	wo, err := r.objectFromValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return r.withCommonMetadata(wo), nil
+}
+
+// withoutField removes the field holding the attribute at the attribute names
+// path of v, a value described by s, from v and from the elements of the
+// lists and maps along the way.
+func withoutField(s *spec.Schema, v interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	if s != nil && len(s.AllOf) > 0 {
+		s = flattenAllOf(s)
+	}
+	switch v := v.(type) {
+	case []interface{}:
+		var es *spec.Schema
+		if s != nil && s.Items != nil {
+			es = s.Items.Schema
+		}
+		for _, e := range v {
+			withoutField(es, e, path)
+		}
+	case map[string]interface{}:
+		if s != nil && len(s.Properties) == 0 && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			for _, e := range v {
+				withoutField(s.AdditionalProperties.Schema, e, path)
+			}
+			return
+		}
+		field, fs := fieldOf(s, path[0])
+		if len(path) == 1 {
+			delete(v, field)
+			return
+		}
+		withoutField(fs, v[field], path[1:])
+	}
+}
+
+// attributePathString returns the attribute names along p, joined with dots,
+// leaving out list, set and map elements.
+func attributePathString(p *tftypes.AttributePath) string {
+	var names []string
+	for _, s := range p.Steps() {
+		if n, ok := s.(tftypes.AttributeName); ok {
+			names = append(names, string(n))
+		}
+	}
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestCustomResourceWriteOnlyPaths(t *testing.T) {
+	t.Setenv("CRD_WRITE_ONLY_ATTRIBUTES", "spec.password,status.phase")
+	s := testCRDSchema()
+	sp := s.Properties["spec"]
+	pw := spec.StringProperty()
+	pw.Default = "changeme"
+	sp.Properties["password"] = *pw
+	s.Properties["spec"] = sp
+
+	names := v1.CustomResourceDefinitionNames{Kind: "Widget", Singular: "widget", Plural: "widgets"}
+	opts, err := schemaOptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewCustomResource("v1", "example.com", names, v1.NamespaceScoped, s, opts)
+	rs := testCustomResourceSchema(t, r)
+	attrs := rs.Attributes["spec"].(schema.SingleNestedAttribute).Attributes
+	pa, ok := attrs["password"].(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("expected a string attribute, got %T", attrs["password"])
+	}
+	if !pa.IsWriteOnly() || pa.IsComputed() || pa.Default != nil {
+		t.Errorf("expected password to be write-only without a default, got %+v", pa)
+	}
+	if attrs["image"].IsWriteOnly() {
+		t.Error("expected image not to be write-only")
+	}
+	if rs.Attributes["status"].(schema.SingleNestedAttribute).Attributes["phase"].IsWriteOnly() {
+		t.Error("expected computed attributes not to be write-only")
+	}
+
+	st := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"password": tftypes.String,
+		"image":    tftypes.String,
+	}}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"spec": st}}
+	value := func(password interface{}) tftypes.Value {
+		return tftypes.NewValue(typ, map[string]tftypes.Value{
+			"spec": tftypes.NewValue(st, map[string]tftypes.Value{
+				"password": tftypes.NewValue(tftypes.String, password),
+				"image":    tftypes.NewValue(tftypes.String, "nginx"),
+			}),
+		})
+	}
+	v, err := r.(*CustomResource).withWriteOnlyValues(value(nil), value("s3cr3t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Equal(value("s3cr3t")) {
+		t.Errorf("expected the configured password, got %s", v)
+	}
+}
@@ -23,15 +23,19 @@ var (
 
 func main() {
 	var debug bool
+	var address string
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	// TODO: Update this string with the published name of your provider.
+	// Also update the tfplugindocs generate command to either remove the
+	// -provider-name flag or set its value to the updated provider name.
+	flag.StringVar(&address, "address", "registry.terraform.io/hashicorp/crd", "the provider address TF_REATTACH_PROVIDERS is keyed by in debug mode")
 	flag.Parse()
 
+	// The provider is served over protocol version 6, the default, as
+	// protocol version 5 has no nested attributes.
 	opts := providerserver.ServeOpts{
-		// TODO: Update this string with the published name of your provider.
-		// Also update the tfplugindocs generate command to either remove the
-		// -provider-name flag or set its value to the updated provider name.
-		Address: "registry.terraform.io/hashicorp/crd",
+		Address: address,
 		Debug:   debug,
 	}
 